@@ -23,6 +23,8 @@ import (
 	ocpmetadata "github.com/cloud-bulldozer/go-commons/v2/ocp-metadata"
 	uid "github.com/google/uuid"
 	"github.com/kube-burner/kube-burner-ocp/pkg/clusterhealth"
+	"github.com/kube-burner/kube-burner-ocp/pkg/echoserver"
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
 	ocpWorkloads "github.com/kube-burner/kube-burner-ocp/pkg/workloads"
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
 	"github.com/kube-burner/kube-burner/v2/pkg/util"
@@ -87,10 +89,14 @@ func openShiftCmd() *cobra.Command {
 			}
 			os.Exit(0)
 		}
+		// vm subcommands run from the workload hooks, once per VM, they log to the console only
+		if cmd.HasParent() && cmd.Parent().Name() == "vm" {
+			return
+		}
 		if enableFileLogging {
 			util.SetupFileLogging("ocp-" + workloadConfig.UUID)
 		}
-		if cmd.Name() == "cluster-health" {
+		if cmd.Name() == "cluster-health" || cmd.Name() == "echo-server" {
 			return
 		}
 		kubeClientProvider := config.NewKubeClientProvider("", "")
@@ -101,6 +107,7 @@ func openShiftCmd() *cobra.Command {
 		}
 		workloadDir := filepath.Join(rootDir, configDir)
 		wh = workloads.NewWorkloadHelper(workloadConfig, &ocpConfig, workloadDir, metricsProfilesDir, alertsDir, scriptsDir, kubeClientProvider)
+		ocpWorkloads.RegisterIPAMFunctions()
 
 		// Set common variables that all workloads can use
 		ocpWorkloads.AdditionalVars = map[string]any{
@@ -179,6 +186,8 @@ func openShiftCmd() *cobra.Command {
 		ocpWorkloads.NewVirtUDNDensity(&wh, "virt-udn-density"),
 		ocpWorkloads.NewVirtUDNDensity(&wh, "virt-cudn-density"),
 		clusterhealth.ClusterHealth(),
+		echoserver.EchoServer(),
+		vmops.VM(),
 		ocpWorkloads.CustomWorkload(&wh),
 		ocpWorkloads.NewVirtCapacityBenchmark(&wh),
 		ocpWorkloads.NewVirtParallel(&wh),
@@ -187,9 +196,12 @@ func openShiftCmd() *cobra.Command {
 		ocpWorkloads.NewVirtEphemeralRestart(&wh),
 		ocpWorkloads.NewDVClone(&wh),
 		ocpWorkloads.NewVirtMigration(&wh),
+		ocpWorkloads.NewVirtHotplug(&wh),
+		ocpWorkloads.NewVirtSnapshotRestore(&wh),
 		ocpWorkloads.NewKueueOperator(&wh, "kueue-operator-pods"),
 		ocpWorkloads.NewKueueOperator(&wh, "kueue-operator-jobs"),
 		ocpWorkloads.NewKueueOperator(&wh, "kueue-operator-jobs-shared"),
+		ocpWorkloads.NewKueueOperatorCohort(&wh),
 		ocpWorkloads.NewANPDensityPods(&wh, "anp-density-pods"),
 		ocpWorkloads.NewBuildFarm(&wh),
 		ocpWorkloads.NewEtcdDensity(&wh),
@@ -0,0 +1,98 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echoserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Handler replies to every request with the source IP address observed by the server, followed by a new line
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			sourceIP = r.RemoteAddr
+		}
+		log.Debugf("Request from %s to %s", sourceIP, r.Host)
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintln(w, sourceIP)
+	})
+}
+
+// EchoServer returns a command serving the source IP of each request, meant to be run on the external host of the egressip workload
+func EchoServer() *cobra.Command {
+	var address string
+	var port, portCount int
+	cmd := &cobra.Command{
+		Use:          "echo-server",
+		Short:        "Runs an HTTP server replying with the observed source IP of each request",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if portCount < 1 {
+				return fmt.Errorf("--port-count must be >= 1")
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			return Serve(ctx, address, port, portCount)
+		},
+	}
+	cmd.Flags().StringVar(&address, "address", "0.0.0.0", "Address to listen on")
+	cmd.Flags().IntVar(&port, "port", 9002, "First port to listen on")
+	cmd.Flags().IntVar(&portCount, "port-count", 60, "Number of consecutive ports to listen on, the egressip workload spreads iterations across 60 ports")
+	return cmd
+}
+
+// Serve listens on portCount consecutive ports starting at port until the context is cancelled
+func Serve(ctx context.Context, address string, port, portCount int) error {
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errCh := make(chan error, portCount)
+	for i := range portCount {
+		server := &http.Server{
+			Addr:              net.JoinHostPort(address, fmt.Sprint(port+i)),
+			Handler:           Handler(),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Infof("Echo server listening on %s", server.Addr)
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errCh <- fmt.Errorf("echo server on %s: %w", server.Addr, err)
+				cancel()
+			}
+		}()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			server.Shutdown(shutdownCtx)
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
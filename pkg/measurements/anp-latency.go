@@ -0,0 +1,383 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	anpLatencyMeasurementName      = "anpLatencyMeasurement"
+	anpLatencyQuantilesMeasurement = "anpLatencyQuantilesMeasurement"
+	// OVN-Kubernetes reports one condition per zone (node) once the policy is programmed
+	anpReadyConditionPrefix = "Ready-In-Zone-"
+	// Label of the generated tenant policies
+	anpTenantLabelKey = "kube-burner.io/anp-tenant"
+	// Label set on the connectivity probe pods
+	anpProbeLabel = "anp-connectivity-probe"
+)
+
+var (
+	supportedANPLatencyJobTypes = []config.JobType{config.CreationJob}
+	anpGVRForLatency            = schema.GroupVersionResource{
+		Group:    "policy.networking.k8s.io",
+		Version:  "v1alpha1",
+		Resource: "adminnetworkpolicies",
+	}
+)
+
+type anpMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Name       string    `json:"anpName"`
+	Metadata   any       `json:"metadata,omitempty"`
+	Priority   int64     `json:"priority"`
+	// Number of rules and peers, used to correlate latency with the policy size
+	IngressRules int `json:"ingressRules"`
+	EgressRules  int `json:"egressRules"`
+	Peers        int `json:"peers"`
+	Networks     int `json:"networks"`
+	ReadyZones   int `json:"readyZones"`
+	// Whether the policy was programmed in every zone before the timeout
+	Ready bool `json:"ready"`
+	// Time from creation to the first and the last zone reporting the policy as programmed, -1 when never reached
+	FirstZoneReadyLatency int `json:"firstZoneReadyLatency"`
+	ReadyLatency          int `json:"readyLatency"`
+	// Connectivity probe results of the namespaces selected by the policy
+	ProbedNamespaces   int `json:"probedNamespaces,omitempty"`
+	UnprobedNamespaces int `json:"unprobedNamespaces,omitempty"`
+	UnexpectedAllows   int `json:"unexpectedAllows,omitempty"`
+	UnexpectedDenies   int `json:"unexpectedDenies,omitempty"`
+	tenant             string
+	subject            labels.Selector
+	firstZoneReadyTime time.Time
+	readyTime          time.Time
+}
+
+type anpLatency struct {
+	measurements.BaseMeasurement
+	dynamicClient     dynamic.Interface
+	zones             int
+	connectivityProbe bool
+	// time to wait for all the policies to be programmed
+	readyTimeout time.Duration
+}
+
+type anpLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewANPLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return anpLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (amf anpLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &anpLatency{
+		BaseMeasurement: amf.NewBaseLatency(jobConfig, clientSet, restConfig, anpLatencyMeasurementName, anpLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (a *anpLatency) setInputVars() {
+	a.readyTimeout = 5 * time.Minute
+	for _, obj := range a.JobConfig.Objects {
+		if val, ok := obj.InputVars["anpReadyTimeout"]; ok {
+			timeout, err := time.ParseDuration(fmt.Sprint(val))
+			if err != nil {
+				log.Errorf("Failure parsing anpReadyTimeout: %v", err)
+				continue
+			}
+			a.readyTimeout = timeout
+		}
+		if val, ok := obj.InputVars["connectivityProbe"]; ok {
+			a.connectivityProbe = fmt.Sprint(val) == "true"
+		}
+	}
+}
+
+func (a *anpLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	a.LatencyQuantiles, a.NormLatencies = nil, nil
+	a.Metrics = sync.Map{}
+	if a.JobConfig.SkipIndexing {
+		return nil
+	}
+	a.setInputVars()
+	// With OVN-Kubernetes interconnect, every node is a zone
+	nodes, err := a.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %w", err)
+	}
+	a.zones = len(nodes.Items)
+	log.Infof("Measuring AdminNetworkPolicy latency across %d zones", a.zones)
+	return nil
+}
+
+func (a *anpLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// newANPMetric builds the metric of a policy from its spec and status conditions
+func newANPMetric(anp *unstructured.Unstructured) anpMetric {
+	m := anpMetric{
+		Name:                  anp.GetName(),
+		Timestamp:             anp.GetCreationTimestamp().UTC(),
+		tenant:                anp.GetLabels()[anpTenantLabelKey],
+		FirstZoneReadyLatency: -1,
+		ReadyLatency:          -1,
+	}
+	m.Priority, _, _ = unstructured.NestedInt64(anp.Object, "spec", "priority")
+	if subject, found, _ := unstructured.NestedMap(anp.Object, "spec", "subject", "namespaces"); found {
+		var selector metav1.LabelSelector
+		if runtime.DefaultUnstructuredConverter.FromUnstructured(subject, &selector) == nil {
+			m.subject, _ = metav1.LabelSelectorAsSelector(&selector)
+		}
+	}
+	for _, direction := range []string{"ingress", "egress"} {
+		rules, _, _ := unstructured.NestedSlice(anp.Object, "spec", direction)
+		if direction == "ingress" {
+			m.IngressRules = len(rules)
+		} else {
+			m.EgressRules = len(rules)
+		}
+		for _, r := range rules {
+			rule, ok := r.(map[string]any)
+			if !ok {
+				continue
+			}
+			peerField := "to"
+			if direction == "ingress" {
+				peerField = "from"
+			}
+			peers, _, _ := unstructured.NestedSlice(rule, peerField)
+			m.Peers += len(peers)
+			for _, p := range peers {
+				peer, ok := p.(map[string]any)
+				if !ok {
+					continue
+				}
+				networks, _, _ := unstructured.NestedStringSlice(peer, "networks")
+				m.Networks += len(networks)
+			}
+		}
+	}
+	conditions, _, _ := unstructured.NestedSlice(anp.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(condition, "type")
+		condStatus, _, _ := unstructured.NestedString(condition, "status")
+		if !strings.HasPrefix(condType, anpReadyConditionPrefix) || condStatus != "True" {
+			continue
+		}
+		ltt, _, _ := unstructured.NestedString(condition, "lastTransitionTime")
+		t, err := time.Parse(time.RFC3339, ltt)
+		if err != nil {
+			continue
+		}
+		m.ReadyZones++
+		if m.firstZoneReadyTime.IsZero() || t.Before(m.firstZoneReadyTime) {
+			m.firstZoneReadyTime = t
+		}
+		if t.After(m.readyTime) {
+			m.readyTime = t
+		}
+	}
+	return m
+}
+
+// waitForPolicies waits until every policy created by this run is programmed in all the zones
+func (a *anpLatency) waitForPolicies() {
+	labelSelector := fmt.Sprintf("kube-burner.io/uuid=%s", a.Uuid)
+	err := wait.PollUntilContextTimeout(context.TODO(), 5*time.Second, a.readyTimeout, true, func(ctx context.Context) (bool, error) {
+		anpList, err := a.dynamicClient.Resource(anpGVRForLatency).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			log.Errorf("Error listing AdminNetworkPolicies: %v", err)
+			return false, nil
+		}
+		pending := 0
+		for i := range anpList.Items {
+			m := newANPMetric(&anpList.Items[i])
+			if m.ReadyZones > 0 {
+				m.FirstZoneReadyLatency = int(m.firstZoneReadyTime.Sub(m.Timestamp).Milliseconds())
+			}
+			if m.ReadyZones >= a.zones {
+				m.ReadyLatency = int(m.readyTime.Sub(m.Timestamp).Milliseconds())
+			} else {
+				pending++
+			}
+			a.Metrics.Store(m.Name, m)
+		}
+		log.Debugf("%d/%d AdminNetworkPolicies programmed in all zones", len(anpList.Items)-pending, len(anpList.Items))
+		return pending == 0, nil
+	})
+	if err != nil {
+		log.Warnf("Timeout waiting for AdminNetworkPolicies to be programmed in all zones: %v", err)
+	}
+}
+
+// probeConnectivity reads the connectivity probe results and attaches them to the tenant policy selecting each probed namespace
+func (a *anpLatency) probeConnectivity() {
+	labelSelector := fmt.Sprintf("%s=true,kube-burner.io/uuid=%s", anpProbeLabel, a.Uuid)
+	pods, err := a.ClientSet.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		log.Errorf("Error listing connectivity probe pods: %v", err)
+		return
+	}
+	if len(pods.Items) == 0 {
+		return
+	}
+	var tenantPolicies []anpMetric
+	a.Metrics.Range(func(key, value any) bool {
+		if m := value.(anpMetric); m.tenant != "" && m.subject != nil {
+			tenantPolicies = append(tenantPolicies, m)
+		}
+		return true
+	})
+	for _, pod := range pods.Items {
+		ns, err := a.ClientSet.CoreV1().Namespaces().Get(context.TODO(), pod.Namespace, metav1.GetOptions{})
+		if err != nil {
+			log.Errorf("Error getting namespace %s: %v", pod.Namespace, err)
+			continue
+		}
+		i := slices.IndexFunc(tenantPolicies, func(m anpMetric) bool {
+			return m.subject.Matches(labels.Set(ns.Labels))
+		})
+		if i < 0 {
+			log.Debugf("Namespace %s isn't selected by any programmed tenant AdminNetworkPolicy", pod.Namespace)
+			continue
+		}
+		policy := tenantPolicies[i].Name
+		val, _ := a.Metrics.Load(policy)
+		m := val.(anpMetric)
+		var results, unexpectedAllows, unexpectedDenies int
+		logs, err := a.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(context.TODO())
+		if err != nil {
+			log.Errorf("Error reading logs from pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		} else {
+			results, unexpectedAllows, unexpectedDenies = evaluateANPProbeResults(string(logs))
+		}
+		// A probe without results didn't check anything, it must not count as a namespace enforcing the policy
+		if results == 0 {
+			log.Warnf("Connectivity probe %s/%s didn't report any result", pod.Namespace, pod.Name)
+			m.UnprobedNamespaces++
+			a.Metrics.Store(policy, m)
+			continue
+		}
+		if unexpectedAllows+unexpectedDenies > 0 {
+			log.Warnf("Connectivity probe %s/%s: %d unexpected allows, %d unexpected denies", pod.Namespace, pod.Name, unexpectedAllows, unexpectedDenies)
+		}
+		m.ProbedNamespaces++
+		m.UnexpectedAllows += unexpectedAllows
+		m.UnexpectedDenies += unexpectedDenies
+		a.Metrics.Store(policy, m)
+	}
+}
+
+// evaluateANPProbeResults parses the probe output, made of "<allow|deny> <target> <OK|FAIL>" lines, and
+// returns the number of targets probed and of those whose latest result doesn't match the expected action
+func evaluateANPProbeResults(logs string) (results, unexpectedAllows, unexpectedDenies int) {
+	latest := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || (fields[0] != "allow" && fields[0] != "deny") {
+			continue
+		}
+		latest[fields[0]+" "+fields[1]] = fields[2]
+	}
+	for target, result := range latest {
+		switch {
+		case strings.HasPrefix(target, "allow ") && result != "OK":
+			unexpectedDenies++
+		case strings.HasPrefix(target, "deny ") && result == "OK":
+			unexpectedAllows++
+		}
+	}
+	return len(latest), unexpectedAllows, unexpectedDenies
+}
+
+func (a *anpLatency) Stop() error {
+	if a.JobConfig.SkipIndexing {
+		return nil
+	}
+	a.waitForPolicies()
+	if a.connectivityProbe {
+		a.probeConnectivity()
+	}
+	return a.StopMeasurement(a.normalizeMetrics, a.getLatency)
+}
+
+func (a *anpLatency) normalizeMetrics() float64 {
+	a.Metrics.Range(func(key, value any) bool {
+		m := value.(anpMetric)
+		m.Ready = m.ReadyLatency >= 0
+		if !m.Ready {
+			log.Warnf("AdminNetworkPolicy %s only programmed in %d/%d zones", m.Name, m.ReadyZones, a.zones)
+		}
+		m.MetricName = anpLatencyMeasurementName
+		m.UUID = a.Uuid
+		m.JobName = a.JobConfig.Name
+		m.Metadata = a.Metadata
+		a.NormLatencies = append(a.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency excludes the policies never programmed in every zone from the quantiles
+func (a *anpLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(anpMetric)
+	if !m.Ready {
+		return map[string]float64{}
+	}
+	return map[string]float64{
+		"FirstZoneReadyLatency": float64(m.FirstZoneReadyLatency),
+		"ReadyLatency":          float64(m.ReadyLatency),
+	}
+}
+
+func (a *anpLatency) IsCompatible() bool {
+	return slices.Contains(supportedANPLatencyJobTypes, a.JobConfig.JobType)
+}
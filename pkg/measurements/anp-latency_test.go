@@ -0,0 +1,20 @@
+package measurements
+
+import "testing"
+
+func TestEvaluateANPProbeResults(t *testing.T) {
+	logs := `allow 10.128.0.1:9100 FAIL
+deny 10.129.0.1:9091 OK
+allow 10.128.0.1:9100 OK
+deny 10.129.0.1:9091 FAIL
+deny 10.129.0.2:9091 OK
+`
+	// Only the latest result of each target is evaluated
+	results, unexpectedAllows, unexpectedDenies := evaluateANPProbeResults(logs)
+	if results != 3 || unexpectedAllows != 1 || unexpectedDenies != 0 {
+		t.Errorf("unexpected results %d, unexpected allows %d, unexpected denies %d", results, unexpectedAllows, unexpectedDenies)
+	}
+	if results, _, _ := evaluateANPProbeResults("sh: nc: not found\n"); results != 0 {
+		t.Errorf("expected no results from a probe that didn't run, got %d", results)
+	}
+}
@@ -0,0 +1,467 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
+)
+
+const (
+	cloneIntegrityMeasurementName      = "cloneIntegrityMeasurement"
+	cloneIntegrityQuantilesMeasurement = "cloneIntegrityQuantilesMeasurement"
+	// Verify the payload file inside the VMs created by the job over SSH
+	cloneIntegrityVMMode = "vm"
+	// Write the payload into the disks of the DataVolumes created by the job
+	cloneIntegrityPVCWriteMode = "pvcWrite"
+	// Verify the payload in the disks of the DataVolumes created by the job from a pod mounting them
+	cloneIntegrityPVCVerifyMode = "pvcVerify"
+	cloneIntegrityDiskVolume    = "disk"
+	cloneIntegrityDiskImage     = "/disk/disk.img"
+	cloneIntegrityBlockDevice   = "/dev/disk"
+	cloneIntegrityPodLabel      = "kube-burner.io/clone-integrity"
+)
+
+var supportedCloneIntegrityJobTypes = []config.JobType{config.CreationJob}
+
+type cloneIntegrityMetric struct {
+	Timestamp        time.Time `json:"timestamp"`
+	MetricName       string    `json:"metricName"`
+	UUID             string    `json:"uuid"`
+	JobName          string    `json:"jobName,omitempty"`
+	Namespace        string    `json:"namespace"`
+	Name             string    `json:"name"`
+	Kind             string    `json:"kind"`
+	Metadata         any       `json:"metadata,omitempty"`
+	Passed           bool      `json:"passed"`
+	ExpectedChecksum string    `json:"expectedChecksum"`
+	Checksum         string    `json:"checksum,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	Attempts         int       `json:"attempts"`
+	// Time from the start of the verification to the checksum of the payload
+	VerificationLatency int `json:"verificationLatency"`
+}
+
+// cloneIntegrityConfig holds the input variables describing the payload and how to reach it
+type cloneIntegrityConfig struct {
+	mode         string
+	token        string
+	sizeMiB      int
+	offsetMiB    int
+	path         string
+	image        string
+	privateKey   string
+	remoteUser   string
+	useVirtctl   bool
+	timeout      time.Duration
+	pollInterval time.Duration
+	concurrency  int
+}
+
+type cloneIntegrity struct {
+	measurements.BaseMeasurement
+	dynamicClient dynamic.Interface
+	cfg           cloneIntegrityConfig
+	vmClient      *vmops.Client
+}
+
+type cloneIntegrityMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewCloneIntegrityMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return cloneIntegrityMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (cif cloneIntegrityMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &cloneIntegrity{
+		BaseMeasurement: cif.NewBaseLatency(jobConfig, clientSet, restConfig, cloneIntegrityMeasurementName, cloneIntegrityQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (c *cloneIntegrity) setInputVars() error {
+	c.cfg = cloneIntegrityConfig{
+		remoteUser:   "fedora",
+		timeout:      15 * time.Minute,
+		pollInterval: 5 * time.Second,
+		concurrency:  20,
+	}
+	for _, obj := range c.JobConfig.Objects {
+		for key, val := range obj.InputVars {
+			var err error
+			switch key {
+			case "integrityMode":
+				c.cfg.mode = fmt.Sprint(val)
+			case "integrityToken":
+				c.cfg.token = fmt.Sprint(val)
+			case "integritySizeMiB":
+				_, err = fmt.Sscan(fmt.Sprint(val), &c.cfg.sizeMiB)
+			case "integrityOffsetMiB":
+				_, err = fmt.Sscan(fmt.Sprint(val), &c.cfg.offsetMiB)
+			case "integrityPath":
+				c.cfg.path = fmt.Sprint(val)
+			case "integrityImage":
+				c.cfg.image = fmt.Sprint(val)
+			case "integrityTimeout":
+				c.cfg.timeout, err = time.ParseDuration(fmt.Sprint(val))
+			case "integrityConcurrency":
+				_, err = fmt.Sscan(fmt.Sprint(val), &c.cfg.concurrency)
+			case "privateKey":
+				c.cfg.privateKey = fmt.Sprint(val)
+			case "remoteUser":
+				c.cfg.remoteUser = fmt.Sprint(val)
+			case "useVirtctl":
+				c.cfg.useVirtctl = fmt.Sprint(val) == "true"
+			}
+			if err != nil {
+				return fmt.Errorf("failure parsing %s: %w", key, err)
+			}
+		}
+	}
+	if c.cfg.concurrency < 1 {
+		c.cfg.concurrency = 1
+	}
+	switch c.cfg.mode {
+	case "":
+	case cloneIntegrityVMMode:
+		if c.cfg.path == "" || c.cfg.privateKey == "" {
+			return fmt.Errorf("integrity mode %s requires the integrityPath and privateKey input variables", c.cfg.mode)
+		}
+	case cloneIntegrityPVCWriteMode, cloneIntegrityPVCVerifyMode:
+		if c.cfg.image == "" {
+			return fmt.Errorf("integrity mode %s requires the integrityImage input variable", c.cfg.mode)
+		}
+	default:
+		return fmt.Errorf("unsupported integrity mode %s", c.cfg.mode)
+	}
+	if c.cfg.mode != "" && (c.cfg.token == "" || c.cfg.sizeMiB <= 0) {
+		return fmt.Errorf("integrity mode %s requires the integrityToken and integritySizeMiB input variables", c.cfg.mode)
+	}
+	return nil
+}
+
+func (c *cloneIntegrity) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	c.LatencyQuantiles, c.NormLatencies = nil, nil
+	c.Metrics = sync.Map{}
+	if c.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := c.setInputVars(); err != nil {
+		return err
+	}
+	// Only the jobs creating the source disk or the clones provide the payload
+	if c.cfg.mode == "" {
+		log.Debugf("No integrityMode input variable found in job %s, skipping clone integrity checks", c.JobConfig.Name)
+		return nil
+	}
+	if c.cfg.mode == cloneIntegrityVMMode {
+		var err error
+		if c.vmClient, err = vmops.NewClient(c.RestConfig); err != nil {
+			return err
+		}
+		c.vmClient.UseVirtctl = c.cfg.useVirtctl
+	}
+	return nil
+}
+
+func (c *cloneIntegrity) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// Stop writes or verifies the payload, the objects of the job are ready by then
+func (c *cloneIntegrity) Stop() error {
+	if c.JobConfig.SkipIndexing || c.cfg.mode == "" {
+		return nil
+	}
+	targets, err := c.listTargets()
+	if err != nil {
+		return err
+	}
+	if c.cfg.mode == cloneIntegrityPVCWriteMode {
+		return c.writePayload(targets)
+	}
+	expected := integrityPayloadChecksum(c.cfg.token, int64(c.cfg.sizeMiB)<<20)
+	log.Infof("Verifying the integrity payload of %d clones of job %s", len(targets), c.JobConfig.Name)
+	c.forEach(targets, func(namespace, name string) {
+		m := c.verify(namespace, name, expected)
+		c.Metrics.Store(namespace+"/"+name, m)
+	})
+	var failed []string
+	c.Metrics.Range(func(key, value any) bool {
+		if m := value.(cloneIntegrityMetric); !m.Passed {
+			failed = append(failed, key.(string))
+		}
+		return true
+	})
+	if err := c.StopMeasurement(c.normalizeMetrics, c.getLatency); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("%d clones failed the integrity verification: %v", len(failed), failed)
+	}
+	log.Infof("Integrity payload verified in all the clones of job %s", c.JobConfig.Name)
+	return nil
+}
+
+// listTargets returns the namespace and name of the VMs or DataVolumes created by the job
+func (c *cloneIntegrity) listTargets() ([][2]string, error) {
+	gvr := dataVolumeGVR
+	if c.cfg.mode == cloneIntegrityVMMode {
+		gvr = vmGVR
+	}
+	selector := fmt.Sprintf("%s=%s,%s=%s", config.KubeBurnerLabelUUID, c.Uuid, config.KubeBurnerLabelJob, c.JobConfig.Name)
+	list, err := c.dynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the %s of job %s: %w", gvr.Resource, c.JobConfig.Name, err)
+	}
+	targets := make([][2]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		targets = append(targets, [2]string{item.GetNamespace(), item.GetName()})
+	}
+	return targets, nil
+}
+
+// forEach runs fn for every target, at most concurrency at a time
+func (c *cloneIntegrity) forEach(targets [][2]string, fn func(namespace, name string)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.cfg.concurrency)
+	for _, target := range targets {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(target[0], target[1])
+		})
+	}
+	wg.Wait()
+}
+
+// writePayload overwrites a region of the source disks with the payload
+func (c *cloneIntegrity) writePayload(targets [][2]string) error {
+	var mu sync.Mutex
+	var errs []string
+	c.forEach(targets, func(namespace, name string) {
+		log.Infof("Writing the integrity payload into PVC %s/%s", namespace, name)
+		if _, err := c.runDiskPod(namespace, name, func(device string) string {
+			return fmt.Sprintf("yes %s | head -c %d > /tmp/payload && dd if=/tmp/payload of=%s bs=1M seek=%d conv=notrunc && sync",
+				c.cfg.token, int64(c.cfg.sizeMiB)<<20, device, c.cfg.offsetMiB)
+		}); err != nil {
+			mu.Lock()
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", namespace, name, err))
+			mu.Unlock()
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to write the integrity payload: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// verify checksums the payload of a clone until it succeeds or the timeout expires, a mismatch isn't retried
+func (c *cloneIntegrity) verify(namespace, name, expected string) cloneIntegrityMetric {
+	m := cloneIntegrityMetric{
+		Timestamp:        time.Now().UTC(),
+		MetricName:       cloneIntegrityMeasurementName,
+		UUID:             c.Uuid,
+		JobName:          c.JobConfig.Name,
+		Namespace:        namespace,
+		Name:             name,
+		Kind:             "PersistentVolumeClaim",
+		Metadata:         c.Metadata,
+		ExpectedChecksum: expected,
+	}
+	if c.cfg.mode == cloneIntegrityVMMode {
+		m.Kind = "VirtualMachine"
+	}
+	deadline := m.Timestamp.Add(c.cfg.timeout)
+	for {
+		m.Attempts++
+		output, err := c.readChecksum(namespace, name)
+		if err == nil {
+			m.Checksum, err = parseChecksum(output)
+		}
+		if err == nil {
+			m.Error = ""
+			m.Passed = m.Checksum == expected
+			m.VerificationLatency = int(time.Since(m.Timestamp).Milliseconds())
+			if !m.Passed {
+				m.Error = "checksum mismatch"
+				log.Warnf("Integrity payload of %s %s/%s doesn't match: expected %s, got %s", m.Kind, namespace, name, expected, m.Checksum)
+			}
+			return m
+		}
+		m.Error = err.Error()
+		if !time.Now().Add(c.cfg.pollInterval).Before(deadline) {
+			log.Warnf("Unable to verify the integrity payload of %s %s/%s after %d attempts: %v", m.Kind, namespace, name, m.Attempts, err)
+			return m
+		}
+		time.Sleep(c.cfg.pollInterval)
+	}
+}
+
+func (c *cloneIntegrity) readChecksum(namespace, name string) (string, error) {
+	if c.cfg.mode == cloneIntegrityVMMode {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		return c.vmClient.RunCommand(ctx, namespace, name, c.cfg.remoteUser, c.cfg.privateKey, "sha256sum "+c.cfg.path)
+	}
+	return c.runDiskPod(namespace, name, func(device string) string {
+		return fmt.Sprintf("dd if=%s bs=1M skip=%d count=%d 2>/dev/null | sha256sum", device, c.cfg.offsetMiB, c.cfg.sizeMiB)
+	})
+}
+
+// runDiskPod runs the script returned by command in a pod mounting the PVC and returns its output.
+// command receives the path of the disk image or of the block device depending on the volume mode of the PVC
+func (c *cloneIntegrity) runDiskPod(namespace, pvcName string, command func(device string) string) (string, error) {
+	ctx := context.TODO()
+	pvc, err := c.ClientSet.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	// Disk images and block devices are only accessible to privileged users
+	privileged, rootUser := true, int64(0)
+	container := corev1.Container{
+		Name:  "integrity",
+		Image: c.cfg.image,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+			RunAsUser:  &rootUser,
+		},
+	}
+	device := cloneIntegrityDiskImage
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == corev1.PersistentVolumeBlock {
+		device = cloneIntegrityBlockDevice
+		container.VolumeDevices = []corev1.VolumeDevice{{Name: cloneIntegrityDiskVolume, DevicePath: device}}
+	} else {
+		container.VolumeMounts = []corev1.VolumeMount{{Name: cloneIntegrityDiskVolume, MountPath: "/disk"}}
+	}
+	container.Command = []string{"/bin/sh", "-c", command(device)}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "clone-integrity-",
+			Labels:       map[string]string{cloneIntegrityPodLabel: pvcName},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers:    []corev1.Container{container},
+			Volumes: []corev1.Volume{{
+				Name: cloneIntegrityDiskVolume,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+				},
+			}},
+		},
+	}
+	pod, err = c.ClientSet.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := c.ClientSet.CoreV1().Pods(namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			log.Warnf("Failed to delete pod %s/%s: %v", namespace, pod.Name, err)
+		}
+	}()
+	var phase corev1.PodPhase
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, c.cfg.timeout, true, func(ctx context.Context) (bool, error) {
+		p, err := c.ClientSet.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		phase = p.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("pod %s/%s didn't complete: %w", namespace, pod.Name, err)
+	}
+	logs, err := c.ClientSet.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	if phase == corev1.PodFailed {
+		return "", fmt.Errorf("pod %s/%s failed: %s", namespace, pod.Name, strings.TrimSpace(string(logs)))
+	}
+	return string(logs), nil
+}
+
+// parseChecksum extracts the digest printed by sha256sum, e.g. "<digest>  <file>"
+func parseChecksum(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("unexpected sha256sum output: %q", strings.TrimSpace(output))
+	}
+	return fields[0], nil
+}
+
+// integrityPayloadChecksum returns the SHA-256 of the payload generated in the guests and pods with "yes <token> | head -c <size>"
+func integrityPayloadChecksum(token string, size int64) string {
+	line := []byte(token + "\n")
+	chunk := bytes.Repeat(line, max(1, (1<<20)/len(line)))
+	h := sha256.New()
+	for size > 0 {
+		n := min(size, int64(len(chunk)))
+		h.Write(chunk[:n])
+		size -= n
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *cloneIntegrity) normalizeMetrics() float64 {
+	c.Metrics.Range(func(key, value any) bool {
+		c.NormLatencies = append(c.NormLatencies, value.(cloneIntegrityMetric))
+		return true
+	})
+	return 0
+}
+
+// getLatency excludes the clones that didn't pass from the quantiles
+func (c *cloneIntegrity) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(cloneIntegrityMetric)
+	if !m.Passed {
+		return map[string]float64{}
+	}
+	return map[string]float64{
+		"VerificationLatency": float64(m.VerificationLatency),
+	}
+}
+
+func (c *cloneIntegrity) IsCompatible() bool {
+	return slices.Contains(supportedCloneIntegrityJobTypes, c.JobConfig.JobType)
+}
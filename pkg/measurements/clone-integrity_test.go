@@ -0,0 +1,43 @@
+package measurements
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestIntegrityPayloadChecksum(t *testing.T) {
+	token := "6f1c2b0e-6a39-4d5e-9a3e-0c6b8c1e2f4d"
+	for _, size := range []int64{1, 37, 38, 1 << 20, 3<<20 + 5} {
+		// Same payload as "yes <token> | head -c <size>"
+		payload := strings.Repeat(token+"\n", int(size)/(len(token)+1)+1)[:size]
+		sum := sha256.Sum256([]byte(payload))
+		if got, expected := integrityPayloadChecksum(token, size), hex.EncodeToString(sum[:]); got != expected {
+			t.Errorf("size %d: expected checksum %s, got %s", size, expected, got)
+		}
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	digest := strings.Repeat("ab", sha256.Size)
+	tests := map[string]struct {
+		output   string
+		expected string
+		err      bool
+	}{
+		"file":        {output: digest + "  /var/lib/kube-burner-integrity\n", expected: digest},
+		"stdin":       {output: digest + "  -\n", expected: digest},
+		"empty":       {output: "", err: true},
+		"missingFile": {output: "sha256sum: /var/lib/kube-burner-integrity: No such file or directory\n", err: true},
+	}
+	for name, tc := range tests {
+		got, err := parseChecksum(tc.output)
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %q, got %q", name, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	apidiscoveryv2 "k8s.io/api/apidiscovery/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	crdLatencyMeasurementName      = "crdLatencyMeasurement"
+	crdLatencyQuantilesMeasurement = "crdLatencyQuantilesMeasurement"
+	crdDiscoveryMeasurementName    = "crdDiscoveryMeasurement"
+	crdNamesAcceptedCondition      = "NamesAccepted"
+	crdEstablishedCondition        = "Established"
+	// Accept header requesting the aggregated discovery document of the /apis endpoint
+	aggregatedDiscoveryAccept = "application/json;g=apidiscovery.k8s.io;v=v2;as=APIGroupDiscoveryList"
+)
+
+var (
+	supportedCRDLatencyJobTypes = []config.JobType{config.CreationJob}
+	crdGVR                      = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+)
+
+type crdLatencyMetric struct {
+	Timestamp   time.Time `json:"timestamp"`
+	MetricName  string    `json:"metricName"`
+	UUID        string    `json:"uuid"`
+	JobName     string    `json:"jobName,omitempty"`
+	Name        string    `json:"crdName"`
+	Group       string    `json:"group"`
+	Kind        string    `json:"kind"`
+	Metadata    any       `json:"metadata,omitempty"`
+	Established bool      `json:"established"`
+	// Milliseconds from the CRD creation to each condition, -1 when never reached
+	NamesAcceptedLatency int `json:"namesAcceptedLatency"`
+	EstablishedLatency   int `json:"establishedLatency"`
+}
+
+// crdDiscoveryMetric is a sample of the aggregated discovery document taken while the CRDs are created
+type crdDiscoveryMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Metadata   any       `json:"metadata,omitempty"`
+	// CRDs of the job established when the sample was taken
+	EstablishedCRDs int `json:"establishedCRDs"`
+	// Milliseconds taken by the discovery call, and size in bytes, API groups and resources of the document
+	DiscoveryLatency int `json:"discoveryLatency"`
+	DocumentSize     int `json:"documentSize"`
+	APIGroups        int `json:"apiGroups"`
+	APIResources     int `json:"apiResources"`
+}
+
+type crdLatency struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	interval      time.Duration
+	samplerWg     sync.WaitGroup
+	samples       []crdDiscoveryMetric
+	startTime     time.Time
+}
+
+type crdLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewCRDLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return crdLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (cmf crdLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &crdLatency{
+		BaseMeasurement: cmf.NewBaseLatency(jobConfig, clientSet, restConfig, crdLatencyMeasurementName, crdLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (c *crdLatency) setInputVars() error {
+	c.interval = 10 * time.Second
+	for _, obj := range c.JobConfig.Objects {
+		if val, ok := obj.InputVars["discoveryInterval"]; ok {
+			var err error
+			if c.interval, err = time.ParseDuration(fmt.Sprint(val)); err != nil {
+				return fmt.Errorf("failure parsing discoveryInterval: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *crdLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	c.LatencyQuantiles, c.NormLatencies = nil, nil
+	c.Metrics = sync.Map{}
+	c.samples = nil
+	if c.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := c.setInputVars(); err != nil {
+		return err
+	}
+	c.stopCh = make(chan struct{})
+	c.startTime = time.Now().UTC().Truncate(time.Second)
+	labelSelector := fmt.Sprintf("%s=%s", config.KubeBurnerLabelUUID, c.Uuid)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamicClient, 0, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = labelSelector
+	})
+	factory.ForResource(crdGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.handleCRD,
+		UpdateFunc: func(oldObj, newObj any) {
+			c.handleCRD(newObj)
+		},
+	})
+	log.Infof("Starting CRD latency watcher for job %s", c.JobConfig.Name)
+	factory.Start(c.stopCh)
+	factory.WaitForCacheSync(c.stopCh)
+	if c.interval > 0 {
+		log.Infof("Sampling the aggregated discovery document every %v", c.interval)
+		c.samplerWg.Go(func() {
+			ticker := time.NewTicker(c.interval)
+			defer ticker.Stop()
+			for {
+				c.sampleDiscovery()
+				select {
+				case <-c.stopCh:
+					return
+				case <-ticker.C:
+				}
+			}
+		})
+	}
+	return nil
+}
+
+func (c *crdLatency) handleCRD(obj any) {
+	crd := obj.(*unstructured.Unstructured)
+	created := crd.GetCreationTimestamp().UTC()
+	// CRDs of previous jobs of the run share its uuid label, e.g. when populating them with custom resources
+	if created.Before(c.startTime) {
+		return
+	}
+	var m crdLatencyMetric
+	if val, ok := c.Metrics.Load(string(crd.GetUID())); ok {
+		m = val.(crdLatencyMetric)
+	} else {
+		m = crdLatencyMetric{
+			Timestamp:            created,
+			MetricName:           crdLatencyMeasurementName,
+			Name:                 crd.GetName(),
+			NamesAcceptedLatency: -1,
+			EstablishedLatency:   -1,
+		}
+		m.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+		m.Kind, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	}
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	setCRDConditions(&m, conditions, time.Now().UTC())
+	c.Metrics.Store(string(crd.GetUID()), m)
+}
+
+// setCRDConditions records the first time the names of the CRD were accepted and the CRD was established
+func setCRDConditions(m *crdLatencyMetric, conditions []any, observed time.Time) {
+	for _, cond := range conditions {
+		condition, ok := cond.(map[string]any)
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		transition, _ := condition["lastTransitionTime"].(string)
+		switch condition["type"] {
+		case crdNamesAcceptedCondition:
+			if m.NamesAcceptedLatency < 0 {
+				m.NamesAcceptedLatency = conditionLatency(m.Timestamp, transition, observed)
+			}
+		case crdEstablishedCondition:
+			if m.EstablishedLatency < 0 {
+				m.Established = true
+				m.EstablishedLatency = conditionLatency(m.Timestamp, transition, observed)
+			}
+		}
+	}
+}
+
+// sampleDiscovery times an aggregated discovery call and records the size of the document returned
+func (c *crdLatency) sampleDiscovery() {
+	var established int
+	c.Metrics.Range(func(key, value any) bool {
+		if m, ok := value.(crdLatencyMetric); ok && m.Established {
+			established++
+		}
+		return true
+	})
+	start := time.Now()
+	raw, err := c.ClientSet.Discovery().RESTClient().Get().AbsPath("/apis").SetHeader("Accept", aggregatedDiscoveryAccept).DoRaw(context.TODO())
+	elapsed := time.Since(start)
+	if err != nil {
+		log.Warnf("Aggregated discovery call failed: %v", err)
+		return
+	}
+	sample := crdDiscoveryMetric{
+		Timestamp:        start.UTC(),
+		MetricName:       crdDiscoveryMeasurementName,
+		EstablishedCRDs:  established,
+		DiscoveryLatency: int(elapsed.Milliseconds()),
+		DocumentSize:     len(raw),
+	}
+	if sample.APIGroups, sample.APIResources, err = countDiscoveryResources(raw); err != nil {
+		log.Warnf("Failed to parse the aggregated discovery document: %v", err)
+	}
+	c.samples = append(c.samples, sample)
+}
+
+// countDiscoveryResources returns the API groups and resources, across versions, of an aggregated discovery document
+func countDiscoveryResources(raw []byte) (int, int, error) {
+	var discovery apidiscoveryv2.APIGroupDiscoveryList
+	if err := json.Unmarshal(raw, &discovery); err != nil {
+		return 0, 0, err
+	}
+	if discovery.Kind != "APIGroupDiscoveryList" {
+		return 0, 0, fmt.Errorf("unexpected kind %q, aggregated discovery may not be supported by the API server", discovery.Kind)
+	}
+	var resources int
+	for _, group := range discovery.Items {
+		for _, version := range group.Versions {
+			resources += len(version.Resources)
+		}
+	}
+	return len(discovery.Items), resources, nil
+}
+
+func (c *crdLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (c *crdLatency) Stop() error {
+	if c.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(c.stopCh)
+	c.samplerWg.Wait()
+	var total, established int
+	c.Metrics.Range(func(key, value any) bool {
+		total++
+		if value.(crdLatencyMetric).Established {
+			established++
+		}
+		return true
+	})
+	if established < total {
+		log.Warnf("%d/%d CRDs of job %s weren't established when the job finished", total-established, total, c.JobConfig.Name)
+	}
+	// A last sample with all the CRDs of the job in place
+	if c.interval > 0 {
+		c.sampleDiscovery()
+	}
+	if len(c.samples) > 0 {
+		first, last := c.samples[0], c.samples[len(c.samples)-1]
+		log.Infof("Aggregated discovery document grew from %d to %d bytes and %d to %d resources, discovery call took %dms with %d CRDs established",
+			first.DocumentSize, last.DocumentSize, first.APIResources, last.APIResources, last.DiscoveryLatency, last.EstablishedCRDs)
+	}
+	for i, sample := range c.samples {
+		c.Metrics.Store(fmt.Sprintf("%s/%d", crdDiscoveryMeasurementName, i), sample)
+	}
+	return c.StopMeasurement(c.normalizeMetrics, c.getLatency)
+}
+
+func (c *crdLatency) normalizeMetrics() float64 {
+	c.Metrics.Range(func(key, value any) bool {
+		switch m := value.(type) {
+		case crdLatencyMetric:
+			m.UUID = c.Uuid
+			m.JobName = c.JobConfig.Name
+			m.Metadata = c.Metadata
+			c.NormLatencies = append(c.NormLatencies, m)
+		case crdDiscoveryMetric:
+			m.UUID = c.Uuid
+			m.JobName = c.JobConfig.Name
+			m.Metadata = c.Metadata
+			c.NormLatencies = append(c.NormLatencies, m)
+		}
+		return true
+	})
+	return 0
+}
+
+func (c *crdLatency) getLatency(normLatency any) map[string]float64 {
+	switch m := normLatency.(type) {
+	case crdLatencyMetric:
+		latencies := map[string]float64{}
+		for name, latency := range map[string]int{
+			"NamesAcceptedLatency": m.NamesAcceptedLatency,
+			"EstablishedLatency":   m.EstablishedLatency,
+		} {
+			if latency >= 0 {
+				latencies[name] = float64(latency)
+			}
+		}
+		return latencies
+	case crdDiscoveryMetric:
+		return map[string]float64{
+			"DiscoveryLatency": float64(m.DiscoveryLatency),
+		}
+	}
+	return map[string]float64{}
+}
+
+func (c *crdLatency) IsCompatible() bool {
+	return slices.Contains(supportedCRDLatencyJobTypes, c.JobConfig.JobType)
+}
@@ -0,0 +1,51 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetCRDConditions(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := crdLatencyMetric{Timestamp: created, NamesAcceptedLatency: -1, EstablishedLatency: -1}
+	setCRDConditions(&m, []any{
+		map[string]any{"type": "NamesAccepted", "status": "True", "lastTransitionTime": "2025-01-01T10:00:00Z"},
+		map[string]any{"type": "Established", "status": "False", "lastTransitionTime": "2025-01-01T10:00:00Z"},
+	}, created.Add(300*time.Millisecond))
+	if m.Established || m.NamesAcceptedLatency != 300 || m.EstablishedLatency != -1 {
+		t.Errorf("unexpected conditions established %v names accepted %dms established %dms", m.Established, m.NamesAcceptedLatency, m.EstablishedLatency)
+	}
+	// Conditions observed late use the transition time, and the first one reached is kept
+	setCRDConditions(&m, []any{
+		map[string]any{"type": "NamesAccepted", "status": "True", "lastTransitionTime": "2025-01-01T10:00:04Z"},
+		map[string]any{"type": "Established", "status": "True", "lastTransitionTime": "2025-01-01T10:00:02Z"},
+	}, created.Add(5*time.Second))
+	if !m.Established || m.NamesAcceptedLatency != 300 || m.EstablishedLatency != 2000 {
+		t.Errorf("unexpected conditions established %v names accepted %dms established %dms", m.Established, m.NamesAcceptedLatency, m.EstablishedLatency)
+	}
+}
+
+func TestCountDiscoveryResources(t *testing.T) {
+	document := `{
+  "kind": "APIGroupDiscoveryList",
+  "apiVersion": "apidiscovery.k8s.io/v2",
+  "items": [
+    {"metadata": {"name": "apps"}, "versions": [{"version": "v1", "resources": [{"resource": "deployments"}, {"resource": "statefulsets"}]}]},
+    {"metadata": {"name": "cloudbulldozer.example.com"}, "versions": [
+      {"version": "v1", "resources": [{"resource": "kubeburners1"}, {"resource": "kubeburners2"}]},
+      {"version": "v1beta1", "resources": [{"resource": "kubeburners1"}]}
+    ]}
+  ]
+}`
+	groups, resources, err := countDiscoveryResources([]byte(document))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if groups != 2 || resources != 5 {
+		t.Errorf("expected 2 groups and 5 resources, got %d and %d", groups, resources)
+	}
+	// API servers without aggregated discovery return the APIGroupList
+	if _, _, err := countDiscoveryResources([]byte(`{"kind": "APIGroupList", "groups": [{"name": "apps"}]}`)); err == nil {
+		t.Errorf("expected an error with a non aggregated discovery document")
+	}
+}
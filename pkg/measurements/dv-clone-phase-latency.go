@@ -0,0 +1,377 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	dvClonePhaseLatencyMeasurementName      = "dvClonePhaseLatencyMeasurement"
+	dvClonePhaseLatencyQuantilesMeasurement = "dvClonePhaseLatencyQuantilesMeasurement"
+	dvCloneStrategySummaryName              = "dvCloneStrategySummary"
+	dvSucceededPhase                        = "Succeeded"
+	dvFailedPhase                           = "Failed"
+	// Set by CDI on the target PVC with the clone strategy it used
+	cdiCloneTypeAnnotation = "cdi.kubevirt.io/cloneType"
+	hostAssistedStrategy   = "host-assisted"
+	unknownCloneStrategy   = "unknown"
+)
+
+var (
+	supportedDVClonePhaseLatencyJobTypes = []config.JobType{config.CreationJob}
+	dataVolumeGVR                        = schema.GroupVersionResource{
+		Group:    "cdi.kubevirt.io",
+		Version:  "v1beta1",
+		Resource: "datavolumes",
+	}
+	storageProfileGVR = schema.GroupVersionResource{
+		Group:    "cdi.kubevirt.io",
+		Version:  "v1beta1",
+		Resource: "storageprofiles",
+	}
+	// CDI names the host-assisted strategy copy
+	cdiCloneStrategies = map[string]string{
+		"copy":      hostAssistedStrategy,
+		"snapshot":  "snapshot",
+		"csi-clone": "csi-clone",
+	}
+)
+
+type dvClonePhaseMetric struct {
+	Timestamp    time.Time `json:"timestamp"`
+	MetricName   string    `json:"metricName"`
+	UUID         string    `json:"uuid"`
+	JobName      string    `json:"jobName,omitempty"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Name         string    `json:"dvName,omitempty"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	Metadata     any       `json:"metadata,omitempty"`
+	Phase        string    `json:"phase,omitempty"`
+	// Strategy CDI used and the one the StorageProfile advertises
+	CloneStrategy         string `json:"cloneStrategy,omitempty"`
+	ExpectedCloneStrategy string `json:"expectedCloneStrategy,omitempty"`
+	HostAssistedFallback  bool   `json:"hostAssistedFallback"`
+	// Milliseconds spent in each phase, a phase entered several times accumulates its durations
+	PhaseDurations map[string]int `json:"phaseDurations,omitempty"`
+	// Time from the DataVolume creation to Succeeded, -1 when it didn't succeed
+	CloneLatency int `json:"cloneLatency"`
+	// Strategy summary fields
+	CloneStrategies map[string]int `json:"cloneStrategies,omitempty"`
+	Fallbacks       int            `json:"fallbacks,omitempty"`
+}
+
+type dvPhaseTransition struct {
+	phase     string
+	timestamp time.Time
+}
+
+type dvCloneState struct {
+	namespace   string
+	name        string
+	created     time.Time
+	transitions []dvPhaseTransition
+	// Set once the DataVolume reached a final phase, its clone strategy is read when the measurement stops
+	done bool
+}
+
+type dvClonePhaseLatency struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	startTime     time.Time
+	mu            sync.Mutex
+	dataVolumes   map[string]*dvCloneState
+	// Target PVCs of the DataVolumes, annotated by CDI with the clone strategy it used
+	pvcLister corev1listers.PersistentVolumeClaimLister
+	// Clone strategy advertised by the StorageProfile of each storage class, only accessed when the measurement stops
+	profileStrategies map[string]string
+}
+
+type dvClonePhaseLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewDVClonePhaseLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return dvClonePhaseLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (dmf dvClonePhaseLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &dvClonePhaseLatency{
+		BaseMeasurement: dmf.NewBaseLatency(jobConfig, clientSet, restConfig, dvClonePhaseLatencyMeasurementName, dvClonePhaseLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+func (d *dvClonePhaseLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	d.LatencyQuantiles, d.NormLatencies = nil, nil
+	d.Metrics = sync.Map{}
+	d.dataVolumes = make(map[string]*dvCloneState)
+	d.profileStrategies = make(map[string]string)
+	if d.JobConfig.SkipIndexing {
+		return nil
+	}
+	d.startTime = time.Now().UTC().Truncate(time.Second)
+	d.stopCh = make(chan struct{})
+	pvcFactory := informers.NewSharedInformerFactoryWithOptions(d.ClientSet, 0, informers.WithNamespace(d.JobConfig.Namespace))
+	d.pvcLister = pvcFactory.Core().V1().PersistentVolumeClaims().Lister()
+	pvcFactory.Start(d.stopCh)
+	pvcFactory.WaitForCacheSync(d.stopCh)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(d.dynamicClient, 0, d.JobConfig.Namespace, nil)
+	dvInformer := factory.ForResource(dataVolumeGVR).Informer()
+	dvInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: d.handleDataVolume,
+		UpdateFunc: func(oldObj, newObj any) {
+			d.handleDataVolume(newObj)
+		},
+	})
+	log.Infof("Starting DataVolume clone phase latency watcher for job %s", d.JobConfig.Name)
+	factory.Start(d.stopCh)
+	factory.WaitForCacheSync(d.stopCh)
+	return nil
+}
+
+// isCloneDataVolume returns true for DataVolumes cloning a PVC or a snapshot, directly or through a DataSource
+func isCloneDataVolume(dv *unstructured.Unstructured) bool {
+	if _, found, _ := unstructured.NestedMap(dv.Object, "spec", "sourceRef"); found {
+		return true
+	}
+	source, _, _ := unstructured.NestedMap(dv.Object, "spec", "source")
+	_, pvc := source["pvc"]
+	_, snapshot := source["snapshot"]
+	return pvc || snapshot
+}
+
+// handleDataVolume records the phase changes of the clone DataVolumes created during the job
+func (d *dvClonePhaseLatency) handleDataVolume(obj any) {
+	dv := obj.(*unstructured.Unstructured)
+	created := dv.GetCreationTimestamp().UTC()
+	if created.Before(d.startTime) || !isCloneDataVolume(dv) {
+		return
+	}
+	phase, _, _ := unstructured.NestedString(dv.Object, "status", "phase")
+	if phase == "" {
+		return
+	}
+	observed := time.Now().UTC()
+	conditions, _, _ := unstructured.NestedSlice(dv.Object, "status", "conditions")
+	d.mu.Lock()
+	state, ok := d.dataVolumes[string(dv.GetUID())]
+	if !ok {
+		state = &dvCloneState{namespace: dv.GetNamespace(), name: dv.GetName(), created: created}
+		d.dataVolumes[string(dv.GetUID())] = state
+	}
+	if state.done {
+		d.mu.Unlock()
+		return
+	}
+	if n := len(state.transitions); n == 0 || state.transitions[n-1].phase != phase {
+		previous := state.created
+		if n > 0 {
+			previous = state.transitions[n-1].timestamp
+		}
+		state.transitions = append(state.transitions, dvPhaseTransition{phase: phase, timestamp: dvPhaseTransitionTime(conditions, previous, observed)})
+	}
+	state.done = phase == dvSucceededPhase || phase == dvFailedPhase
+	d.mu.Unlock()
+}
+
+// dvPhaseTransitionTime returns the lastTransitionTime of the most recently changed condition, which CDI updates along with the phase,
+// or the observation time when no condition changed since the previous transition
+func dvPhaseTransitionTime(conditions []any, previous, observed time.Time) time.Time {
+	var latest time.Time
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		ts, _ := condition["lastTransitionTime"].(string)
+		if parsed, err := time.Parse(time.RFC3339, ts); err == nil && parsed.After(latest) {
+			latest = parsed.UTC()
+		}
+	}
+	if latest.After(previous) && !latest.After(observed) {
+		return latest
+	}
+	return observed
+}
+
+// newMetric builds the metric of a completed DataVolume, reading the clone strategy from the cached target PVC
+func (d *dvClonePhaseLatency) newMetric(state *dvCloneState) dvClonePhaseMetric {
+	last := state.transitions[len(state.transitions)-1]
+	m := dvClonePhaseMetric{
+		Timestamp:      state.created,
+		MetricName:     dvClonePhaseLatencyMeasurementName,
+		Namespace:      state.namespace,
+		Name:           state.name,
+		Phase:          last.phase,
+		PhaseDurations: dvPhaseDurations(state.created, state.transitions),
+		CloneLatency:   -1,
+		CloneStrategy:  unknownCloneStrategy,
+	}
+	if last.phase == dvSucceededPhase {
+		m.CloneLatency = int(last.timestamp.Sub(state.created).Milliseconds())
+	}
+	pvc, err := d.pvcLister.PersistentVolumeClaims(state.namespace).Get(state.name)
+	if err != nil {
+		log.Warnf("Unable to get the clone strategy of DataVolume %s/%s: %v", state.namespace, state.name, err)
+		return m
+	}
+	if pvc.Spec.StorageClassName != nil {
+		m.StorageClass = *pvc.Spec.StorageClassName
+	}
+	if strategy, ok := cdiCloneStrategies[pvc.Annotations[cdiCloneTypeAnnotation]]; ok {
+		m.CloneStrategy = strategy
+	}
+	m.ExpectedCloneStrategy = d.getProfileStrategy(m.StorageClass)
+	m.HostAssistedFallback = m.CloneStrategy == hostAssistedStrategy && m.ExpectedCloneStrategy != "" && m.ExpectedCloneStrategy != hostAssistedStrategy
+	if m.HostAssistedFallback {
+		log.Warnf("DataVolume %s/%s fell back to a host-assisted clone, StorageProfile %s advertises %s", state.namespace, state.name, m.StorageClass, m.ExpectedCloneStrategy)
+	}
+	return m
+}
+
+// getProfileStrategy returns the clone strategy of the StorageProfile, empty when the profile doesn't set one or can't be read.
+// Each StorageProfile is read once, the result is cached per storage class
+func (d *dvClonePhaseLatency) getProfileStrategy(storageClass string) string {
+	if storageClass == "" {
+		return ""
+	}
+	if strategy, ok := d.profileStrategies[storageClass]; ok {
+		return strategy
+	}
+	var strategy string
+	profile, err := d.dynamicClient.Resource(storageProfileGVR).Get(context.TODO(), storageClass, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("Unable to get StorageProfile %s: %v", storageClass, err)
+	} else {
+		cdiStrategy, _, _ := unstructured.NestedString(profile.Object, "status", "cloneStrategy")
+		strategy = cdiCloneStrategies[cdiStrategy]
+	}
+	d.profileStrategies[storageClass] = strategy
+	return strategy
+}
+
+// dvPhaseDurations accumulates the time spent in each phase, the first phase starts with the DataVolume creation
+func dvPhaseDurations(created time.Time, transitions []dvPhaseTransition) map[string]int {
+	durations := make(map[string]int)
+	start := created
+	for i, transition := range transitions[:len(transitions)-1] {
+		if i > 0 {
+			start = transition.timestamp
+		}
+		durations[transition.phase] += int(transitions[i+1].timestamp.Sub(start).Milliseconds())
+	}
+	return durations
+}
+
+func (d *dvClonePhaseLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (d *dvClonePhaseLatency) Stop() error {
+	if d.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(d.stopCh)
+	summary := dvClonePhaseMetric{
+		Timestamp:       time.Now().UTC(),
+		MetricName:      dvCloneStrategySummaryName,
+		CloneStrategies: make(map[string]int),
+		CloneLatency:    -1,
+	}
+	d.mu.Lock()
+	for uid, state := range d.dataVolumes {
+		var m dvClonePhaseMetric
+		if state.done {
+			m = d.newMetric(state)
+		} else {
+			// Report the phases observed so far for DataVolumes not completed when the job finished
+			m = dvClonePhaseMetric{
+				Timestamp:      state.created,
+				MetricName:     dvClonePhaseLatencyMeasurementName,
+				Namespace:      state.namespace,
+				Name:           state.name,
+				Phase:          state.transitions[len(state.transitions)-1].phase,
+				PhaseDurations: dvPhaseDurations(state.created, slices.Concat(state.transitions, []dvPhaseTransition{{timestamp: summary.Timestamp}})),
+				CloneLatency:   -1,
+				CloneStrategy:  unknownCloneStrategy,
+			}
+			log.Warnf("DataVolume %s/%s didn't complete, last phase %s", m.Namespace, m.Name, m.Phase)
+		}
+		summary.CloneStrategies[m.CloneStrategy]++
+		if m.HostAssistedFallback {
+			summary.Fallbacks++
+		}
+		d.Metrics.Store(uid, m)
+	}
+	d.mu.Unlock()
+	if len(summary.CloneStrategies) > 0 {
+		log.Infof("DataVolume clone strategies: %v, host-assisted fallbacks: %d", summary.CloneStrategies, summary.Fallbacks)
+		d.Metrics.Store(dvCloneStrategySummaryName, summary)
+	}
+	return d.StopMeasurement(d.normalizeMetrics, d.getLatency)
+}
+
+func (d *dvClonePhaseLatency) normalizeMetrics() float64 {
+	d.Metrics.Range(func(key, value any) bool {
+		m := value.(dvClonePhaseMetric)
+		m.UUID = d.Uuid
+		m.JobName = d.JobConfig.Name
+		m.Metadata = d.Metadata
+		d.NormLatencies = append(d.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency only accounts for successful clones, the phases they went through depend on the clone strategy
+func (d *dvClonePhaseLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(dvClonePhaseMetric)
+	if m.MetricName != dvClonePhaseLatencyMeasurementName || m.Phase != dvSucceededPhase {
+		return map[string]float64{}
+	}
+	latencies := map[string]float64{"CloneLatency": float64(m.CloneLatency)}
+	for phase, duration := range m.PhaseDurations {
+		latencies[phase] = float64(duration)
+	}
+	return latencies
+}
+
+func (d *dvClonePhaseLatency) IsCompatible() bool {
+	return slices.Contains(supportedDVClonePhaseLatencyJobTypes, d.JobConfig.JobType)
+}
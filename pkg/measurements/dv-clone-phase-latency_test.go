@@ -0,0 +1,63 @@
+package measurements
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDVPhaseDurations(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	transitions := []dvPhaseTransition{
+		{phase: "Pending", timestamp: created.Add(time.Second)},
+		{phase: "CloneScheduled", timestamp: created.Add(3 * time.Second)},
+		{phase: "CloneInProgress", timestamp: created.Add(4 * time.Second)},
+		{phase: "CloneScheduled", timestamp: created.Add(10 * time.Second)},
+		{phase: "CloneInProgress", timestamp: created.Add(12 * time.Second)},
+		{phase: dvSucceededPhase, timestamp: created.Add(20 * time.Second)},
+	}
+	expected := map[string]int{
+		"Pending":         3000,
+		"CloneScheduled":  3000,
+		"CloneInProgress": 14000,
+	}
+	if got := dvPhaseDurations(created, transitions); !maps.Equal(got, expected) {
+		t.Errorf("expected durations %v, got %v", expected, got)
+	}
+}
+
+func TestIsCloneDataVolume(t *testing.T) {
+	tests := map[string]struct {
+		spec     map[string]any
+		expected bool
+	}{
+		"dataSource": {map[string]any{"sourceRef": map[string]any{"kind": "DataSource", "name": "image"}}, true},
+		"pvc":        {map[string]any{"source": map[string]any{"pvc": map[string]any{"name": "image"}}}, true},
+		"snapshot":   {map[string]any{"source": map[string]any{"snapshot": map[string]any{"name": "image"}}}, true},
+		"registry":   {map[string]any{"source": map[string]any{"registry": map[string]any{"url": "docker://image"}}}, false},
+	}
+	for name, tc := range tests {
+		dv := &unstructured.Unstructured{Object: map[string]any{"spec": tc.spec}}
+		if got := isCloneDataVolume(dv); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, got)
+		}
+	}
+}
+
+func TestDVPhaseTransitionTime(t *testing.T) {
+	previous := time.Date(2025, 1, 1, 10, 0, 2, 0, time.UTC)
+	observed := previous.Add(5 * time.Second)
+	conditions := []any{
+		map[string]any{"type": "Bound", "status": "True", "lastTransitionTime": "2025-01-01T10:00:01Z"},
+		map[string]any{"type": "Running", "status": "True", "lastTransitionTime": "2025-01-01T10:00:04Z"},
+	}
+	if got, expected := dvPhaseTransitionTime(conditions, previous, observed), previous.Add(2*time.Second); !got.Equal(expected) {
+		t.Errorf("expected the Running condition transition time %v, got %v", expected, got)
+	}
+	// No condition changed after the previous transition, the phase change is only known when observed
+	if got := dvPhaseTransitionTime(conditions[:1], previous, observed); !got.Equal(observed) {
+		t.Errorf("expected the observation time %v, got %v", observed, got)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	egressIPSourceLatencyMeasurementName      = "egressIPSourceLatencyMeasurement"
+	egressIPSourceLatencyQuantilesMeasurement = "egressIPSourceLatencyQuantilesMeasurement"
+	// Label set on the pods probing the external echo server
+	egressIPSourceCheckLabel = "egressip-source-check"
+	// Line logged by the probing pods when the echo server can't be reached
+	egressIPSourceCheckError = "ERROR"
+)
+
+var (
+	supportedEgressIPSourceLatencyJobTypes = []config.JobType{config.CreationJob}
+	egressIPGVR                            = schema.GroupVersionResource{
+		Group:    "k8s.ovn.org",
+		Version:  "v1",
+		Resource: "egressips",
+	}
+)
+
+type egressIPSourceMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	EgressIP   string    `json:"egressIP"`
+	Metadata   any       `json:"metadata,omitempty"`
+	EgressIPs  []string  `json:"egressIPs"`
+	// Source check pods running in the namespace and how many of them never observed one of the EgressIPs
+	Pods           int `json:"pods"`
+	UnverifiedPods int `json:"unverifiedPods"`
+	// Time from pod creation to the first response reporting one of the namespace EgressIPs, slowest pod of the namespace, -1 when never observed
+	FirstCorrectSourceLatency int `json:"firstCorrectSourceLatency"`
+	// Responses reporting an unexpected source IP after the first correct one
+	Mismatches int `json:"mismatches"`
+	// Responses reporting an unexpected source IP before the EgressIP was applied
+	PreReadyMismatches int `json:"preReadyMismatches"`
+	// Requests that didn't get any response from the echo server
+	Errors   int `json:"errors"`
+	Requests int `json:"requests"`
+}
+
+// namespaceEgressIP is the EgressIP object selecting a namespace
+type namespaceEgressIP struct {
+	name      string
+	addresses []string
+}
+
+// egressIPSourceSample is a single probe result logged by the source check pods
+type egressIPSourceSample struct {
+	timestamp time.Time
+	sourceIP  string
+}
+
+type egressIPSourceLatency struct {
+	measurements.BaseMeasurement
+	dynamicClient dynamic.Interface
+	// time given to the probing pods before collecting their results
+	checkDuration time.Duration
+}
+
+type egressIPSourceLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewEgressIPSourceLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return egressIPSourceLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (emf egressIPSourceLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &egressIPSourceLatency{
+		BaseMeasurement: emf.NewBaseLatency(jobConfig, clientSet, restConfig, egressIPSourceLatencyMeasurementName, egressIPSourceLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (e *egressIPSourceLatency) setInputVars() {
+	e.checkDuration = 30 * time.Second
+	for _, obj := range e.JobConfig.Objects {
+		if val, ok := obj.InputVars["sourceCheckDuration"]; ok {
+			duration, err := time.ParseDuration(fmt.Sprint(val))
+			if err != nil {
+				log.Errorf("Failure parsing sourceCheckDuration: %v", err)
+				continue
+			}
+			e.checkDuration = duration
+		}
+	}
+}
+
+func (e *egressIPSourceLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	e.LatencyQuantiles, e.NormLatencies = nil, nil
+	e.Metrics = sync.Map{}
+	if e.JobConfig.SkipIndexing {
+		return nil
+	}
+	e.setInputVars()
+	return nil
+}
+
+func (e *egressIPSourceLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// getNamespaceEgressIPs maps every namespace selected by an EgressIP to the EgressIP and its assigned addresses
+func (e *egressIPSourceLatency) getNamespaceEgressIPs() (map[string]namespaceEgressIP, error) {
+	eipList, err := e.dynamicClient.Resource(egressIPGVR).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	namespaceEIPs := make(map[string]namespaceEgressIP)
+	for _, eip := range eipList.Items {
+		namespace, found, _ := unstructured.NestedString(eip.Object, "spec", "namespaceSelector", "matchLabels", corev1.LabelMetadataName)
+		if !found {
+			log.Debugf("EgressIP %s doesn't select namespaces by name, skipping", eip.GetName())
+			continue
+		}
+		addresses, _, _ := unstructured.NestedStringSlice(eip.Object, "spec", "egressIPs")
+		nsEIP := namespaceEIPs[namespace]
+		if nsEIP.name == "" {
+			nsEIP.name = eip.GetName()
+		}
+		nsEIP.addresses = append(nsEIP.addresses, addresses...)
+		namespaceEIPs[namespace] = nsEIP
+	}
+	return namespaceEIPs, nil
+}
+
+func (e *egressIPSourceLatency) getPodSamples(pod corev1.Pod) ([]egressIPSourceSample, error) {
+	logs, err := e.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Timestamps: true}).DoRaw(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return parseEgressIPSourceSamples(string(logs)), nil
+}
+
+func (e *egressIPSourceLatency) Stop() error {
+	if e.JobConfig.SkipIndexing {
+		return nil
+	}
+	log.Infof("Waiting %v for EgressIP source checks to complete", e.checkDuration)
+	time.Sleep(e.checkDuration)
+	namespaceEIPs, err := e.getNamespaceEgressIPs()
+	if err != nil {
+		return fmt.Errorf("error listing EgressIPs: %w", err)
+	}
+	labelSelector := fmt.Sprintf("%s=true,kube-burner.io/uuid=%s", egressIPSourceCheckLabel, e.Uuid)
+	podList, err := e.ClientSet.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return fmt.Errorf("error listing EgressIP source check pods: %w", err)
+	}
+	namespaceMetrics := make(map[string]egressIPSourceMetric)
+	for _, pod := range podList.Items {
+		nsEIP, ok := namespaceEIPs[pod.Namespace]
+		if !ok {
+			log.Warnf("No EgressIP found for namespace %s, skipping pod %s", pod.Namespace, pod.Name)
+			continue
+		}
+		samples, err := e.getPodSamples(pod)
+		if err != nil {
+			log.Errorf("Error reading logs from pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		podMetric := evaluateEgressIPSourceSamples(samples, nsEIP.addresses, pod.CreationTimestamp.UTC())
+		if podMetric.Mismatches > 0 {
+			log.Warnf("Pod %s/%s reached the external server with a source IP different from %v %d times after the EgressIP was applied", pod.Namespace, pod.Name, nsEIP.addresses, podMetric.Mismatches)
+		}
+		m, ok := namespaceMetrics[pod.Namespace]
+		if !ok {
+			m = egressIPSourceMetric{
+				MetricName: egressIPSourceLatencyMeasurementName,
+				UUID:       e.Uuid,
+				JobName:    e.JobConfig.Name,
+				Metadata:   e.Metadata,
+				Namespace:  pod.Namespace,
+				EgressIP:   nsEIP.name,
+			}
+		}
+		namespaceMetrics[pod.Namespace] = mergeEgressIPSourceMetrics(m, podMetric)
+	}
+	for namespace, m := range namespaceMetrics {
+		e.Metrics.Store(namespace+"/"+m.EgressIP, m)
+	}
+	return e.StopMeasurement(e.normalizeMetrics, e.getLatency)
+}
+
+// parseEgressIPSourceSamples parses the timestamped log lines of a source check pod, each line holds the source IP reported by the echo server or ERROR
+func parseEgressIPSourceSamples(logs string) []egressIPSourceSample {
+	var samples []egressIPSourceSample
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		ts, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !found {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, egressIPSourceSample{timestamp: t, sourceIP: strings.TrimSpace(value)})
+	}
+	return samples
+}
+
+// evaluateEgressIPSourceSamples compares every sample against the expected EgressIPs
+func evaluateEgressIPSourceSamples(samples []egressIPSourceSample, eips []string, created time.Time) egressIPSourceMetric {
+	m := egressIPSourceMetric{
+		Timestamp:                 created,
+		EgressIPs:                 eips,
+		FirstCorrectSourceLatency: -1,
+	}
+	for _, s := range samples {
+		m.Requests++
+		switch {
+		case s.sourceIP == egressIPSourceCheckError || s.sourceIP == "":
+			m.Errors++
+		case slices.Contains(eips, s.sourceIP):
+			if m.FirstCorrectSourceLatency < 0 {
+				m.FirstCorrectSourceLatency = int(s.timestamp.Sub(created).Milliseconds())
+			}
+		case m.FirstCorrectSourceLatency < 0:
+			m.PreReadyMismatches++
+		default:
+			m.Mismatches++
+		}
+	}
+	return m
+}
+
+// mergeEgressIPSourceMetrics adds the results of a source check pod to the metric of its namespace, whose latency is the one of its slowest pod
+func mergeEgressIPSourceMetrics(m, podMetric egressIPSourceMetric) egressIPSourceMetric {
+	if m.Pods == 0 || podMetric.Timestamp.Before(m.Timestamp) {
+		m.Timestamp = podMetric.Timestamp
+	}
+	m.EgressIPs = podMetric.EgressIPs
+	switch {
+	case podMetric.FirstCorrectSourceLatency < 0:
+		m.UnverifiedPods++
+		m.FirstCorrectSourceLatency = -1
+	case m.UnverifiedPods == 0:
+		m.FirstCorrectSourceLatency = max(m.FirstCorrectSourceLatency, podMetric.FirstCorrectSourceLatency)
+	}
+	m.Pods++
+	m.Mismatches += podMetric.Mismatches
+	m.PreReadyMismatches += podMetric.PreReadyMismatches
+	m.Errors += podMetric.Errors
+	m.Requests += podMetric.Requests
+	return m
+}
+
+func (e *egressIPSourceLatency) normalizeMetrics() float64 {
+	e.Metrics.Range(func(key, value any) bool {
+		m := value.(egressIPSourceMetric)
+		if m.UnverifiedPods > 0 {
+			log.Warnf("%d/%d pods of namespace %s never reached the external server using EgressIP %s", m.UnverifiedPods, m.Pods, m.Namespace, m.EgressIP)
+		}
+		e.NormLatencies = append(e.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency excludes namespaces with pods that never observed their EgressIP from the quantiles
+func (e *egressIPSourceLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(egressIPSourceMetric)
+	if m.FirstCorrectSourceLatency < 0 {
+		return map[string]float64{}
+	}
+	return map[string]float64{
+		"FirstCorrectSourceLatency": float64(m.FirstCorrectSourceLatency),
+	}
+}
+
+func (e *egressIPSourceLatency) IsCompatible() bool {
+	return slices.Contains(supportedEgressIPSourceLatencyJobTypes, e.JobConfig.JobType)
+}
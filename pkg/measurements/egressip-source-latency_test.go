@@ -0,0 +1,100 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEgressIPSourceSamples(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		logs string
+		want []string
+	}{
+		{
+			name: "source IPs and errors",
+			logs: "2026-01-01T00:00:01.000000000Z 10.0.0.1\n2026-01-01T00:00:02.000000000Z ERROR\n2026-01-01T00:00:03.000000000Z 192.168.1.10\n",
+			want: []string{"10.0.0.1", egressIPSourceCheckError, "192.168.1.10"},
+		},
+		{
+			name: "lines without timestamp or value are skipped",
+			logs: "curl: (28) Connection timed out\n2026-01-01T00:00:01.000000000Z\n2026-01-01T00:00:02.000000000Z 192.168.1.10\n",
+			want: []string{"192.168.1.10"},
+		},
+		{
+			name: "empty logs",
+			logs: "",
+		},
+	} {
+		samples := parseEgressIPSourceSamples(tc.logs)
+		if len(samples) != len(tc.want) {
+			t.Errorf("%s: expected %d samples, got %d", tc.name, len(tc.want), len(samples))
+			continue
+		}
+		for i, s := range samples {
+			if s.sourceIP != tc.want[i] {
+				t.Errorf("%s: sample %d expected source IP %s, got %s", tc.name, i, tc.want[i], s.sourceIP)
+			}
+		}
+	}
+}
+
+func TestEvaluateEgressIPSourceSamples(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(offset time.Duration, sourceIP string) egressIPSourceSample {
+		return egressIPSourceSample{timestamp: created.Add(offset), sourceIP: sourceIP}
+	}
+	eips := []string{"192.168.1.10", "192.168.1.11"}
+	for _, tc := range []struct {
+		name    string
+		samples []egressIPSourceSample
+		want    egressIPSourceMetric
+	}{
+		{
+			name: "node IP before the EgressIP is applied",
+			samples: []egressIPSourceSample{
+				sample(time.Second, "10.0.0.1"),
+				sample(2*time.Second, egressIPSourceCheckError),
+				sample(3*time.Second, "192.168.1.11"),
+				sample(4*time.Second, "192.168.1.10"),
+			},
+			want: egressIPSourceMetric{FirstCorrectSourceLatency: 3000, PreReadyMismatches: 1, Errors: 1, Requests: 4},
+		},
+		{
+			name: "traffic leaking after the EgressIP is applied",
+			samples: []egressIPSourceSample{
+				sample(500*time.Millisecond, "192.168.1.10"),
+				sample(time.Second, "10.0.0.1"),
+				sample(2*time.Second, ""),
+			},
+			want: egressIPSourceMetric{FirstCorrectSourceLatency: 500, Mismatches: 1, Errors: 1, Requests: 3},
+		},
+		{
+			name:    "EgressIP never observed",
+			samples: []egressIPSourceSample{sample(time.Second, "10.0.0.1")},
+			want:    egressIPSourceMetric{FirstCorrectSourceLatency: -1, PreReadyMismatches: 1, Requests: 1},
+		},
+	} {
+		m := evaluateEgressIPSourceSamples(tc.samples, eips, created)
+		if m.FirstCorrectSourceLatency != tc.want.FirstCorrectSourceLatency || m.Mismatches != tc.want.Mismatches ||
+			m.PreReadyMismatches != tc.want.PreReadyMismatches || m.Errors != tc.want.Errors || m.Requests != tc.want.Requests {
+			t.Errorf("%s: expected %+v, got %+v", tc.name, tc.want, m)
+		}
+	}
+}
+
+func TestMergeEgressIPSourceMetrics(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var m egressIPSourceMetric
+	m = mergeEgressIPSourceMetrics(m, egressIPSourceMetric{Timestamp: created.Add(time.Second), FirstCorrectSourceLatency: 1200, Requests: 10, Mismatches: 1})
+	m = mergeEgressIPSourceMetrics(m, egressIPSourceMetric{Timestamp: created, FirstCorrectSourceLatency: 3400, Requests: 12, Errors: 2})
+	if m.Pods != 2 || m.FirstCorrectSourceLatency != 3400 || !m.Timestamp.Equal(created) || m.Requests != 22 || m.Mismatches != 1 || m.Errors != 2 {
+		t.Errorf("unexpected namespace metric %+v", m)
+	}
+	// A single pod that never observed the EgressIP leaves the namespace unverified
+	m = mergeEgressIPSourceMetrics(m, egressIPSourceMetric{Timestamp: created, FirstCorrectSourceLatency: -1, Requests: 5})
+	m = mergeEgressIPSourceMetrics(m, egressIPSourceMetric{Timestamp: created, FirstCorrectSourceLatency: 5000, Requests: 5})
+	if m.Pods != 4 || m.UnverifiedPods != 1 || m.FirstCorrectSourceLatency != -1 {
+		t.Errorf("expected an unverified namespace, got %+v", m)
+	}
+}
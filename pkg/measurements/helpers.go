@@ -0,0 +1,46 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"math"
+	"time"
+)
+
+// percentileDuration formats the percentile of the sorted latencies in milliseconds
+func percentileDuration(sorted []int, p float64) string {
+	if len(sorted) == 0 {
+		return "-"
+	}
+	return (time.Duration(percentile(sorted, p)) * time.Millisecond).String()
+}
+
+// percentile returns the nearest-rank percentile of the sorted values, 0 when empty
+func percentile(sorted []int, p float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := max(int(math.Ceil(p/100*float64(len(sorted))))-1, 0)
+	return sorted[rank]
+}
+
+// conditionLatency returns the milliseconds from the creation to the condition transition. Transition times
+// have second precision, the observation time is preferred when it falls within that second
+func conditionLatency(created time.Time, transition string, observed time.Time) int {
+	if parsed, err := time.Parse(time.RFC3339, transition); err == nil && observed.Sub(parsed) >= time.Second {
+		return int(parsed.Sub(created).Milliseconds())
+	}
+	return int(observed.Sub(created).Milliseconds())
+}
@@ -0,0 +1,17 @@
+package measurements
+
+import (
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for p, expected := range map[float64]int{50: 5, 99: 10, 100: 10, 0: 1} {
+		if got := percentile(sorted, p); got != expected {
+			t.Errorf("percentile %v: expected %d, got %d", p, expected, got)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("expected 0 for empty values, got %d", got)
+	}
+}
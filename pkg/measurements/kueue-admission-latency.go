@@ -0,0 +1,351 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	kueueAdmissionLatencyMeasurementName      = "kueueAdmissionLatencyMeasurement"
+	kueueAdmissionLatencyQuantilesMeasurement = "kueueAdmissionLatencyQuantilesMeasurement"
+	kueueAdmissionSummaryName                 = "kueueAdmissionSummary"
+	kueueQuotaReservedCondition               = "QuotaReserved"
+	kueueAdmittedCondition                    = "Admitted"
+	kueueFinishedCondition                    = "Finished"
+	kueueEvictedCondition                     = "Evicted"
+	kueuePreemptedCondition                   = "Preempted"
+)
+
+var (
+	supportedKueueAdmissionLatencyJobTypes = []config.JobType{config.CreationJob}
+	kueueWorkloadGVR                       = schema.GroupVersionResource{
+		Group:    "kueue.x-k8s.io",
+		Version:  "v1beta2",
+		Resource: "workloads",
+	}
+)
+
+type kueueAdmissionMetric struct {
+	Timestamp     time.Time `json:"timestamp"`
+	MetricName    string    `json:"metricName"`
+	UUID          string    `json:"uuid"`
+	JobName       string    `json:"jobName,omitempty"`
+	Namespace     string    `json:"namespace,omitempty"`
+	Name          string    `json:"workloadName,omitempty"`
+	OwnerKind     string    `json:"ownerKind,omitempty"`
+	OwnerName     string    `json:"ownerName,omitempty"`
+	LocalQueue    string    `json:"localQueue"`
+	ClusterQueue  string    `json:"clusterQueue"`
+	PriorityClass string    `json:"priorityClass,omitempty"`
+	Metadata      any       `json:"metadata,omitempty"`
+	Admitted      bool      `json:"admitted"`
+	Finished      bool      `json:"finished"`
+	// Milliseconds from the Workload creation to the first time each condition was reached, -1 when never reached
+	QuotaReservedLatency int `json:"quotaReservedLatency"`
+	AdmittedLatency      int `json:"admittedLatency"`
+	FinishedLatency      int `json:"finishedLatency"`
+	// Times the Workload was evicted, including preemptions, and the reason of the last eviction and preemption
+	Evictions        int    `json:"evictions"`
+	Preemptions      int    `json:"preemptions"`
+	EvictionReason   string `json:"evictionReason,omitempty"`
+	PreemptionReason string `json:"preemptionReason,omitempty"`
+	// ClusterQueue and LocalQueue summary fields
+	Workloads               int     `json:"workloads,omitempty"`
+	AdmittedWorkloads       int     `json:"admittedWorkloads,omitempty"`
+	FinishedWorkloads       int     `json:"finishedWorkloads,omitempty"`
+	EvictedWorkloads        int     `json:"evictedWorkloads,omitempty"`
+	PreemptedWorkloads      int     `json:"preemptedWorkloads,omitempty"`
+	P50QuotaReservedLatency int     `json:"p50QuotaReservedLatency,omitempty"`
+	P99QuotaReservedLatency int     `json:"p99QuotaReservedLatency,omitempty"`
+	P50AdmittedLatency      int     `json:"p50AdmittedLatency,omitempty"`
+	P99AdmittedLatency      int     `json:"p99AdmittedLatency,omitempty"`
+	AdmissionThroughput     float64 `json:"admissionThroughput,omitempty"`
+	// Transition times of the last eviction and preemption already accounted
+	lastEviction   string
+	lastPreemption string
+}
+
+type kueueAdmissionLatency struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	startTime     time.Time
+}
+
+type kueueAdmissionLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewKueueAdmissionLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return kueueAdmissionLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (kmf kueueAdmissionLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &kueueAdmissionLatency{
+		BaseMeasurement: kmf.NewBaseLatency(jobConfig, clientSet, restConfig, kueueAdmissionLatencyMeasurementName, kueueAdmissionLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+func (k *kueueAdmissionLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	k.LatencyQuantiles, k.NormLatencies = nil, nil
+	k.Metrics = sync.Map{}
+	if k.JobConfig.SkipIndexing {
+		return nil
+	}
+	k.startTime = time.Now().UTC().Truncate(time.Second)
+	k.stopCh = make(chan struct{})
+	namespace := k.JobConfig.Namespace
+	if k.JobConfig.NamespacedIterations {
+		namespace = metav1.NamespaceAll
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(k.dynamicClient, 0, namespace, nil)
+	factory.ForResource(kueueWorkloadGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: k.handleWorkload,
+		UpdateFunc: func(oldObj, newObj any) {
+			k.handleWorkload(newObj)
+		},
+	})
+	log.Infof("Starting Kueue admission latency watcher for job %s", k.JobConfig.Name)
+	factory.Start(k.stopCh)
+	factory.WaitForCacheSync(k.stopCh)
+	return nil
+}
+
+// handleWorkload records the conditions reached by the Workloads created by the job, Kueue creates them for the Jobs and pods queued
+func (k *kueueAdmissionLatency) handleWorkload(obj any) {
+	workload := obj.(*unstructured.Unstructured)
+	created := workload.GetCreationTimestamp().UTC()
+	if created.Before(k.startTime) {
+		return
+	}
+	var m kueueAdmissionMetric
+	if val, ok := k.Metrics.Load(string(workload.GetUID())); ok {
+		m = val.(kueueAdmissionMetric)
+	} else {
+		m = kueueAdmissionMetric{
+			Timestamp:            created,
+			MetricName:           kueueAdmissionLatencyMeasurementName,
+			Namespace:            workload.GetNamespace(),
+			Name:                 workload.GetName(),
+			QuotaReservedLatency: -1,
+			AdmittedLatency:      -1,
+			FinishedLatency:      -1,
+		}
+		if owners := workload.GetOwnerReferences(); len(owners) > 0 {
+			m.OwnerKind, m.OwnerName = owners[0].Kind, owners[0].Name
+		}
+		m.LocalQueue, _, _ = unstructured.NestedString(workload.Object, "spec", "queueName")
+		m.PriorityClass, _, _ = unstructured.NestedString(workload.Object, "spec", "priorityClassName")
+	}
+	// The admission is cleared when the Workload is evicted, keep the last ClusterQueue reserving quota
+	if clusterQueue, _, _ := unstructured.NestedString(workload.Object, "status", "admission", "clusterQueue"); clusterQueue != "" {
+		m.ClusterQueue = clusterQueue
+	}
+	conditions, _, _ := unstructured.NestedSlice(workload.Object, "status", "conditions")
+	setKueueWorkloadConditions(&m, conditions, time.Now().UTC())
+	k.Metrics.Store(string(workload.GetUID()), m)
+}
+
+// setKueueWorkloadConditions updates the milestones reached according to the Workload conditions observed at the given time
+func setKueueWorkloadConditions(m *kueueAdmissionMetric, conditions []any, observed time.Time) {
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		transition, _ := condition["lastTransitionTime"].(string)
+		reason, _ := condition["reason"].(string)
+		switch condition["type"] {
+		case kueueQuotaReservedCondition:
+			if m.QuotaReservedLatency < 0 {
+				m.QuotaReservedLatency = conditionLatency(m.Timestamp, transition, observed)
+			}
+		case kueueAdmittedCondition:
+			if m.AdmittedLatency < 0 {
+				m.Admitted = true
+				m.AdmittedLatency = conditionLatency(m.Timestamp, transition, observed)
+			}
+		case kueueFinishedCondition:
+			if m.FinishedLatency < 0 {
+				m.Finished = true
+				m.FinishedLatency = conditionLatency(m.Timestamp, transition, observed)
+			}
+		case kueueEvictedCondition:
+			if transition != m.lastEviction {
+				m.Evictions++
+				m.EvictionReason = reason
+				m.lastEviction = transition
+			}
+		case kueuePreemptedCondition:
+			if transition != m.lastPreemption {
+				m.Preemptions++
+				m.PreemptionReason = reason
+				m.lastPreemption = transition
+			}
+		}
+	}
+}
+
+func (k *kueueAdmissionLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (k *kueueAdmissionLatency) Stop() error {
+	if k.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(k.stopCh)
+	var workloads []kueueAdmissionMetric
+	var pending int
+	k.Metrics.Range(func(key, value any) bool {
+		m := value.(kueueAdmissionMetric)
+		workloads = append(workloads, m)
+		if !m.Admitted {
+			pending++
+		}
+		return true
+	})
+	if pending > 0 {
+		log.Warnf("%d Kueue Workloads of job %s weren't admitted when the job finished", pending, k.JobConfig.Name)
+	}
+	for _, summary := range kueueQueueSummaries(workloads) {
+		log.Infof("ClusterQueue %s LocalQueue %s/%s: %d workloads, %d admitted, %d preempted, admitted P99 %dms, %.2f admissions/s",
+			summary.ClusterQueue, summary.Namespace, summary.LocalQueue, summary.Workloads, summary.AdmittedWorkloads, summary.PreemptedWorkloads, summary.P99AdmittedLatency, summary.AdmissionThroughput)
+		k.Metrics.Store(kueueAdmissionSummaryName+"/"+summary.ClusterQueue+"/"+summary.Namespace+"/"+summary.LocalQueue, summary)
+	}
+	return k.StopMeasurement(k.normalizeMetrics, k.getLatency)
+}
+
+// kueueQueueSummaries returns a document per ClusterQueue and LocalQueue with the admission percentiles and throughput,
+// measured as the Workloads admitted per second from the first Workload creation to the last admission
+func kueueQueueSummaries(workloads []kueueAdmissionMetric) []kueueAdmissionMetric {
+	type group struct {
+		summary                 kueueAdmissionMetric
+		quotaReserved, admitted []int
+		firstCreated, lastAdmit time.Time
+	}
+	groups := make(map[string]*group)
+	var keys []string
+	for _, m := range workloads {
+		groupKey := m.ClusterQueue + "/" + m.Namespace + "/" + m.LocalQueue
+		g, ok := groups[groupKey]
+		if !ok {
+			g = &group{summary: kueueAdmissionMetric{
+				MetricName:   kueueAdmissionSummaryName,
+				Namespace:    m.Namespace,
+				LocalQueue:   m.LocalQueue,
+				ClusterQueue: m.ClusterQueue,
+			}}
+			groups[groupKey] = g
+			keys = append(keys, groupKey)
+		}
+		g.summary.Workloads++
+		if g.firstCreated.IsZero() || m.Timestamp.Before(g.firstCreated) {
+			g.firstCreated = m.Timestamp
+		}
+		if m.QuotaReservedLatency >= 0 {
+			g.quotaReserved = append(g.quotaReserved, m.QuotaReservedLatency)
+		}
+		if m.Admitted {
+			g.summary.AdmittedWorkloads++
+			g.admitted = append(g.admitted, m.AdmittedLatency)
+			if admission := m.Timestamp.Add(time.Duration(m.AdmittedLatency) * time.Millisecond); admission.After(g.lastAdmit) {
+				g.lastAdmit = admission
+			}
+		}
+		if m.Finished {
+			g.summary.FinishedWorkloads++
+		}
+		if m.Evictions > 0 {
+			g.summary.EvictedWorkloads++
+		}
+		if m.Preemptions > 0 {
+			g.summary.PreemptedWorkloads++
+		}
+	}
+	slices.Sort(keys)
+	summaries := make([]kueueAdmissionMetric, 0, len(keys))
+	for _, groupKey := range keys {
+		g := groups[groupKey]
+		slices.Sort(g.quotaReserved)
+		slices.Sort(g.admitted)
+		g.summary.Timestamp = time.Now().UTC()
+		g.summary.P50QuotaReservedLatency = percentile(g.quotaReserved, 50)
+		g.summary.P99QuotaReservedLatency = percentile(g.quotaReserved, 99)
+		g.summary.P50AdmittedLatency = percentile(g.admitted, 50)
+		g.summary.P99AdmittedLatency = percentile(g.admitted, 99)
+		if span := g.lastAdmit.Sub(g.firstCreated).Seconds(); span > 0 {
+			g.summary.AdmissionThroughput = float64(g.summary.AdmittedWorkloads) / span
+		}
+		summaries = append(summaries, g.summary)
+	}
+	return summaries
+}
+
+func (k *kueueAdmissionLatency) normalizeMetrics() float64 {
+	k.Metrics.Range(func(key, value any) bool {
+		m := value.(kueueAdmissionMetric)
+		m.UUID = k.Uuid
+		m.JobName = k.JobConfig.Name
+		m.Metadata = k.Metadata
+		k.NormLatencies = append(k.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency skips the summaries and the milestones not reached
+func (k *kueueAdmissionLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(kueueAdmissionMetric)
+	latencies := map[string]float64{}
+	if m.MetricName != kueueAdmissionLatencyMeasurementName {
+		return latencies
+	}
+	for name, latency := range map[string]int{
+		"QuotaReservedLatency": m.QuotaReservedLatency,
+		"AdmittedLatency":      m.AdmittedLatency,
+		"FinishedLatency":      m.FinishedLatency,
+	} {
+		if latency >= 0 {
+			latencies[name] = float64(latency)
+		}
+	}
+	return latencies
+}
+
+func (k *kueueAdmissionLatency) IsCompatible() bool {
+	return slices.Contains(supportedKueueAdmissionLatencyJobTypes, k.JobConfig.JobType)
+}
@@ -0,0 +1,64 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetKueueWorkloadConditions(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := kueueAdmissionMetric{Timestamp: created, QuotaReservedLatency: -1, AdmittedLatency: -1, FinishedLatency: -1}
+	setKueueWorkloadConditions(&m, []any{
+		map[string]any{"type": "QuotaReserved", "status": "True", "lastTransitionTime": "2025-01-01T10:00:02Z"},
+		map[string]any{"type": "Admitted", "status": "True", "lastTransitionTime": "2025-01-01T10:00:02Z"},
+	}, created.Add(2500*time.Millisecond))
+	if !m.Admitted || m.QuotaReservedLatency != 2500 || m.AdmittedLatency != 2500 {
+		t.Errorf("unexpected admission admitted %v quota reserved %dms admitted %dms", m.Admitted, m.QuotaReservedLatency, m.AdmittedLatency)
+	}
+	preempted := []any{
+		map[string]any{"type": "QuotaReserved", "status": "False", "lastTransitionTime": "2025-01-01T10:00:05Z"},
+		map[string]any{"type": "Evicted", "status": "True", "reason": "Preempted", "lastTransitionTime": "2025-01-01T10:00:05Z"},
+		map[string]any{"type": "Preempted", "status": "True", "reason": "InCohortReclamation", "lastTransitionTime": "2025-01-01T10:00:05Z"},
+	}
+	// Repeated updates of the same eviction are only accounted once
+	setKueueWorkloadConditions(&m, preempted, created.Add(5*time.Second))
+	setKueueWorkloadConditions(&m, preempted, created.Add(6*time.Second))
+	if m.Evictions != 1 || m.Preemptions != 1 || m.EvictionReason != "Preempted" || m.PreemptionReason != "InCohortReclamation" {
+		t.Errorf("unexpected evictions %d (%s) preemptions %d (%s)", m.Evictions, m.EvictionReason, m.Preemptions, m.PreemptionReason)
+	}
+	// Milestones observed late use the transition time
+	setKueueWorkloadConditions(&m, []any{
+		map[string]any{"type": "Finished", "status": "True", "lastTransitionTime": "2025-01-01T10:00:20Z"},
+	}, created.Add(30*time.Second))
+	if !m.Finished || m.FinishedLatency != 20000 || m.AdmittedLatency != 2500 {
+		t.Errorf("unexpected finished %v after %dms, admitted after %dms", m.Finished, m.FinishedLatency, m.AdmittedLatency)
+	}
+}
+
+func TestKueueQueueSummaries(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	workloads := []kueueAdmissionMetric{
+		{Timestamp: created, Namespace: "tenant-a", LocalQueue: "lq", ClusterQueue: "cq-a", Admitted: true, QuotaReservedLatency: 1000, AdmittedLatency: 1000},
+		{Timestamp: created.Add(time.Second), Namespace: "tenant-a", LocalQueue: "lq", ClusterQueue: "cq-a", Admitted: true, QuotaReservedLatency: 2000, AdmittedLatency: 3000, Evictions: 1, Preemptions: 1},
+		{Timestamp: created, Namespace: "tenant-a", LocalQueue: "lq", ClusterQueue: "cq-a", QuotaReservedLatency: -1, AdmittedLatency: -1},
+		{Timestamp: created, Namespace: "tenant-b", LocalQueue: "lq", ClusterQueue: "cq-b", Admitted: true, Finished: true, QuotaReservedLatency: 500, AdmittedLatency: 500},
+	}
+	summaries := kueueQueueSummaries(workloads)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(summaries))
+	}
+	a := summaries[0]
+	if a.ClusterQueue != "cq-a" || a.Workloads != 3 || a.AdmittedWorkloads != 2 || a.EvictedWorkloads != 1 || a.PreemptedWorkloads != 1 {
+		t.Errorf("unexpected summary %+v", a)
+	}
+	if a.P50AdmittedLatency != 1000 || a.P99AdmittedLatency != 3000 {
+		t.Errorf("unexpected admitted percentiles P50 %dms P99 %dms", a.P50AdmittedLatency, a.P99AdmittedLatency)
+	}
+	// 2 workloads admitted from the first creation to the last admission, 4s later
+	if a.AdmissionThroughput != 0.5 {
+		t.Errorf("expected 0.5 admissions per second, got %f", a.AdmissionThroughput)
+	}
+	if b := summaries[1]; b.ClusterQueue != "cq-b" || b.FinishedWorkloads != 1 || b.AdmissionThroughput != 2 {
+		t.Errorf("unexpected summary %+v", b)
+	}
+}
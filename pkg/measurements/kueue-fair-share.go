@@ -0,0 +1,351 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	kueueFairShareMeasurementName      = "kueueFairShareMeasurement"
+	kueueFairShareQuantilesMeasurement = "kueueFairShareQuantilesMeasurement"
+	kueueFairShareSummaryName          = "kueueFairShareSummary"
+)
+
+var (
+	supportedKueueFairShareJobTypes = []config.JobType{config.CreationJob}
+	kueueClusterQueueGVR            = schema.GroupVersionResource{
+		Group:    "kueue.x-k8s.io",
+		Version:  "v1beta2",
+		Resource: "clusterqueues",
+	}
+)
+
+type kueueFairShareMetric struct {
+	Timestamp    time.Time `json:"timestamp"`
+	MetricName   string    `json:"metricName"`
+	UUID         string    `json:"uuid"`
+	JobName      string    `json:"jobName,omitempty"`
+	ClusterQueue string    `json:"clusterQueue"`
+	Cohort       string    `json:"cohort"`
+	Resource     string    `json:"resource"`
+	Weight       float64   `json:"weight"`
+	Metadata     any       `json:"metadata,omitempty"`
+	// Workloads of the ClusterQueue and resource usage of the admitted ones when the sample was taken
+	AdmittedWorkloads int   `json:"admittedWorkloads"`
+	PendingWorkloads  int   `json:"pendingWorkloads"`
+	Usage             int64 `json:"usage"`
+	CohortUsage       int64 `json:"cohortUsage"`
+	NominalQuota      int64 `json:"nominalQuota"`
+	// Share of the cohort usage of the ClusterQueue, and share expected according to the weights of the
+	// ClusterQueues of the cohort with workloads, admitted or pending
+	AdmittedShare float64 `json:"admittedShare"`
+	ExpectedShare float64 `json:"expectedShare"`
+	// Weighted share reported by Kueue, only set with fair sharing enabled
+	WeightedShare int64 `json:"weightedShare"`
+	// ClusterQueue summary fields, averages of the samples with demand in the ClusterQueue and usage in the cohort
+	Samples               int     `json:"samples,omitempty"`
+	AvgAdmittedShare      float64 `json:"avgAdmittedShare,omitempty"`
+	AvgExpectedShare      float64 `json:"avgExpectedShare,omitempty"`
+	AvgShareDeviation     float64 `json:"avgShareDeviation,omitempty"`
+	MaxShareDeviation     float64 `json:"maxShareDeviation,omitempty"`
+	AvgWeightedShare      float64 `json:"avgWeightedShare,omitempty"`
+	PeakAdmittedWorkloads int     `json:"peakAdmittedWorkloads,omitempty"`
+	PeakPendingWorkloads  int     `json:"peakPendingWorkloads,omitempty"`
+	PeakUsage             int64   `json:"peakUsage,omitempty"`
+}
+
+type kueueFairShare struct {
+	measurements.BaseMeasurement
+	dynamicClient dynamic.Interface
+	labelSelector string
+	resourceName  string
+	interval      time.Duration
+	stopCh        chan struct{}
+	samplerWg     sync.WaitGroup
+	samples       []kueueFairShareMetric
+}
+
+type kueueFairShareMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewKueueFairShareMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return kueueFairShareMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (kmf kueueFairShareMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &kueueFairShare{
+		BaseMeasurement: kmf.NewBaseLatency(jobConfig, clientSet, restConfig, kueueFairShareMeasurementName, kueueFairShareQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (k *kueueFairShare) setInputVars() error {
+	k.labelSelector = fmt.Sprintf("%s=%s", config.KubeBurnerLabelUUID, k.Uuid)
+	k.resourceName = "pods"
+	k.interval = 10 * time.Second
+	for _, obj := range k.JobConfig.Objects {
+		for key, val := range obj.InputVars {
+			var err error
+			switch key {
+			case "fairShareLabelSelector":
+				k.labelSelector = fmt.Sprint(val)
+			case "fairShareResource":
+				k.resourceName = fmt.Sprint(val)
+			case "fairShareInterval":
+				k.interval, err = time.ParseDuration(fmt.Sprint(val))
+			}
+			if err != nil {
+				return fmt.Errorf("failure parsing %s: %w", key, err)
+			}
+		}
+	}
+	if k.interval <= 0 {
+		return fmt.Errorf("fairShareInterval must be greater than 0")
+	}
+	return nil
+}
+
+func (k *kueueFairShare) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	k.LatencyQuantiles, k.NormLatencies = nil, nil
+	k.Metrics = sync.Map{}
+	k.samples = nil
+	if k.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := k.setInputVars(); err != nil {
+		return err
+	}
+	k.stopCh = make(chan struct{})
+	log.Infof("Sampling the %s share of the ClusterQueues matching %s every %v", k.resourceName, k.labelSelector, k.interval)
+	k.samplerWg.Go(func() {
+		ticker := time.NewTicker(k.interval)
+		defer ticker.Stop()
+		for {
+			k.sample()
+			select {
+			case <-k.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	return nil
+}
+
+func (k *kueueFairShare) sample() {
+	clusterQueues, err := k.dynamicClient.Resource(kueueClusterQueueGVR).List(context.TODO(), metav1.ListOptions{LabelSelector: k.labelSelector})
+	if err != nil {
+		log.Warnf("Failed to list ClusterQueues: %v", err)
+		return
+	}
+	k.samples = append(k.samples, kueueFairShareSamples(clusterQueues.Items, k.resourceName, time.Now().UTC())...)
+}
+
+// kueueFairShareSamples computes the share of the cohort usage of each ClusterQueue at the given time
+func kueueFairShareSamples(clusterQueues []unstructured.Unstructured, resourceName string, now time.Time) []kueueFairShareMetric {
+	type cohort struct {
+		usage        int64
+		activeWeight float64
+	}
+	cohorts := make(map[string]*cohort)
+	samples := make([]kueueFairShareMetric, 0, len(clusterQueues))
+	for _, cq := range clusterQueues {
+		m := kueueFairShareMetric{
+			Timestamp:    now,
+			MetricName:   kueueFairShareMeasurementName,
+			ClusterQueue: cq.GetName(),
+			Resource:     resourceName,
+			Weight:       1,
+		}
+		m.Cohort, _, _ = unstructured.NestedString(cq.Object, "spec", "cohortName")
+		// The weight is a quantity, that may be set as a number or as a string
+		if weight, found, _ := unstructured.NestedFieldNoCopy(cq.Object, "spec", "fairSharing", "weight"); found {
+			if quantity, err := resource.ParseQuantity(fmt.Sprint(weight)); err == nil {
+				m.Weight = quantity.AsApproximateFloat64()
+			}
+		}
+		admitted, _, _ := unstructured.NestedInt64(cq.Object, "status", "admittedWorkloads")
+		pending, _, _ := unstructured.NestedInt64(cq.Object, "status", "pendingWorkloads")
+		m.AdmittedWorkloads, m.PendingWorkloads = int(admitted), int(pending)
+		m.WeightedShare, _, _ = unstructured.NestedInt64(cq.Object, "status", "fairSharing", "weightedShare")
+		usage, _, _ := unstructured.NestedSlice(cq.Object, "status", "flavorsUsage")
+		m.Usage = kueueFlavorsResource(usage, resourceName, "total")
+		groups, _, _ := unstructured.NestedSlice(cq.Object, "spec", "resourceGroups")
+		for _, g := range groups {
+			if group, ok := g.(map[string]any); ok {
+				flavors, _, _ := unstructured.NestedSlice(group, "flavors")
+				m.NominalQuota += kueueFlavorsResource(flavors, resourceName, "nominalQuota")
+			}
+		}
+		c, ok := cohorts[m.Cohort]
+		if !ok {
+			c = &cohort{}
+			cohorts[m.Cohort] = c
+		}
+		c.usage += m.Usage
+		if m.AdmittedWorkloads > 0 || m.PendingWorkloads > 0 {
+			c.activeWeight += m.Weight
+		}
+		samples = append(samples, m)
+	}
+	for i := range samples {
+		c := cohorts[samples[i].Cohort]
+		samples[i].CohortUsage = c.usage
+		if c.usage > 0 {
+			samples[i].AdmittedShare = float64(samples[i].Usage) / float64(c.usage)
+		}
+		if c.activeWeight > 0 && (samples[i].AdmittedWorkloads > 0 || samples[i].PendingWorkloads > 0) {
+			samples[i].ExpectedShare = samples[i].Weight / c.activeWeight
+		}
+	}
+	return samples
+}
+
+// kueueFlavorsResource adds the given field of the resource across the flavors, e.g. the usage total or the nominal quota
+func kueueFlavorsResource(flavors []any, resourceName, field string) int64 {
+	var total int64
+	for _, f := range flavors {
+		flavor, ok := f.(map[string]any)
+		if !ok {
+			continue
+		}
+		resources, _, _ := unstructured.NestedSlice(flavor, "resources")
+		for _, r := range resources {
+			res, ok := r.(map[string]any)
+			if !ok || res["name"] != resourceName {
+				continue
+			}
+			if quantity, err := resource.ParseQuantity(fmt.Sprint(res[field])); err == nil {
+				total += quantity.Value()
+			}
+		}
+	}
+	return total
+}
+
+// kueueFairShareSummaries returns a document per ClusterQueue averaging the samples taken with cohort usage
+func kueueFairShareSummaries(samples []kueueFairShareMetric) []kueueFairShareMetric {
+	summaries := make(map[string]*kueueFairShareMetric)
+	var names []string
+	for _, m := range samples {
+		s, ok := summaries[m.ClusterQueue]
+		if !ok {
+			s = &kueueFairShareMetric{
+				MetricName:   kueueFairShareSummaryName,
+				ClusterQueue: m.ClusterQueue,
+				Cohort:       m.Cohort,
+				Resource:     m.Resource,
+				Weight:       m.Weight,
+				NominalQuota: m.NominalQuota,
+			}
+			summaries[m.ClusterQueue] = s
+			names = append(names, m.ClusterQueue)
+		}
+		s.PeakAdmittedWorkloads = max(s.PeakAdmittedWorkloads, m.AdmittedWorkloads)
+		s.PeakPendingWorkloads = max(s.PeakPendingWorkloads, m.PendingWorkloads)
+		s.PeakUsage = max(s.PeakUsage, m.Usage)
+		// Samples without demand in the ClusterQueue or usage in the cohort don't say anything about the sharing
+		if m.ExpectedShare == 0 || m.CohortUsage == 0 {
+			continue
+		}
+		deviation := math.Abs(m.AdmittedShare - m.ExpectedShare)
+		s.Samples++
+		s.AvgAdmittedShare += m.AdmittedShare
+		s.AvgExpectedShare += m.ExpectedShare
+		s.AvgShareDeviation += deviation
+		s.AvgWeightedShare += float64(m.WeightedShare)
+		s.MaxShareDeviation = max(s.MaxShareDeviation, deviation)
+	}
+	slices.Sort(names)
+	result := make([]kueueFairShareMetric, 0, len(names))
+	for _, name := range names {
+		s := summaries[name]
+		s.Timestamp = time.Now().UTC()
+		if s.Samples > 0 {
+			s.AvgAdmittedShare /= float64(s.Samples)
+			s.AvgExpectedShare /= float64(s.Samples)
+			s.AvgShareDeviation /= float64(s.Samples)
+			s.AvgWeightedShare /= float64(s.Samples)
+		}
+		result = append(result, *s)
+	}
+	return result
+}
+
+func (k *kueueFairShare) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (k *kueueFairShare) Stop() error {
+	if k.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(k.stopCh)
+	k.samplerWg.Wait()
+	for i, m := range k.samples {
+		k.Metrics.Store(fmt.Sprintf("%s/%d", m.ClusterQueue, i), m)
+	}
+	for _, s := range kueueFairShareSummaries(k.samples) {
+		log.Infof("ClusterQueue %s weight %.2f: average admitted %s share %.2f, expected %.2f, average deviation %.2f",
+			s.ClusterQueue, s.Weight, s.Resource, s.AvgAdmittedShare, s.AvgExpectedShare, s.AvgShareDeviation)
+		k.Metrics.Store(kueueFairShareSummaryName+"/"+s.ClusterQueue, s)
+	}
+	return k.StopMeasurement(k.normalizeMetrics, k.getLatency)
+}
+
+func (k *kueueFairShare) normalizeMetrics() float64 {
+	k.Metrics.Range(func(key, value any) bool {
+		m := value.(kueueFairShareMetric)
+		m.UUID = k.Uuid
+		m.JobName = k.JobConfig.Name
+		m.Metadata = k.Metadata
+		k.NormLatencies = append(k.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency doesn't report quantiles, shares are compared per ClusterQueue in the summaries
+func (k *kueueFairShare) getLatency(normLatency any) map[string]float64 {
+	return map[string]float64{}
+}
+
+func (k *kueueFairShare) IsCompatible() bool {
+	return slices.Contains(supportedKueueFairShareJobTypes, k.JobConfig.JobType)
+}
@@ -0,0 +1,87 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestClusterQueue(name string, weight any, admitted, pending int64, usage string) unstructured.Unstructured {
+	cq := unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"cohortName": "tenants",
+			"resourceGroups": []any{map[string]any{
+				"coveredResources": []any{"pods"},
+				"flavors": []any{map[string]any{
+					"name":      "default",
+					"resources": []any{map[string]any{"name": "pods", "nominalQuota": int64(10), "borrowingLimit": int64(10)}},
+				}},
+			}},
+		},
+		"status": map[string]any{
+			"admittedWorkloads": admitted,
+			"pendingWorkloads":  pending,
+			"flavorsUsage": []any{map[string]any{
+				"name":      "default",
+				"resources": []any{map[string]any{"name": "pods", "total": usage}},
+			}},
+		},
+	}}
+	cq.SetName(name)
+	if weight != nil {
+		unstructured.SetNestedField(cq.Object, weight, "spec", "fairSharing", "weight")
+	}
+	return cq
+}
+
+func TestKueueFairShareSamples(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	samples := kueueFairShareSamples([]unstructured.Unstructured{
+		newTestClusterQueue("tenant-0", nil, 3, 10, "6"),
+		newTestClusterQueue("tenant-1", "3", 7, 5, "14"),
+		newTestClusterQueue("tenant-2", int64(2), 0, 0, "0"),
+	}, "pods", now)
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(samples))
+	}
+	// tenant-2 has no workloads, the cohort is shared between tenant-0 and tenant-1 with weights 1 and 3
+	expected := map[string][2]float64{
+		"tenant-0": {0.3, 0.25},
+		"tenant-1": {0.7, 0.75},
+		"tenant-2": {0, 0},
+	}
+	for _, m := range samples {
+		if m.CohortUsage != 20 || m.NominalQuota != 10 || m.Cohort != "tenants" {
+			t.Errorf("unexpected %s cohort %s usage %d, nominal quota %d", m.ClusterQueue, m.Cohort, m.CohortUsage, m.NominalQuota)
+		}
+		if m.AdmittedShare != expected[m.ClusterQueue][0] || m.ExpectedShare != expected[m.ClusterQueue][1] {
+			t.Errorf("unexpected %s admitted share %.2f and expected share %.2f", m.ClusterQueue, m.AdmittedShare, m.ExpectedShare)
+		}
+	}
+	if samples[2].Weight != 2 {
+		t.Errorf("expected weight 2, got %.2f", samples[2].Weight)
+	}
+}
+
+func TestKueueFairShareSummaries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	samples := []kueueFairShareMetric{
+		// Samples without cohort usage are excluded from the averages
+		{ClusterQueue: "tenant-0", Weight: 1, PendingWorkloads: 10, ExpectedShare: 0.5},
+		{ClusterQueue: "tenant-0", Weight: 1, AdmittedWorkloads: 2, PendingWorkloads: 8, Usage: 4, CohortUsage: 10, AdmittedShare: 0.4, ExpectedShare: 0.5},
+		{ClusterQueue: "tenant-0", Weight: 1, AdmittedWorkloads: 3, PendingWorkloads: 2, Usage: 6, CohortUsage: 10, AdmittedShare: 0.6, ExpectedShare: 0.5},
+		{ClusterQueue: "tenant-1", Weight: 1, AdmittedWorkloads: 3, Usage: 6, CohortUsage: 10, AdmittedShare: 0.6, ExpectedShare: 0.5, Timestamp: now},
+	}
+	summaries := kueueFairShareSummaries(samples)
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(summaries))
+	}
+	s := summaries[0]
+	if s.ClusterQueue != "tenant-0" || s.Samples != 2 || s.PeakPendingWorkloads != 10 || s.PeakUsage != 6 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if s.AvgAdmittedShare != 0.5 || s.AvgExpectedShare != 0.5 || s.AvgShareDeviation < 0.0999 || s.AvgShareDeviation > 0.1001 {
+		t.Errorf("unexpected shares admitted %.2f expected %.2f deviation %.4f", s.AvgAdmittedShare, s.AvgExpectedShare, s.AvgShareDeviation)
+	}
+}
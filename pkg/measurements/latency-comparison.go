@@ -0,0 +1,208 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// LatencyComparison records the quantiles indexed by the measurements of several runs of a workload to compare them side by side
+type LatencyComparison struct {
+	mu      sync.Mutex
+	current string
+	started time.Time
+	runs    []string
+	results map[string]int
+	// Quantiles per row, job and quantile name, and run
+	quantiles map[string]map[string]comparedQuantile
+}
+
+type comparedQuantile struct {
+	QuantileName string  `json:"quantileName"`
+	JobName      string  `json:"jobName"`
+	P50          float64 `json:"P50"`
+	P95          float64 `json:"P95"`
+	P99          float64 `json:"P99"`
+	Max          float64 `json:"max"`
+	Avg          float64 `json:"avg"`
+}
+
+// LatencyComparisonStats lists the statistics of a quantile that can be looked up
+var LatencyComparisonStats = []string{"P50", "P95", "P99", "max", "avg"}
+
+// latencyQuantilesFilePattern matches the quantile documents written by the local indexer, named <metricName>-<jobName>.json
+const latencyQuantilesFilePattern = "*QuantilesMeasurement-*.json"
+
+func NewLatencyComparison() *LatencyComparison {
+	return &LatencyComparison{
+		results:   make(map[string]int),
+		quantiles: make(map[string]map[string]comparedQuantile),
+	}
+}
+
+// SetRun sets the run the following measurements belong to
+func (c *LatencyComparison) SetRun(run string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = run
+	c.started = time.Now().Truncate(time.Second)
+	c.runs = append(c.runs, run)
+}
+
+// SetResult records the return code of the run
+func (c *LatencyComparison) SetResult(run string, rc int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[run] = rc
+}
+
+// Collect records the quantiles indexed locally in the metrics directory, or its subdirectories, since the current run was set.
+// Reading the indexed documents covers the kube-burner built-in measurements as well as the ones of this repository
+func (c *LatencyComparison) Collect(metricsDirectory string) error {
+	c.mu.Lock()
+	started := c.started
+	c.mu.Unlock()
+	return filepath.WalkDir(metricsDirectory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Nothing was indexed when the run failed early
+			if path == metricsDirectory && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if matched, _ := filepath.Match(latencyQuantilesFilePattern, d.Name()); !matched {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		// Files left by a previous run in the same directory
+		if info.ModTime().Before(started) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := c.record(data); err != nil {
+			return fmt.Errorf("failed parsing %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// record records the quantile documents under the current run
+func (c *LatencyComparison) record(data []byte) error {
+	var latencyQuantiles []comparedQuantile
+	if err := json.Unmarshal(data, &latencyQuantiles); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, q := range latencyQuantiles {
+		if q.QuantileName == "" {
+			continue
+		}
+		row := q.JobName + " " + q.QuantileName
+		if _, ok := c.quantiles[row]; !ok {
+			c.quantiles[row] = make(map[string]comparedQuantile)
+		}
+		c.quantiles[row][c.current] = q
+	}
+	return nil
+}
+
+// Quantile returns the given statistic in milliseconds of a quantile of the run, the highest one when several jobs report the quantile
+func (c *LatencyComparison) Quantile(run, quantileName, stat string) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var value float64
+	var found bool
+	for _, runs := range c.quantiles {
+		q, ok := runs[run]
+		if !ok || q.QuantileName != quantileName {
+			continue
+		}
+		found = true
+		value = max(value, q.stat(stat))
+	}
+	return value, found
+}
+
+func (q comparedQuantile) stat(stat string) float64 {
+	switch stat {
+	case "P50":
+		return q.P50
+	case "P95":
+		return q.P95
+	case "max":
+		return q.Max
+	case "avg":
+		return q.Avg
+	default:
+		return q.P99
+	}
+}
+
+// Table renders the result of every run and the P50/P99 of each quantile, one column per run
+func (c *LatencyComparison) Table() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "QUANTILE (P50 / P99)\t%s\n", strings.Join(c.runs, "\t"))
+	results := make([]string, 0, len(c.runs))
+	for _, run := range c.runs {
+		result := "passed"
+		if c.results[run] != 0 {
+			result = fmt.Sprintf("failed (rc %d)", c.results[run])
+		}
+		results = append(results, result)
+	}
+	fmt.Fprintf(w, "result\t%s\n", strings.Join(results, "\t"))
+	rows := make([]string, 0, len(c.quantiles))
+	for row := range c.quantiles {
+		rows = append(rows, row)
+	}
+	slices.Sort(rows)
+	for _, row := range rows {
+		values := make([]string, 0, len(c.runs))
+		for _, run := range c.runs {
+			q, ok := c.quantiles[row][run]
+			if !ok {
+				values = append(values, "-")
+				continue
+			}
+			values = append(values, fmt.Sprintf("%s / %s", time.Duration(q.P50)*time.Millisecond, time.Duration(q.P99)*time.Millisecond))
+		}
+		fmt.Fprintf(w, "%s\t%s\n", row, strings.Join(values, "\t"))
+	}
+	w.Flush()
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package measurements
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeQuantilesFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLatencyComparisonTable(t *testing.T) {
+	dir := t.TempDir()
+	comparison := NewLatencyComparison()
+	comparison.SetRun("gp3-csi/RWO")
+	writeQuantilesFile(t, dir, "pvcLatencyQuantilesMeasurement-pvc-density.json", `[
+  {"quantileName": "Bound", "jobName": "pvc-density", "P50": 1000, "P99": 3000, "metricName": "pvcLatencyQuantilesMeasurement"}
+]`)
+	writeQuantilesFile(t, dir, "podLatencyQuantilesMeasurement-pvc-density.json", `[
+  {"quantileName": "Ready", "jobName": "pvc-density", "P50": 2000, "P99": 4000, "metricName": "podLatencyQuantilesMeasurement"}
+]`)
+	// Measurement documents aren't quantiles
+	writeQuantilesFile(t, dir, "podLatencyMeasurement-pvc-density.json", `[{"podName": "pvc-density-1"}]`)
+	if err := comparison.Collect(dir); err != nil {
+		t.Fatal(err)
+	}
+	comparison.SetResult("gp3-csi/RWO", 0)
+
+	comparison.SetRun("ocs-rbd/RWX")
+	// The pod quantiles of the previous run are left in the directory
+	podQuantiles := filepath.Join(dir, "podLatencyQuantilesMeasurement-pvc-density.json")
+	if err := os.Chtimes(podQuantiles, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	writeQuantilesFile(t, dir, "pvcLatencyQuantilesMeasurement-pvc-density.json", `[
+  {"quantileName": "Bound", "jobName": "pvc-density", "P50": 500, "P99": 1500}
+]`)
+	if err := comparison.Collect(dir); err != nil {
+		t.Fatal(err)
+	}
+	comparison.SetResult("ocs-rbd/RWX", 1)
+
+	lines := strings.Split(strings.TrimSpace(comparison.Table()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header, the results and 2 quantiles, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if fields := strings.Fields(lines[1]); !slices.Equal(fields, []string{"result", "passed", "failed", "(rc", "1)"}) {
+		t.Errorf("unexpected result row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); !slices.Equal(fields, []string{"pvc-density", "Bound", "1s", "/", "3s", "500ms", "/", "1.5s"}) {
+		t.Errorf("unexpected Bound row %q", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); !slices.Equal(fields, []string{"pvc-density", "Ready", "2s", "/", "4s", "-"}) {
+		t.Errorf("unexpected Ready row %q", lines[3])
+	}
+}
+
+func TestLatencyComparisonQuantile(t *testing.T) {
+	dir := t.TempDir()
+	comparison := NewLatencyComparison()
+	comparison.SetRun("loop-0")
+	// Quantiles of the kube-burner vmiLatency measurement of two jobs of the loop
+	writeQuantilesFile(t, filepath.Join(dir, "iteration-0"), "vmiLatencyQuantilesMeasurement-create-vms-0.json", `[
+  {"quantileName": "VMReady", "jobName": "create-vms-0", "P99": 3000, "max": 5000}
+]`)
+	writeQuantilesFile(t, filepath.Join(dir, "iteration-0"), "vmiLatencyQuantilesMeasurement-restart-vms-0.json", `[
+  {"quantileName": "VMReady", "jobName": "restart-vms-0", "P99": 4000, "max": 4500}
+]`)
+	if err := comparison.Collect(dir); err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := comparison.Quantile("loop-0", "VMReady", "P99"); !ok || value != 4000 {
+		t.Errorf("expected the highest P99 4000, got %v %v", value, ok)
+	}
+	if value, ok := comparison.Quantile("loop-0", "VMReady", "max"); !ok || value != 5000 {
+		t.Errorf("expected the highest max 5000, got %v %v", value, ok)
+	}
+	if _, ok := comparison.Quantile("loop-1", "VMReady", "P99"); ok {
+		t.Error("expected no quantile for an unknown run")
+	}
+	writeQuantilesFile(t, dir, "vmiLatencyQuantilesMeasurement-broken.json", "{")
+	if err := comparison.Collect(dir); err == nil {
+		t.Error("expected an error with a malformed quantiles file")
+	}
+}
@@ -0,0 +1,430 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	netpolConnectivityMeasurementName      = "netpolConnectivityMeasurement"
+	netpolConnectivityQuantilesMeasurement = "netpolConnectivityQuantilesMeasurement"
+	netpolConnectivitySummaryName          = "netpolConnectivitySummary"
+	// Port served by the webserver container of the network-policy pods, it's the first single port allowed by the rules
+	netpolProbePort      = 8080
+	netpolProbeContainer = "curlapp"
+	netpolProbeWorkers   = 20
+	// curl exit codes returned when the connection is refused or times out
+	curlCouldntConnect    = 7
+	curlOperationTimedOut = 28
+)
+
+var supportedNetpolConnectivityJobTypes = []config.JobType{config.CreationJob}
+
+// netpolMatrix derives the expected connectivity from the parameters used to render the ingress-np.yml and egress-np.yml templates
+type netpolMatrix struct {
+	namespaces       int
+	podsPerNamespace int
+	netpolsPerNs     int
+	localPods        int
+	podSelectors     int
+	singlePorts      int
+	peerNamespaces   int
+	peerPods         int
+	egress           bool
+}
+
+// netpolEndpoint identifies a pod by its namespace index and its num label
+type netpolEndpoint struct {
+	ns  int
+	pod int
+}
+
+type netpolConnectivityMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Metadata   any       `json:"metadata,omitempty"`
+	SourceNs   string    `json:"sourceNamespace,omitempty"`
+	SourcePod  string    `json:"sourcePod,omitempty"`
+	TargetNs   string    `json:"targetNamespace,omitempty"`
+	TargetPod  string    `json:"targetPod,omitempty"`
+	Expected   string    `json:"expected,omitempty"`
+	Observed   string    `json:"observed,omitempty"`
+	// Summary fields
+	ProbedPairs      int     `json:"probedPairs"`
+	ProbeErrors      int     `json:"probeErrors"`
+	UnexpectedAllows int     `json:"unexpectedAllows"`
+	UnexpectedDenies int     `json:"unexpectedDenies"`
+	MismatchPercent  float64 `json:"mismatchPercent"`
+	probeLatency     int
+}
+
+type netpolConnectivity struct {
+	measurements.BaseMeasurement
+	matrix       netpolMatrix
+	sampleSize   int
+	maxMismatch  float64
+	probeTimeout time.Duration
+}
+
+type netpolConnectivityMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewNetpolConnectivityMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return netpolConnectivityMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (nmf netpolConnectivityMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &netpolConnectivity{
+		BaseMeasurement: nmf.NewBaseLatency(jobConfig, clientSet, restConfig, netpolConnectivityMeasurementName, netpolConnectivityQuantilesMeasurement, embedCfg),
+		sampleSize:      100,
+		probeTimeout:    3 * time.Second,
+	}
+}
+
+func inputVarInt(val any) int {
+	i, _ := strconv.Atoi(fmt.Sprint(val))
+	return i
+}
+
+// Read input variables from job templates, they're the same passed to the network policy templates
+func (n *netpolConnectivity) setInputVars() {
+	for _, obj := range n.JobConfig.Objects {
+		vars := obj.InputVars
+		if _, ok := vars["namespaces"]; !ok {
+			continue
+		}
+		n.matrix = netpolMatrix{
+			namespaces:       inputVarInt(vars["namespaces"]),
+			podsPerNamespace: inputVarInt(vars["pods_per_namespace"]),
+			netpolsPerNs:     inputVarInt(vars["netpols_per_namespace"]),
+			localPods:        inputVarInt(vars["local_pods"]),
+			podSelectors:     inputVarInt(vars["pod_selectors"]),
+			singlePorts:      inputVarInt(vars["single_ports"]),
+			peerNamespaces:   inputVarInt(vars["peer_namespaces"]),
+			peerPods:         inputVarInt(vars["peer_pods"]),
+			egress:           fmt.Sprint(vars["egress_rules"]) == "true",
+		}
+		if val, ok := vars["verifySampleSize"]; ok {
+			n.sampleSize = inputVarInt(val)
+		}
+		if val, ok := vars["verifyMaxMismatchPercent"]; ok {
+			n.maxMismatch, _ = strconv.ParseFloat(fmt.Sprint(val), 64)
+		}
+		if val, ok := vars["verifyProbeTimeout"]; ok {
+			if timeout, err := time.ParseDuration(fmt.Sprint(val)); err == nil {
+				n.probeTimeout = timeout
+			}
+		}
+		return
+	}
+}
+
+// peers returns the namespaces and pods selected by the rules of the policy replica in namespace ns
+func (m netpolMatrix) peers(ns, replica int) ([]int, []int) {
+	var peerNamespaces, peerPods []int
+	peerPodIdx := (replica*m.podSelectors*m.peerNamespaces - 1) / m.namespaces * m.peerPods
+	for lp := range m.peerPods {
+		nextPod := 1 + lp + peerPodIdx
+		if nextPod > m.podsPerNamespace {
+			nextPod = nextPod%m.podsPerNamespace + 1
+		}
+		peerPods = append(peerPods, nextPod)
+	}
+	peerNsIdx := ns*m.podSelectors*m.netpolsPerNs*m.peerNamespaces + (replica-1)*m.podSelectors*m.peerNamespaces + 1
+	for ps := range m.podSelectors {
+		nsStart := peerNsIdx + ps*m.peerNamespaces
+		for i := range m.peerNamespaces {
+			nextNs := nsStart + i
+			if nextNs >= m.namespaces {
+				nextNs = nextNs % m.namespaces
+			}
+			peerNamespaces = append(peerNamespaces, nextNs)
+		}
+	}
+	return peerNamespaces, peerPods
+}
+
+// selectedBy returns whether the peer is selected by any policy of namespace ns
+func (m netpolMatrix) selectedBy(ns int, peer netpolEndpoint) bool {
+	for replica := 1; replica <= m.netpolsPerNs; replica++ {
+		peerNamespaces, peerPods := m.peers(ns, replica)
+		if slices.Contains(peerNamespaces, peer.ns) && slices.Contains(peerPods, peer.pod) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed returns whether traffic from src to the probe port of dst is expected to be allowed.
+// Every namespace has a deny-all ingress policy, only the local pods are opened to their peers.
+func (m netpolMatrix) allowed(src, dst netpolEndpoint) bool {
+	if m.singlePorts < 1 || dst.pod > m.localPods || !m.selectedBy(dst.ns, src) {
+		return false
+	}
+	// Egress is only restricted for the local pods, when egress policies are created
+	if m.egress && src.pod <= m.localPods {
+		return m.selectedBy(src.ns, dst)
+	}
+	return true
+}
+
+// allowedPairs enumerates the pairs expected to be allowed
+func (m netpolMatrix) allowedPairs() [][2]netpolEndpoint {
+	var pairs [][2]netpolEndpoint
+	for dstNs := range m.namespaces {
+		seen := make(map[netpolEndpoint]bool)
+		for replica := 1; replica <= m.netpolsPerNs; replica++ {
+			peerNamespaces, peerPods := m.peers(dstNs, replica)
+			for _, srcNs := range peerNamespaces {
+				for _, srcPod := range peerPods {
+					seen[netpolEndpoint{ns: srcNs, pod: srcPod}] = true
+				}
+			}
+		}
+		for src := range seen {
+			for dstPod := 1; dstPod <= m.localPods; dstPod++ {
+				dst := netpolEndpoint{ns: dstNs, pod: dstPod}
+				if m.allowed(src, dst) {
+					pairs = append(pairs, [2]netpolEndpoint{src, dst})
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// samplePairs picks up to size pairs, half of them expected to be allowed and the rest picked randomly
+func (m netpolMatrix) samplePairs(size int, rnd *rand.Rand) [][2]netpolEndpoint {
+	allowed := m.allowedPairs()
+	rnd.Shuffle(len(allowed), func(i, j int) { allowed[i], allowed[j] = allowed[j], allowed[i] })
+	sample := allowed[:min(len(allowed), size/2)]
+	total := m.namespaces * m.podsPerNamespace
+	for attempts := 0; len(sample) < size && attempts < size*10 && total > 1; attempts++ {
+		src := netpolEndpoint{ns: rnd.IntN(m.namespaces), pod: rnd.IntN(m.podsPerNamespace) + 1}
+		dst := netpolEndpoint{ns: rnd.IntN(m.namespaces), pod: rnd.IntN(m.podsPerNamespace) + 1}
+		if src == dst {
+			continue
+		}
+		sample = append(sample, [2]netpolEndpoint{src, dst})
+	}
+	return sample
+}
+
+func (n *netpolConnectivity) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	n.LatencyQuantiles, n.NormLatencies = nil, nil
+	n.Metrics = sync.Map{}
+	if n.JobConfig.SkipIndexing {
+		return nil
+	}
+	n.setInputVars()
+	return nil
+}
+
+func (n *netpolConnectivity) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// probe connects from the source pod to the target IP, returning whether the connection succeeded
+func (n *netpolConnectivity) probe(namespace, pod, targetIP string) (bool, error) {
+	cmd := []string{"curl", "-s", "-o", "/dev/null", "--connect-timeout", fmt.Sprint(n.probeTimeout.Seconds()), fmt.Sprintf("http://%s:%d", targetIP, netpolProbePort)}
+	req := n.ClientSet.CoreV1().RESTClient().Post().Resource("pods").Name(pod).Namespace(namespace).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: netpolProbeContainer,
+			Command:   cmd,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(n.RestConfig, "POST", req.URL())
+	if err != nil {
+		return false, err
+	}
+	var stdout, stderr bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.TODO(), n.probeTimeout+10*time.Second)
+	defer cancel()
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	return netpolProbeResult(err, stderr.String())
+}
+
+// netpolProbeResult classifies the probe outcome: only curl failing to connect or timing out means
+// the connection was denied, any other exit code or error means we couldn't probe
+func netpolProbeResult(err error, stderr string) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitStatus() {
+		case curlCouldntConnect, curlOperationTimedOut:
+			return false, nil
+		}
+		return false, fmt.Errorf("probe failed with curl exit code %d: %s", exitErr.ExitStatus(), stderr)
+	}
+	return false, fmt.Errorf("%v: %s", err, stderr)
+}
+
+func (n *netpolConnectivity) Stop() error {
+	if n.JobConfig.SkipIndexing {
+		return nil
+	}
+	if n.matrix.namespaces == 0 || n.matrix.podsPerNamespace == 0 {
+		log.Warn("Network policy connectivity verification requires the network policy template parameters, skipping")
+		return nil
+	}
+	pods := make(map[netpolEndpoint]corev1.Pod)
+	for ns := range n.matrix.namespaces {
+		namespace := fmt.Sprintf("%s-%d", n.JobConfig.Namespace, ns)
+		podList, err := n.ClientSet.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing pods in namespace %s: %w", namespace, err)
+		}
+		for _, pod := range podList.Items {
+			if num, err := strconv.Atoi(pod.Labels["num"]); err == nil && pod.Status.PodIP != "" {
+				pods[netpolEndpoint{ns: ns, pod: num}] = pod
+			}
+		}
+	}
+	sample := n.matrix.samplePairs(n.sampleSize, rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)))
+	log.Infof("Verifying network policy connectivity on %d pod pairs", len(sample))
+
+	summary := netpolConnectivityMetric{Timestamp: time.Now().UTC()}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	pairCh := make(chan [2]netpolEndpoint)
+	for range netpolProbeWorkers {
+		wg.Go(func() {
+			for pair := range pairCh {
+				src, srcOK := pods[pair[0]]
+				dst, dstOK := pods[pair[1]]
+				if !srcOK || !dstOK {
+					continue
+				}
+				expected := n.matrix.allowed(pair[0], pair[1])
+				start := time.Now()
+				connected, err := n.probe(src.Namespace, src.Name, dst.Status.PodIP)
+				mu.Lock()
+				summary.ProbedPairs++
+				if err != nil {
+					log.Debugf("Error probing %s/%s -> %s/%s: %v", src.Namespace, src.Name, dst.Namespace, dst.Name, err)
+					summary.ProbeErrors++
+					mu.Unlock()
+					continue
+				}
+				if connected != expected {
+					m := netpolConnectivityMetric{
+						Timestamp:    start.UTC(),
+						SourceNs:     src.Namespace,
+						SourcePod:    src.Name,
+						TargetNs:     dst.Namespace,
+						TargetPod:    dst.Name,
+						Expected:     connectivityVerdict(expected),
+						Observed:     connectivityVerdict(connected),
+						probeLatency: int(time.Since(start).Milliseconds()),
+					}
+					if connected {
+						summary.UnexpectedAllows++
+					} else {
+						summary.UnexpectedDenies++
+					}
+					n.Metrics.Store(fmt.Sprintf("%s/%s-%s/%s", src.Namespace, src.Name, dst.Namespace, dst.Name), m)
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	for _, pair := range sample {
+		pairCh <- pair
+	}
+	close(pairCh)
+	wg.Wait()
+
+	verified := summary.ProbedPairs - summary.ProbeErrors
+	if verified > 0 {
+		summary.MismatchPercent = float64(summary.UnexpectedAllows+summary.UnexpectedDenies) * 100 / float64(verified)
+	}
+	summary.MetricName = netpolConnectivitySummaryName
+	n.Metrics.Store(netpolConnectivitySummaryName, summary)
+	log.Infof("Network policy connectivity: %d pairs probed, %d unexpected allows, %d unexpected denies, %d errors", summary.ProbedPairs, summary.UnexpectedAllows, summary.UnexpectedDenies, summary.ProbeErrors)
+	if err := n.StopMeasurement(n.normalizeMetrics, n.getLatency); err != nil {
+		return err
+	}
+	if summary.MismatchPercent > n.maxMismatch {
+		return fmt.Errorf("network policy connectivity mismatches %.2f%% above the %.2f%% threshold", summary.MismatchPercent, n.maxMismatch)
+	}
+	return nil
+}
+
+func connectivityVerdict(allowed bool) string {
+	if allowed {
+		return "allow"
+	}
+	return "deny"
+}
+
+func (n *netpolConnectivity) normalizeMetrics() float64 {
+	n.Metrics.Range(func(key, value any) bool {
+		m := value.(netpolConnectivityMetric)
+		if m.MetricName == "" {
+			m.MetricName = netpolConnectivityMeasurementName
+		}
+		m.UUID = n.Uuid
+		m.JobName = n.JobConfig.Name
+		m.Metadata = n.Metadata
+		n.NormLatencies = append(n.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+func (n *netpolConnectivity) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(netpolConnectivityMetric)
+	if m.MetricName == netpolConnectivitySummaryName {
+		return map[string]float64{}
+	}
+	return map[string]float64{
+		"ProbeLatency": float64(m.probeLatency),
+	}
+}
+
+func (n *netpolConnectivity) IsCompatible() bool {
+	return slices.Contains(supportedNetpolConnectivityJobTypes, n.JobConfig.JobType)
+}
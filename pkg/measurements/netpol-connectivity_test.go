@@ -0,0 +1,101 @@
+package measurements
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func TestNetpolMatrixAllowed(t *testing.T) {
+	m := netpolMatrix{
+		namespaces:       3,
+		podsPerNamespace: 4,
+		netpolsPerNs:     1,
+		localPods:        2,
+		podSelectors:     1,
+		singlePorts:      1,
+		peerNamespaces:   1,
+		peerPods:         1,
+	}
+	// Every namespace accepts traffic to its local pods from pod 1 of the next namespace
+	for _, tc := range []struct {
+		src, dst netpolEndpoint
+		want     bool
+	}{
+		{netpolEndpoint{ns: 1, pod: 1}, netpolEndpoint{ns: 0, pod: 1}, true},
+		{netpolEndpoint{ns: 1, pod: 1}, netpolEndpoint{ns: 0, pod: 2}, true},
+		{netpolEndpoint{ns: 0, pod: 1}, netpolEndpoint{ns: 2, pod: 1}, true},
+		{netpolEndpoint{ns: 1, pod: 2}, netpolEndpoint{ns: 0, pod: 1}, false},
+		{netpolEndpoint{ns: 1, pod: 1}, netpolEndpoint{ns: 0, pod: 3}, false},
+		{netpolEndpoint{ns: 0, pod: 2}, netpolEndpoint{ns: 0, pod: 1}, false},
+	} {
+		if got := m.allowed(tc.src, tc.dst); got != tc.want {
+			t.Errorf("allowed(%v, %v) = %v, want %v", tc.src, tc.dst, got, tc.want)
+		}
+	}
+	if got := len(m.allowedPairs()); got != 6 {
+		t.Fatalf("expected 6 allowed pairs, got %d", got)
+	}
+
+	// Local pods of the source namespace can only reach the peers of their own egress policies
+	m.egress = true
+	if m.allowed(netpolEndpoint{ns: 1, pod: 1}, netpolEndpoint{ns: 0, pod: 1}) {
+		t.Fatal("did not expect egress from a local pod to a namespace outside its egress peers")
+	}
+	if got := len(m.allowedPairs()); got != 0 {
+		t.Fatalf("expected no allowed pairs with egress policies, got %d", got)
+	}
+}
+
+func TestNetpolMatrixSamplePairs(t *testing.T) {
+	m := netpolMatrix{
+		namespaces:       3,
+		podsPerNamespace: 4,
+		netpolsPerNs:     1,
+		localPods:        2,
+		podSelectors:     1,
+		singlePorts:      1,
+		peerNamespaces:   1,
+		peerPods:         1,
+	}
+	sample := m.samplePairs(10, rand.New(rand.NewPCG(1, 2)))
+	if len(sample) != 10 {
+		t.Fatalf("expected 10 sampled pairs, got %d", len(sample))
+	}
+	allowed := 0
+	for _, pair := range sample {
+		if pair[0] == pair[1] {
+			t.Fatalf("unexpected pair probing a pod against itself: %v", pair)
+		}
+		if m.allowed(pair[0], pair[1]) {
+			allowed++
+		}
+	}
+	if allowed < 5 {
+		t.Fatalf("expected at least half of the sample to be allowed pairs, got %d", allowed)
+	}
+}
+
+func TestNetpolProbeResult(t *testing.T) {
+	for _, tc := range []struct {
+		err     error
+		allowed bool
+		wantErr bool
+	}{
+		{nil, true, false},
+		{utilexec.CodeExitError{Err: errors.New("command terminated with exit code 7"), Code: 7}, false, false},
+		{utilexec.CodeExitError{Err: errors.New("command terminated with exit code 28"), Code: 28}, false, false},
+		// curl not installed, malformed URL and DNS failures aren't denied connections
+		{utilexec.CodeExitError{Err: errors.New("command terminated with exit code 127"), Code: 127}, false, true},
+		{utilexec.CodeExitError{Err: errors.New("command terminated with exit code 3"), Code: 3}, false, true},
+		{utilexec.CodeExitError{Err: errors.New("command terminated with exit code 6"), Code: 6}, false, true},
+		{errors.New("pod not found"), false, true},
+	} {
+		allowed, err := netpolProbeResult(tc.err, "")
+		if allowed != tc.allowed || (err != nil) != tc.wantErr {
+			t.Errorf("probe error %v: expected allowed %v and error %v, got %v and %v", tc.err, tc.allowed, tc.wantErr, allowed, err)
+		}
+	}
+}
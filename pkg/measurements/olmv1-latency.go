@@ -0,0 +1,331 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	olmv1LatencyMeasurementName      = "olmv1LatencyMeasurement"
+	olmv1LatencyQuantilesMeasurement = "olmv1LatencyQuantilesMeasurement"
+	clusterCatalogKind               = "ClusterCatalog"
+	clusterExtensionKind             = "ClusterExtension"
+	olmv1CreateOperation             = "create"
+	olmv1UpdateOperation             = "update"
+	olmv1ProgressingCondition        = "Progressing"
+	olmv1SucceededReason             = "Succeeded"
+	olmv1BlockedReason               = "Blocked"
+)
+
+var (
+	supportedOLMv1LatencyJobTypes = []config.JobType{config.CreationJob, config.PatchJob}
+	clusterCatalogGVR             = schema.GroupVersionResource{
+		Group:    "olm.operatorframework.io",
+		Version:  "v1",
+		Resource: "clustercatalogs",
+	}
+	clusterExtensionGVR = schema.GroupVersionResource{
+		Group:    "olm.operatorframework.io",
+		Version:  "v1",
+		Resource: "clusterextensions",
+	}
+	// Condition reporting each kind is ready to use
+	olmv1ReadyConditions = map[string]string{
+		clusterCatalogKind:   "Serving",
+		clusterExtensionKind: "Installed",
+	}
+)
+
+type olmv1Metric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Metadata   any       `json:"metadata,omitempty"`
+	// Creation of the object or update of its spec, e.g. a ClusterExtension version bump
+	Operation  string `json:"operation"`
+	Generation int64  `json:"generation"`
+	// Catalog image of ClusterCatalogs, package and installed bundle versions of ClusterExtensions
+	Image       string `json:"image,omitempty"`
+	Package     string `json:"package,omitempty"`
+	FromVersion string `json:"fromVersion,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Ready       bool   `json:"ready"`
+	// Milliseconds from the creation or the first observation of the update to the Serving or Installed condition, -1 when not reached
+	ReadyLatency int `json:"readyLatency"`
+	// Last failure reported in the conditions, cleared once ready
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Terminal failure, the operation won't be retried
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+type olmv1Latency struct {
+	measurements.BaseMeasurement
+	stopCh         chan struct{}
+	dynamicClient  dynamic.Interface
+	startTime      time.Time
+	rolloutTimeout time.Duration
+	// Generation and installed version of the objects existing before the job started, indexed by UID
+	baselines sync.Map
+}
+
+type olmv1Baseline struct {
+	generation int64
+	version    string
+}
+
+type olmv1LatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewOLMv1LatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return olmv1LatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (omf olmv1LatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &olmv1Latency{
+		BaseMeasurement: omf.NewBaseLatency(jobConfig, clientSet, restConfig, olmv1LatencyMeasurementName, olmv1LatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (o *olmv1Latency) setInputVars() error {
+	o.rolloutTimeout = 30 * time.Minute
+	for _, obj := range o.JobConfig.Objects {
+		if val, ok := obj.InputVars["rolloutTimeout"]; ok {
+			var err error
+			if o.rolloutTimeout, err = time.ParseDuration(fmt.Sprint(val)); err != nil {
+				return fmt.Errorf("failure parsing rolloutTimeout: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *olmv1Latency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	o.LatencyQuantiles, o.NormLatencies = nil, nil
+	o.Metrics = sync.Map{}
+	o.baselines = sync.Map{}
+	if o.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := o.setInputVars(); err != nil {
+		return err
+	}
+	o.startTime = time.Now().UTC().Truncate(time.Second)
+	o.stopCh = make(chan struct{})
+	// ClusterCatalogs and ClusterExtensions are cluster scoped, only the ones created by this benchmark are tracked
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(o.dynamicClient, 0, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = fmt.Sprintf("%s=%s", config.KubeBurnerLabelUUID, o.Uuid)
+	})
+	for kind, gvr := range map[string]schema.GroupVersionResource{clusterCatalogKind: clusterCatalogGVR, clusterExtensionKind: clusterExtensionGVR} {
+		handler := o.handler(kind)
+		factory.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: handler,
+			UpdateFunc: func(oldObj, newObj any) {
+				handler(newObj)
+			},
+		})
+	}
+	log.Infof("Starting ClusterCatalog and ClusterExtension latency watcher for job %s", o.JobConfig.Name)
+	factory.Start(o.stopCh)
+	factory.WaitForCacheSync(o.stopCh)
+	return nil
+}
+
+// handler returns the event handler tracking the objects of the given kind created or updated during the job
+func (o *olmv1Latency) handler(kind string) func(obj any) {
+	return func(obj any) {
+		u := obj.(*unstructured.Unstructured)
+		uid := string(u.GetUID())
+		now := time.Now().UTC()
+		var m olmv1Metric
+		if val, ok := o.Metrics.Load(uid); ok && val.(olmv1Metric).Generation == u.GetGeneration() {
+			m = val.(olmv1Metric)
+			if m.Ready || m.Blocked {
+				return
+			}
+		} else {
+			m = olmv1Metric{
+				Timestamp:    u.GetCreationTimestamp().UTC(),
+				MetricName:   olmv1LatencyMeasurementName,
+				Kind:         kind,
+				Name:         u.GetName(),
+				Operation:    olmv1CreateOperation,
+				Generation:   u.GetGeneration(),
+				ReadyLatency: -1,
+			}
+			if m.Timestamp.Before(o.startTime) {
+				baseline, loaded := o.baselines.LoadOrStore(uid, olmv1Baseline{generation: u.GetGeneration(), version: olmv1InstalledVersion(u)})
+				// Objects existing before the job are only tracked once their spec is updated
+				if !loaded || baseline.(olmv1Baseline).generation >= u.GetGeneration() {
+					return
+				}
+				m.Timestamp = now
+				m.Operation = olmv1UpdateOperation
+				m.FromVersion = baseline.(olmv1Baseline).version
+			}
+			m.Image, _, _ = unstructured.NestedString(u.Object, "spec", "source", "image", "ref")
+			m.Package, _, _ = unstructured.NestedString(u.Object, "spec", "source", "catalog", "packageName")
+		}
+		setOLMv1Status(&m, u, now)
+		if m.Ready {
+			log.Debugf("%s %s %s ready after %dms", m.Kind, m.Name, m.Operation, m.ReadyLatency)
+		}
+		o.Metrics.Store(uid, m)
+	}
+}
+
+// olmv1InstalledVersion returns the bundle version installed by a ClusterExtension
+func olmv1InstalledVersion(u *unstructured.Unstructured) string {
+	version, _, _ := unstructured.NestedString(u.Object, "status", "install", "bundle", "version")
+	return version
+}
+
+// setOLMv1Status updates the metric according to the object status observed at the given time. The object is ready
+// once its ready condition is true for the tracked generation and, if reported, Progressing succeeded for it
+func setOLMv1Status(m *olmv1Metric, u *unstructured.Unstructured, observed time.Time) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	var ready, progressed, progressReported bool
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		generation, _, _ := unstructured.NestedInt64(condition, "observedGeneration")
+		current := generation >= m.Generation
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		switch condition["type"] {
+		case olmv1ReadyConditions[m.Kind]:
+			ready = current && condition["status"] == "True"
+			if current && condition["status"] == "False" && reason != "" {
+				m.Reason, m.Message = reason, message
+			}
+		case olmv1ProgressingCondition:
+			progressReported = true
+			progressed = current && reason == olmv1SucceededReason
+			if current && reason != olmv1SucceededReason && reason != "" {
+				m.Reason, m.Message = reason, message
+				m.Blocked = reason == olmv1BlockedReason
+			}
+		}
+	}
+	m.Version = olmv1InstalledVersion(u)
+	if ready && (progressed || !progressReported) {
+		m.Ready = true
+		m.Blocked = false
+		m.Reason, m.Message = "", ""
+		m.ReadyLatency = int(observed.Sub(m.Timestamp).Milliseconds())
+	}
+}
+
+func (o *olmv1Latency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// Stop waits for the rollout of the tracked objects, as the patch jobs finish once the spec is updated
+func (o *olmv1Latency) Stop() error {
+	if o.JobConfig.SkipIndexing {
+		return nil
+	}
+	defer close(o.stopCh)
+	deadline := time.Now().Add(o.rolloutTimeout)
+	for pending := o.pending(); len(pending) > 0; pending = o.pending() {
+		if time.Now().After(deadline) {
+			log.Warnf("%d ClusterCatalogs and ClusterExtensions not ready after %v: %v", len(pending), o.rolloutTimeout, pending)
+			break
+		}
+		log.Debugf("Waiting for %d ClusterCatalogs and ClusterExtensions to be ready", len(pending))
+		time.Sleep(time.Second)
+	}
+	o.Metrics.Range(func(key, value any) bool {
+		m := value.(olmv1Metric)
+		if m.Blocked {
+			log.Errorf("%s %s %s blocked: %s %s", m.Kind, m.Name, m.Operation, m.Reason, m.Message)
+		}
+		return true
+	})
+	return o.StopMeasurement(o.normalizeMetrics, o.getLatency)
+}
+
+// pending returns the objects neither ready nor blocked
+func (o *olmv1Latency) pending() []string {
+	var pending []string
+	o.Metrics.Range(func(key, value any) bool {
+		m := value.(olmv1Metric)
+		if !m.Ready && !m.Blocked {
+			pending = append(pending, m.Kind+"/"+m.Name)
+		}
+		return true
+	})
+	return pending
+}
+
+func (o *olmv1Latency) normalizeMetrics() float64 {
+	o.Metrics.Range(func(key, value any) bool {
+		m := value.(olmv1Metric)
+		m.UUID = o.Uuid
+		m.JobName = o.JobConfig.Name
+		m.Metadata = o.Metadata
+		o.NormLatencies = append(o.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency reports the latencies of each kind and operation separately, e.g. ClusterExtensionUpdateLatency for upgrades
+func (o *olmv1Latency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(olmv1Metric)
+	if !m.Ready {
+		return map[string]float64{}
+	}
+	operation := "Create"
+	if m.Operation == olmv1UpdateOperation {
+		operation = "Update"
+	}
+	return map[string]float64{
+		m.Kind + operation + "Latency": float64(m.ReadyLatency),
+	}
+}
+
+func (o *olmv1Latency) IsCompatible() bool {
+	return slices.Contains(supportedOLMv1LatencyJobTypes, o.JobConfig.JobType)
+}
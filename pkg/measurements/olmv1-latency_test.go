@@ -0,0 +1,64 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestClusterExtension(version string, conditions ...any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "ce-1", "generation": int64(2)},
+		"status": map[string]any{
+			"install":    map[string]any{"bundle": map[string]any{"name": "stress-olmv1-c1.v" + version, "version": version}},
+			"conditions": conditions,
+		},
+	}}
+}
+
+func TestSetOLMv1StatusUpgrade(t *testing.T) {
+	updated := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := olmv1Metric{Timestamp: updated, Kind: clusterExtensionKind, Operation: olmv1UpdateOperation, Generation: 2, FromVersion: "1.0.0", ReadyLatency: -1}
+	// Installed is still reported for the previous generation while the new bundle is resolved
+	setOLMv1Status(&m, newTestClusterExtension("1.0.0",
+		map[string]any{"type": "Installed", "status": "True", "reason": "Succeeded", "observedGeneration": int64(1)},
+		map[string]any{"type": "Progressing", "status": "True", "reason": "Retrying", "message": "error upgrading from currently installed version \"1.0.0\": no bundles found", "observedGeneration": int64(2)},
+	), updated.Add(time.Second))
+	if m.Ready || m.Blocked || m.Reason != "Retrying" || m.Message == "" {
+		t.Errorf("expected the upgrade to be retrying, got ready %v blocked %v reason %s", m.Ready, m.Blocked, m.Reason)
+	}
+	setOLMv1Status(&m, newTestClusterExtension("1.1.0",
+		map[string]any{"type": "Installed", "status": "True", "reason": "Succeeded", "observedGeneration": int64(2)},
+		map[string]any{"type": "Progressing", "status": "True", "reason": "Succeeded", "observedGeneration": int64(2)},
+	), updated.Add(8*time.Second))
+	if !m.Ready || m.ReadyLatency != 8000 || m.Version != "1.1.0" || m.Reason != "" {
+		t.Errorf("unexpected upgrade ready %v after %dms to version %s, reason %s", m.Ready, m.ReadyLatency, m.Version, m.Reason)
+	}
+}
+
+func TestSetOLMv1StatusBlocked(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := olmv1Metric{Timestamp: created, Kind: clusterExtensionKind, Operation: olmv1CreateOperation, Generation: 2, ReadyLatency: -1}
+	setOLMv1Status(&m, newTestClusterExtension("",
+		map[string]any{"type": "Installed", "status": "False", "reason": "Failed", "observedGeneration": int64(2)},
+		map[string]any{"type": "Progressing", "status": "False", "reason": "Blocked", "message": "unsupported bundle", "observedGeneration": int64(2)},
+	), created.Add(time.Second))
+	if m.Ready || !m.Blocked || m.Reason != "Blocked" || m.Message != "unsupported bundle" {
+		t.Errorf("expected the install to be blocked, got ready %v blocked %v reason %s", m.Ready, m.Blocked, m.Reason)
+	}
+}
+
+func TestSetOLMv1StatusCatalog(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := olmv1Metric{Timestamp: created, Kind: clusterCatalogKind, Operation: olmv1CreateOperation, Generation: 1, ReadyLatency: -1}
+	catalog := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Serving", "status": "True", "reason": "Available", "observedGeneration": int64(1)},
+		}},
+	}}
+	setOLMv1Status(&m, catalog, created.Add(3*time.Second))
+	if !m.Ready || m.ReadyLatency != 3000 {
+		t.Errorf("expected the catalog to be serving after 3000ms, got ready %v after %dms", m.Ready, m.ReadyLatency)
+	}
+}
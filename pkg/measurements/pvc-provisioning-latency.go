@@ -0,0 +1,352 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	pvcProvisioningLatencyMeasurementName      = "pvcProvisioningLatencyMeasurement"
+	pvcProvisioningLatencyQuantilesMeasurement = "pvcProvisioningLatencyQuantilesMeasurement"
+	pvcProvisioningSummaryName                 = "pvcProvisioningSummary"
+	provisioningSucceededReason                = "ProvisioningSucceeded"
+	successfulAttachVolumeReason               = "SuccessfulAttachVolume"
+	storageProvisionerAnnotation               = "volume.kubernetes.io/storage-provisioner"
+)
+
+var supportedPVCProvisioningLatencyJobTypes = []config.JobType{config.CreationJob}
+
+type pvcProvisioningMetric struct {
+	Timestamp    time.Time `json:"timestamp"`
+	MetricName   string    `json:"metricName"`
+	UUID         string    `json:"uuid"`
+	JobName      string    `json:"jobName,omitempty"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Name         string    `json:"pvcName,omitempty"`
+	StorageClass string    `json:"storageClass"`
+	AccessModes  string    `json:"accessModes"`
+	Provisioner  string    `json:"provisioner,omitempty"`
+	VolumeName   string    `json:"volumeName,omitempty"`
+	PodName      string    `json:"podName,omitempty"`
+	Metadata     any       `json:"metadata,omitempty"`
+	Bound        bool      `json:"bound"`
+	// Milliseconds from the PVC creation, -1 when not observed
+	BoundLatency        int `json:"boundLatency"`
+	ProvisioningLatency int `json:"provisioningLatency"`
+	// Milliseconds from the pod scheduling to the volume attachment, and from the attachment to the pod sandbox creation
+	AttachLatency int `json:"attachLatency"`
+	MountLatency  int `json:"mountLatency"`
+	// Storage class and access mode summary fields
+	PVCs                   int `json:"pvcs,omitempty"`
+	P50BoundLatency        int `json:"p50BoundLatency,omitempty"`
+	P99BoundLatency        int `json:"p99BoundLatency,omitempty"`
+	P50ProvisioningLatency int `json:"p50ProvisioningLatency,omitempty"`
+	P99ProvisioningLatency int `json:"p99ProvisioningLatency,omitempty"`
+}
+
+type pvcProvisioningLatency struct {
+	measurements.BaseMeasurement
+	stopCh    chan struct{}
+	startTime time.Time
+	factory   informers.SharedInformerFactory
+	// Time each PVC was first observed as Bound, PVCs don't record it
+	boundTimes sync.Map
+}
+
+type pvcProvisioningLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewPVCProvisioningLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return pvcProvisioningLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (pmf pvcProvisioningLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &pvcProvisioningLatency{
+		BaseMeasurement: pmf.NewBaseLatency(jobConfig, clientSet, restConfig, pvcProvisioningLatencyMeasurementName, pvcProvisioningLatencyQuantilesMeasurement, embedCfg),
+	}
+}
+
+func (p *pvcProvisioningLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	p.LatencyQuantiles, p.NormLatencies = nil, nil
+	p.Metrics = sync.Map{}
+	p.boundTimes = sync.Map{}
+	if p.JobConfig.SkipIndexing {
+		return nil
+	}
+	p.startTime = time.Now().UTC().Truncate(time.Second)
+	p.stopCh = make(chan struct{})
+	p.factory = informers.NewSharedInformerFactoryWithOptions(p.ClientSet, 0, informers.WithNamespace(p.JobConfig.Namespace))
+	p.factory.Core().V1().PersistentVolumeClaims().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: p.handlePVC,
+		UpdateFunc: func(oldObj, newObj any) {
+			p.handlePVC(newObj)
+		},
+	})
+	// Pods and events are read from the informer caches when the job finishes
+	p.factory.Core().V1().Pods().Informer()
+	p.factory.Core().V1().Events().Informer()
+	log.Infof("Starting PVC provisioning latency watcher for job %s", p.JobConfig.Name)
+	p.factory.Start(p.stopCh)
+	p.factory.WaitForCacheSync(p.stopCh)
+	return nil
+}
+
+func (p *pvcProvisioningLatency) handlePVC(obj any) {
+	pvc := obj.(*corev1.PersistentVolumeClaim)
+	if pvc.Status.Phase == corev1.ClaimBound {
+		p.boundTimes.LoadOrStore(string(pvc.UID), time.Now().UTC())
+	}
+}
+
+func (p *pvcProvisioningLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (p *pvcProvisioningLatency) Stop() error {
+	if p.JobConfig.SkipIndexing {
+		return nil
+	}
+	defer close(p.stopCh)
+	pvcs, err := p.factory.Core().V1().PersistentVolumeClaims().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	pods, err := p.factory.Core().V1().Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	events, err := p.factory.Core().V1().Events().Lister().List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	eventTimes := firstEventTimes(events)
+	claimPods := claimFirstPods(pods, p.startTime)
+	for _, pvc := range pvcs {
+		if pvc.CreationTimestamp.UTC().Before(p.startTime) {
+			continue
+		}
+		m := newPVCProvisioningMetric(pvc, eventTimes, claimPods[pvc.Namespace+"/"+pvc.Name])
+		if bound, ok := p.boundTimes.Load(string(pvc.UID)); ok {
+			m.BoundLatency = int(bound.(time.Time).Sub(m.Timestamp).Milliseconds())
+		}
+		if !m.Bound {
+			log.Warnf("PVC %s/%s wasn't bound when the job finished", m.Namespace, m.Name)
+		}
+		p.Metrics.Store(string(pvc.UID), m)
+	}
+	p.storeSummaries()
+	return p.StopMeasurement(p.normalizeMetrics, p.getLatency)
+}
+
+// newPVCProvisioningMetric derives the provisioning, attach and mount times from the events of the PVC and of the first pod using it
+func newPVCProvisioningMetric(pvc *corev1.PersistentVolumeClaim, eventTimes map[string]time.Time, pod *corev1.Pod) pvcProvisioningMetric {
+	created := pvc.CreationTimestamp.UTC()
+	m := pvcProvisioningMetric{
+		Timestamp:           created,
+		MetricName:          pvcProvisioningLatencyMeasurementName,
+		Namespace:           pvc.Namespace,
+		Name:                pvc.Name,
+		Provisioner:         pvc.Annotations[storageProvisionerAnnotation],
+		VolumeName:          pvc.Spec.VolumeName,
+		Bound:               pvc.Status.Phase == corev1.ClaimBound,
+		BoundLatency:        -1,
+		ProvisioningLatency: -1,
+		AttachLatency:       -1,
+		MountLatency:        -1,
+	}
+	if pvc.Spec.StorageClassName != nil {
+		m.StorageClass = *pvc.Spec.StorageClassName
+	}
+	accessModes := make([]string, 0, len(pvc.Spec.AccessModes))
+	for _, accessMode := range pvc.Spec.AccessModes {
+		accessModes = append(accessModes, string(accessMode))
+	}
+	m.AccessModes = strings.Join(accessModes, ",")
+	if provisioned, ok := eventTimes[eventKey("PersistentVolumeClaim", pvc.Namespace, pvc.Name, provisioningSucceededReason)]; ok {
+		m.ProvisioningLatency = int(provisioned.Sub(created).Milliseconds())
+	}
+	if pod == nil {
+		return m
+	}
+	m.PodName = pod.Name
+	scheduled, ok := podConditionTime(pod, corev1.PodScheduled)
+	if !ok {
+		return m
+	}
+	// Volumes not requiring attachment are ready to be mounted once the pod is scheduled
+	mountStart := scheduled
+	if attached, ok := eventTimes[eventKey("Pod", pod.Namespace, pod.Name, successfulAttachVolumeReason)]; ok {
+		m.AttachLatency = int(attached.Sub(scheduled).Milliseconds())
+		mountStart = attached
+	}
+	// The kubelet creates the pod sandbox once all its volumes are mounted
+	if sandboxReady, ok := podConditionTime(pod, corev1.PodReadyToStartContainers); ok {
+		m.MountLatency = int(sandboxReady.Sub(mountStart).Milliseconds())
+	}
+	return m
+}
+
+func eventKey(kind, namespace, name, reason string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", kind, namespace, name, reason)
+}
+
+// firstEventTimes returns the earliest time each reason was reported for each object
+func firstEventTimes(events []*corev1.Event) map[string]time.Time {
+	eventTimes := make(map[string]time.Time)
+	for _, event := range events {
+		timestamp := event.EventTime.Time
+		if timestamp.IsZero() {
+			timestamp = event.FirstTimestamp.Time
+		}
+		if timestamp.IsZero() {
+			continue
+		}
+		key := eventKey(event.InvolvedObject.Kind, event.InvolvedObject.Namespace, event.InvolvedObject.Name, event.Reason)
+		if previous, ok := eventTimes[key]; !ok || timestamp.Before(previous) {
+			eventTimes[key] = timestamp.UTC()
+		}
+	}
+	return eventTimes
+}
+
+// claimFirstPods returns the first pod created after the given time mounting each claim
+func claimFirstPods(pods []*corev1.Pod, since time.Time) map[string]*corev1.Pod {
+	claimPods := make(map[string]*corev1.Pod)
+	for _, pod := range pods {
+		if pod.CreationTimestamp.UTC().Before(since) {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			key := pod.Namespace + "/" + volume.PersistentVolumeClaim.ClaimName
+			if previous, ok := claimPods[key]; !ok || pod.CreationTimestamp.Before(&previous.CreationTimestamp) {
+				claimPods[key] = pod
+			}
+		}
+	}
+	return claimPods
+}
+
+func podConditionTime(pod *corev1.Pod, conditionType corev1.PodConditionType) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// storeSummaries adds a document per storage class and access modes with the binding and provisioning percentiles
+func (p *pvcProvisioningLatency) storeSummaries() {
+	type group struct {
+		storageClass, accessModes string
+		bound, provisioned        []int
+		pvcs                      int
+	}
+	groups := make(map[string]*group)
+	p.Metrics.Range(func(key, value any) bool {
+		m := value.(pvcProvisioningMetric)
+		groupKey := m.StorageClass + "/" + m.AccessModes
+		g, ok := groups[groupKey]
+		if !ok {
+			g = &group{storageClass: m.StorageClass, accessModes: m.AccessModes}
+			groups[groupKey] = g
+		}
+		g.pvcs++
+		if m.BoundLatency >= 0 {
+			g.bound = append(g.bound, m.BoundLatency)
+		}
+		if m.ProvisioningLatency >= 0 {
+			g.provisioned = append(g.provisioned, m.ProvisioningLatency)
+		}
+		return true
+	})
+	for groupKey, g := range groups {
+		slices.Sort(g.bound)
+		slices.Sort(g.provisioned)
+		summary := pvcProvisioningMetric{
+			Timestamp:              time.Now().UTC(),
+			MetricName:             pvcProvisioningSummaryName,
+			StorageClass:           g.storageClass,
+			AccessModes:            g.accessModes,
+			PVCs:                   g.pvcs,
+			P50BoundLatency:        percentile(g.bound, 50),
+			P99BoundLatency:        percentile(g.bound, 99),
+			P50ProvisioningLatency: percentile(g.provisioned, 50),
+			P99ProvisioningLatency: percentile(g.provisioned, 99),
+		}
+		log.Infof("PVCs %s %s: %d, bound P99 %dms, provisioned P99 %dms", g.storageClass, g.accessModes, g.pvcs, summary.P99BoundLatency, summary.P99ProvisioningLatency)
+		p.Metrics.Store(pvcProvisioningSummaryName+"/"+groupKey, summary)
+	}
+}
+
+func (p *pvcProvisioningLatency) normalizeMetrics() float64 {
+	p.Metrics.Range(func(key, value any) bool {
+		m := value.(pvcProvisioningMetric)
+		m.UUID = p.Uuid
+		m.JobName = p.JobConfig.Name
+		m.Metadata = p.Metadata
+		p.NormLatencies = append(p.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency skips the milestones not observed, e.g. attachment for drivers not requiring it
+func (p *pvcProvisioningLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(pvcProvisioningMetric)
+	latencies := map[string]float64{}
+	if m.MetricName != pvcProvisioningLatencyMeasurementName || !m.Bound {
+		return latencies
+	}
+	for name, latency := range map[string]int{
+		"BoundLatency":        m.BoundLatency,
+		"ProvisioningLatency": m.ProvisioningLatency,
+		"AttachLatency":       m.AttachLatency,
+		"MountLatency":        m.MountLatency,
+	} {
+		if latency >= 0 {
+			latencies[name] = float64(latency)
+		}
+	}
+	return latencies
+}
+
+func (p *pvcProvisioningLatency) IsCompatible() bool {
+	return slices.Contains(supportedPVCProvisioningLatencyJobTypes, p.JobConfig.JobType)
+}
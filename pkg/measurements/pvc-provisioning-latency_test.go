@@ -0,0 +1,60 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewPVCProvisioningMetric(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	storageClass := "csi-rbd"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pvc-1",
+			Namespace:         "pvc-density",
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations:       map[string]string{storageProvisionerAnnotation: "rbd.csi.ceph.com"},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	pods := []*corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "pvc-density", CreationTimestamp: metav1.NewTime(created)},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-1"}},
+		}}},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(3 * time.Second))},
+			{Type: corev1.PodReadyToStartContainers, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(9 * time.Second))},
+		}},
+	}}
+	events := []*corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: "pvc-density", Name: "pvc-1"},
+			Reason:         provisioningSucceededReason,
+			FirstTimestamp: metav1.NewTime(created.Add(2 * time.Second)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "pvc-density", Name: "pod-1"},
+			Reason:         successfulAttachVolumeReason,
+			EventTime:      metav1.NewMicroTime(created.Add(5 * time.Second)),
+		},
+	}
+	m := newPVCProvisioningMetric(pvc, firstEventTimes(events), claimFirstPods(pods, created)["pvc-density/pvc-1"])
+	if m.StorageClass != storageClass || m.AccessModes != "ReadWriteOnce" || m.Provisioner != "rbd.csi.ceph.com" {
+		t.Errorf("unexpected claim details %+v", m)
+	}
+	if m.ProvisioningLatency != 2000 || m.AttachLatency != 2000 || m.MountLatency != 4000 {
+		t.Errorf("unexpected latencies provisioning %dms attach %dms mount %dms", m.ProvisioningLatency, m.AttachLatency, m.MountLatency)
+	}
+	if m.PodName != "pod-1" || !m.Bound {
+		t.Errorf("expected bound claim mounted by pod-1, got %+v", m)
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
+)
+
+const (
+	vmBootMilestonesMeasurementName      = "vmBootMilestonesMeasurement"
+	vmBootMilestonesQuantilesMeasurement = "vmBootMilestonesQuantilesMeasurement"
+	// First cloud-init stage, reported in the serial console as "Cloud-init v. 24.1 running 'init-local' at ... Up 5.50 seconds."
+	cloudInitFirstStage = "init-local"
+)
+
+var (
+	supportedVMBootMilestonesJobTypes = []config.JobType{config.CreationJob}
+	// Kernel messages are prefixed with the guest uptime, e.g. "[    2.345678] systemd[1]: ..."
+	kernelLogUptimeRegex = regexp.MustCompile(`^\[\s*(\d+\.\d+)\]`)
+	// Cloud-init prints a line with the guest uptime when every stage starts and when it finishes
+	cloudInitStageRegex = regexp.MustCompile(`Cloud-init v\. \S+ (?:running '([^']+)'|(finished)) at .*Up (\d+(?:\.\d+)?) seconds`)
+)
+
+type vmBootMilestonesMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"vmiName"`
+	NodeName   string    `json:"nodeName,omitempty"`
+	Metadata   any       `json:"metadata,omitempty"`
+	// Guest boot milestones, relative to the VMI Running phase, -1 when not observed in the serial console
+	KernelStartLatency    int `json:"kernelStartLatency"`
+	CloudInitStartLatency int `json:"cloudInitStartLatency"`
+	CloudInitFinalLatency int `json:"cloudInitFinalLatency"`
+}
+
+// guestConsoleSample is a serial console line carrying the guest uptime, along with the local time it was read
+type guestConsoleSample struct {
+	received time.Time
+	uptime   time.Duration
+	// cloud-init stage started, or finished, reported by the line
+	cloudInitStage string
+}
+
+type vmBootMilestones struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	vmClient      *vmops.Client
+	startTime     time.Time
+	// VirtualMachines created by the job, VMIs don't inherit their kube-burner labels
+	vmLister cache.GenericLister
+	// time to wait for cloud-init to finish once the VMI is Running
+	timeout time.Duration
+	// VMI UIDs already evaluated
+	seen      sync.Map
+	consoleWg sync.WaitGroup
+}
+
+type vmBootMilestonesMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVMBootMilestonesMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmBootMilestonesMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vmf vmBootMilestonesMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmBootMilestones{
+		BaseMeasurement: vmf.NewBaseLatency(jobConfig, clientSet, restConfig, vmBootMilestonesMeasurementName, vmBootMilestonesQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (v *vmBootMilestones) setInputVars() {
+	v.timeout = 15 * time.Minute
+	for _, obj := range v.JobConfig.Objects {
+		if val, ok := obj.InputVars["bootMilestonesTimeout"]; ok {
+			timeout, err := time.ParseDuration(fmt.Sprint(val))
+			if err != nil {
+				log.Errorf("Failure parsing bootMilestonesTimeout: %v", err)
+				continue
+			}
+			v.timeout = timeout
+		}
+	}
+}
+
+func (v *vmBootMilestones) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	v.seen = sync.Map{}
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	v.setInputVars()
+	var err error
+	if v.vmClient, err = vmops.NewClient(v.RestConfig); err != nil {
+		return err
+	}
+	v.startTime = time.Now().UTC()
+	v.stopCh = make(chan struct{})
+	vmFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = labels.Set{config.KubeBurnerLabelUUID: v.Uuid, config.KubeBurnerLabelJob: v.JobConfig.Name}.String()
+	})
+	v.vmLister = vmFactory.ForResource(vmGVR).Lister()
+	vmFactory.Start(v.stopCh)
+	vmFactory.WaitForCacheSync(v.stopCh)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, metav1.NamespaceAll, nil)
+	vmiInformer := factory.ForResource(vmiGVR).Informer()
+	vmiInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: v.handleVMI,
+		UpdateFunc: func(oldObj, newObj any) {
+			v.handleVMI(newObj)
+		},
+	})
+	log.Infof("Starting VM boot milestones watcher for job %s", v.JobConfig.Name)
+	factory.Start(v.stopCh)
+	factory.WaitForCacheSync(v.stopCh)
+	return nil
+}
+
+// handleVMI attaches to the serial console of the VMI once it reaches the Running phase
+func (v *vmBootMilestones) handleVMI(obj any) {
+	observedTime := time.Now().UTC()
+	vmi := obj.(*unstructured.Unstructured)
+	transitionTime, running := getVMIRunningTime(vmi)
+	if !running {
+		return
+	}
+	// VMIs whose VirtualMachine isn't cached yet are evaluated again on their next update
+	if _, err := v.vmLister.ByNamespace(vmi.GetNamespace()).Get(vmi.GetName()); err != nil {
+		return
+	}
+	if _, loaded := v.seen.LoadOrStore(vmi.GetUID(), true); loaded {
+		return
+	}
+	// VMIs that were already running before the job started
+	if transitionTime.Before(v.startTime.Truncate(time.Second)) {
+		return
+	}
+	nodeName, _, _ := unstructured.NestedString(vmi.Object, "status", "nodeName")
+	m := vmBootMilestonesMetric{
+		Timestamp:             vmiRunningTime(transitionTime, observedTime),
+		MetricName:            vmBootMilestonesMeasurementName,
+		UUID:                  v.Uuid,
+		JobName:               v.JobConfig.Name,
+		Namespace:             vmi.GetNamespace(),
+		Name:                  vmi.GetName(),
+		NodeName:              nodeName,
+		Metadata:              v.Metadata,
+		KernelStartLatency:    -1,
+		CloudInitStartLatency: -1,
+		CloudInitFinalLatency: -1,
+	}
+	v.Metrics.Store(string(vmi.GetUID()), m)
+	v.consoleWg.Add(1)
+	go func() {
+		defer v.consoleWg.Done()
+		samples, err := v.readConsole(m.Namespace, m.Name, m.Timestamp.Add(v.timeout))
+		if err != nil {
+			log.Warnf("Failed to read the serial console of VMI %s/%s: %v", m.Namespace, m.Name, err)
+		}
+		setBootMilestones(&m, samples)
+		log.Debugf("VMI %s/%s kernel started after %dms and cloud-init finished after %dms", m.Namespace, m.Name, m.KernelStartLatency, m.CloudInitFinalLatency)
+		v.Metrics.Store(string(vmi.GetUID()), m)
+	}()
+}
+
+// readConsole collects the serial console lines carrying the guest uptime until cloud-init finishes or the deadline is reached
+func (v *vmBootMilestones) readConsole(namespace, name string, deadline time.Time) ([]guestConsoleSample, error) {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	conn, err := v.vmClient.ConsoleVMI(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+	var samples []guestConsoleSample
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		sample, ok := parseGuestConsoleLine(scanner.Text(), time.Now().UTC())
+		if !ok {
+			continue
+		}
+		samples = append(samples, sample)
+		if sample.cloudInitStage == "finished" {
+			return samples, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return samples, err
+	}
+	return samples, nil
+}
+
+// parseGuestConsoleLine extracts the guest uptime of kernel messages and cloud-init stage lines
+func parseGuestConsoleLine(line string, received time.Time) (guestConsoleSample, bool) {
+	sample := guestConsoleSample{received: received}
+	if match := cloudInitStageRegex.FindStringSubmatch(line); match != nil {
+		sample.cloudInitStage = match[1] + match[2]
+		uptime, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			return sample, false
+		}
+		sample.uptime = time.Duration(uptime * float64(time.Second))
+		return sample, true
+	}
+	if match := kernelLogUptimeRegex.FindStringSubmatch(line); match != nil {
+		uptime, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return sample, false
+		}
+		sample.uptime = time.Duration(uptime * float64(time.Second))
+		return sample, true
+	}
+	return sample, false
+}
+
+// setBootMilestones converts the guest uptimes into latencies from the VMI Running phase. The kernel start is the
+// earliest local time a line was read minus the guest uptime it reports, as console lines are only ever delayed
+func setBootMilestones(m *vmBootMilestonesMetric, samples []guestConsoleSample) {
+	if len(samples) == 0 {
+		return
+	}
+	kernelStart := samples[0].received.Add(-samples[0].uptime)
+	for _, s := range samples[1:] {
+		if bootTime := s.received.Add(-s.uptime); bootTime.Before(kernelStart) {
+			kernelStart = bootTime
+		}
+	}
+	latency := func(uptime time.Duration) int {
+		// Guests booting before the Running phase is observed report small negative values
+		return max(int(kernelStart.Add(uptime).Sub(m.Timestamp).Milliseconds()), 0)
+	}
+	m.KernelStartLatency = latency(0)
+	for _, s := range samples {
+		switch s.cloudInitStage {
+		case cloudInitFirstStage:
+			m.CloudInitStartLatency = latency(s.uptime)
+		case "finished":
+			m.CloudInitFinalLatency = latency(s.uptime)
+		}
+	}
+}
+
+func (v *vmBootMilestones) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (v *vmBootMilestones) Stop() error {
+	if v.JobConfig.SkipIndexing || v.stopCh == nil {
+		return nil
+	}
+	close(v.stopCh)
+	log.Infof("Waiting for the VM boot milestones of job %s", v.JobConfig.Name)
+	v.consoleWg.Wait()
+	return v.StopMeasurement(v.normalizeMetrics, v.getLatency)
+}
+
+func (v *vmBootMilestones) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmBootMilestonesMetric)
+		if m.CloudInitFinalLatency < 0 {
+			log.Warnf("Cloud-init completion of VMI %s/%s not observed in its serial console", m.Namespace, m.Name)
+		}
+		v.NormLatencies = append(v.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency only reports the milestones observed in the serial console
+func (v *vmBootMilestones) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(vmBootMilestonesMetric)
+	latencies := map[string]float64{}
+	if m.KernelStartLatency >= 0 {
+		latencies["KernelStartLatency"] = float64(m.KernelStartLatency)
+	}
+	if m.CloudInitStartLatency >= 0 {
+		latencies["CloudInitStartLatency"] = float64(m.CloudInitStartLatency)
+	}
+	if m.CloudInitFinalLatency >= 0 {
+		latencies["CloudInitFinalLatency"] = float64(m.CloudInitFinalLatency)
+	}
+	return latencies
+}
+
+func (v *vmBootMilestones) IsCompatible() bool {
+	return slices.Contains(supportedVMBootMilestonesJobTypes, v.JobConfig.JobType)
+}
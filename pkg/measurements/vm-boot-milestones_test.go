@@ -0,0 +1,80 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGuestConsoleLine(t *testing.T) {
+	received := time.Date(2026, 1, 1, 0, 0, 10, 0, time.UTC)
+	for _, tc := range []struct {
+		name   string
+		line   string
+		ok     bool
+		uptime time.Duration
+		stage  string
+	}{
+		{
+			name:   "kernel message",
+			line:   "[    2.345678] systemd[1]: Detected virtualization kvm.",
+			ok:     true,
+			uptime: 2345678 * time.Microsecond,
+		},
+		{
+			name:   "cloud-init stage",
+			line:   "[   12.100000] cloud-init[812]: Cloud-init v. 24.1.4 running 'init-local' at Thu, 01 Jan 2026 00:00:05 +0000. Up 5.50 seconds.",
+			ok:     true,
+			uptime: 5500 * time.Millisecond,
+			stage:  "init-local",
+		},
+		{
+			name:   "cloud-init finished",
+			line:   "Cloud-init v. 24.1.4 finished at Thu, 01 Jan 2026 00:00:20 +0000. Datasource DataSourceNoCloud.  Up 20.75 seconds",
+			ok:     true,
+			uptime: 20750 * time.Millisecond,
+			stage:  "finished",
+		},
+		{
+			name: "login prompt",
+			line: "vm-1 login:",
+		},
+	} {
+		sample, ok := parseGuestConsoleLine(tc.line, received)
+		if ok != tc.ok {
+			t.Errorf("%s: expected parsed %t, got %t", tc.name, tc.ok, ok)
+			continue
+		}
+		if ok && (sample.uptime != tc.uptime || sample.cloudInitStage != tc.stage || !sample.received.Equal(received)) {
+			t.Errorf("%s: expected uptime %v and stage %q, got %+v", tc.name, tc.uptime, tc.stage, sample)
+		}
+	}
+}
+
+func TestSetBootMilestones(t *testing.T) {
+	running := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newMetric := func() vmBootMilestonesMetric {
+		return vmBootMilestonesMetric{Timestamp: running, KernelStartLatency: -1, CloudInitStartLatency: -1, CloudInitFinalLatency: -1}
+	}
+	m := newMetric()
+	setBootMilestones(&m, []guestConsoleSample{
+		// Delayed console output must not move the kernel start later
+		{received: running.Add(4 * time.Second), uptime: time.Second},
+		{received: running.Add(3 * time.Second), uptime: 2 * time.Second},
+		{received: running.Add(8 * time.Second), uptime: 6 * time.Second, cloudInitStage: "init-local"},
+		{received: running.Add(25 * time.Second), uptime: 20 * time.Second, cloudInitStage: "finished"},
+	})
+	if m.KernelStartLatency != 1000 || m.CloudInitStartLatency != 7000 || m.CloudInitFinalLatency != 21000 {
+		t.Errorf("unexpected boot milestones %+v", m)
+	}
+	// Cloud-init never reported anything in the serial console
+	m = newMetric()
+	setBootMilestones(&m, []guestConsoleSample{{received: running.Add(500 * time.Millisecond), uptime: time.Second}})
+	if m.KernelStartLatency != 0 || m.CloudInitStartLatency != -1 || m.CloudInitFinalLatency != -1 {
+		t.Errorf("expected only the kernel start, got %+v", m)
+	}
+	m = newMetric()
+	setBootMilestones(&m, nil)
+	if m.KernelStartLatency != -1 {
+		t.Errorf("expected no boot milestones, got %+v", m)
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
+)
+
+const (
+	vmFioMeasurementName      = "vmFioMeasurement"
+	vmFioQuantilesMeasurement = "vmFioQuantilesMeasurement"
+)
+
+var supportedVMFioJobTypes = []config.JobType{config.CreationJob}
+
+type vmFioMetric struct {
+	Timestamp    time.Time `json:"timestamp"`
+	MetricName   string    `json:"metricName"`
+	UUID         string    `json:"uuid"`
+	JobName      string    `json:"jobName,omitempty"`
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"vmName"`
+	NodeName     string    `json:"nodeName,omitempty"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	Iteration    string    `json:"iteration,omitempty"`
+	Metadata     any       `json:"metadata,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	fioResult
+}
+
+// fioResult holds the aggregated results of the fio jobs, bandwidth in KiB/s and completion latencies in ms
+type fioResult struct {
+	ReadIOPS        float64 `json:"readIOPS"`
+	ReadBandwidth   float64 `json:"readBandwidth"`
+	ReadClatP50     float64 `json:"readClatP50"`
+	ReadClatP99     float64 `json:"readClatP99"`
+	ReadClatP999    float64 `json:"readClatP999"`
+	WriteIOPS       float64 `json:"writeIOPS"`
+	WriteBandwidth  float64 `json:"writeBandwidth"`
+	WriteClatP50    float64 `json:"writeClatP50"`
+	WriteClatP99    float64 `json:"writeClatP99"`
+	WriteClatP999   float64 `json:"writeClatP999"`
+	ReadOperations  int64   `json:"readOperations"`
+	WriteOperations int64   `json:"writeOperations"`
+}
+
+// fioOutput is the subset of the fio JSON output read by the measurement
+type fioOutput struct {
+	Jobs []struct {
+		Read  fioDirection `json:"read"`
+		Write fioDirection `json:"write"`
+	} `json:"jobs"`
+}
+
+type fioDirection struct {
+	IOPS    float64 `json:"iops"`
+	BW      float64 `json:"bw"`
+	TotalIO int64   `json:"total_ios"`
+	ClatNs  struct {
+		Percentile map[string]float64 `json:"percentile"`
+	} `json:"clat_ns"`
+}
+
+// vmFioConfig holds the input variables selecting the VMs and describing the fio run
+type vmFioConfig struct {
+	vms            int
+	labelSelector  string
+	target         string
+	args           string
+	installCommand string
+	privateKey     string
+	remoteUser     string
+	useVirtctl     bool
+	timeout        time.Duration
+	storageClass   string
+	iteration      string
+}
+
+type vmFio struct {
+	measurements.BaseMeasurement
+	dynamicClient dynamic.Interface
+	cfg           vmFioConfig
+	vmClient      *vmops.Client
+}
+
+type vmFioMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVMFioMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmFioMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vff vmFioMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmFio{
+		BaseMeasurement: vff.NewBaseLatency(jobConfig, clientSet, restConfig, vmFioMeasurementName, vmFioQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (v *vmFio) setInputVars() error {
+	v.cfg = vmFioConfig{
+		remoteUser:     "fedora",
+		target:         "/var/tmp/kube-burner-fio",
+		args:           "--rw=randrw --bs=4k --iodepth=16 --ioengine=libaio --direct=1 --size=256M --runtime=60s --time_based",
+		installCommand: "command -v fio || sudo dnf install -y -q fio",
+		timeout:        15 * time.Minute,
+		labelSelector:  fmt.Sprintf("%s=%s,%s=%s", config.KubeBurnerLabelUUID, v.Uuid, config.KubeBurnerLabelJob, v.JobConfig.Name),
+	}
+	for _, obj := range v.JobConfig.Objects {
+		for key, val := range obj.InputVars {
+			var err error
+			switch key {
+			case "fioVMs":
+				_, err = fmt.Sscan(fmt.Sprint(val), &v.cfg.vms)
+			case "fioLabelSelector":
+				v.cfg.labelSelector = fmt.Sprint(val)
+			case "fioTarget":
+				v.cfg.target = fmt.Sprint(val)
+			case "fioArgs":
+				v.cfg.args = fmt.Sprint(val)
+			case "fioInstallCommand":
+				v.cfg.installCommand = fmt.Sprint(val)
+			case "fioTimeout":
+				v.cfg.timeout, err = time.ParseDuration(fmt.Sprint(val))
+			case "privateKey":
+				v.cfg.privateKey = fmt.Sprint(val)
+			case "remoteUser":
+				v.cfg.remoteUser = fmt.Sprint(val)
+			case "useVirtctl":
+				v.cfg.useVirtctl = fmt.Sprint(val) == "true"
+			case "storageClassName":
+				v.cfg.storageClass = fmt.Sprint(val)
+			case "counter":
+				v.cfg.iteration = fmt.Sprint(val)
+			}
+			if err != nil {
+				return fmt.Errorf("failure parsing %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *vmFio) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := v.setInputVars(); err != nil {
+		return err
+	}
+	if v.cfg.vms <= 0 || v.cfg.privateKey == "" {
+		log.Debugf("No fioVMs or privateKey input variables found in job %s, skipping fio", v.JobConfig.Name)
+		v.cfg.vms = 0
+		return nil
+	}
+	var err error
+	if v.vmClient, err = vmops.NewClient(v.RestConfig); err != nil {
+		return err
+	}
+	v.vmClient.UseVirtctl = v.cfg.useVirtctl
+	return nil
+}
+
+func (v *vmFio) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// Stop runs fio concurrently in the selected VMs, which are running by then
+func (v *vmFio) Stop() error {
+	if v.JobConfig.SkipIndexing || v.cfg.vms == 0 {
+		return nil
+	}
+	vms, err := v.dynamicClient.Resource(vmGVR).Namespace(v.JobConfig.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: v.cfg.labelSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the VirtualMachines of job %s: %w", v.JobConfig.Name, err)
+	}
+	names := make([]string, 0, len(vms.Items))
+	for _, vm := range vms.Items {
+		names = append(names, vm.GetName())
+	}
+	slices.Sort(names)
+	names = names[:min(v.cfg.vms, len(names))]
+	log.Infof("Running fio in %d VMs of job %s", len(names), v.JobConfig.Name)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Go(func() {
+			m := v.runFio(v.JobConfig.Namespace, name)
+			v.Metrics.Store(name, m)
+		})
+	}
+	wg.Wait()
+	var failed []string
+	v.Metrics.Range(func(key, value any) bool {
+		if m := value.(vmFioMetric); m.Error != "" {
+			failed = append(failed, m.Namespace+"/"+m.Name)
+		}
+		return true
+	})
+	if err := v.StopMeasurement(v.normalizeMetrics, v.getLatency); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("fio failed in %d VMs: %v", len(failed), failed)
+	}
+	return nil
+}
+
+// runFio waits for the guest to be reachable, installs fio when missing and runs it
+func (v *vmFio) runFio(namespace, name string) vmFioMetric {
+	m := vmFioMetric{
+		Timestamp:    time.Now().UTC(),
+		MetricName:   vmFioMeasurementName,
+		UUID:         v.Uuid,
+		JobName:      v.JobConfig.Name,
+		Namespace:    namespace,
+		Name:         name,
+		StorageClass: v.cfg.storageClass,
+		Iteration:    v.cfg.iteration,
+		Metadata:     v.Metadata,
+	}
+	if vmi, err := v.dynamicClient.Resource(vmiGVR).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+		m.NodeName, _, _ = unstructured.NestedString(vmi.Object, "status", "nodeName")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), v.cfg.timeout)
+	defer cancel()
+	var err error
+	for {
+		if _, err = v.vmClient.RunCommand(ctx, namespace, name, v.cfg.remoteUser, v.cfg.privateKey, "true"); err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(5 * time.Second)
+	}
+	if err == nil && v.cfg.installCommand != "" {
+		if _, err = v.vmClient.RunCommand(ctx, namespace, name, v.cfg.remoteUser, v.cfg.privateKey, v.cfg.installCommand); err != nil {
+			err = fmt.Errorf("failed to install fio: %w", err)
+		}
+	}
+	if err == nil {
+		var output string
+		command := fmt.Sprintf("sudo fio --name=kube-burner --filename=%s --output-format=json %s", v.cfg.target, v.cfg.args)
+		if output, err = v.vmClient.RunCommand(ctx, namespace, name, v.cfg.remoteUser, v.cfg.privateKey, command); err == nil {
+			m.fioResult, err = parseFioOutput(output)
+		}
+	}
+	if err != nil {
+		m.Error = err.Error()
+		log.Warnf("fio failed in VM %s/%s: %v", namespace, name, err)
+	}
+	return m
+}
+
+// parseFioOutput aggregates the jobs of the fio JSON output, summing IOPS and bandwidth and keeping the highest percentiles
+func parseFioOutput(output string) (fioResult, error) {
+	var result fioResult
+	// fio may print warnings before the JSON document
+	start := strings.Index(output, "{")
+	if start < 0 {
+		return result, fmt.Errorf("no JSON document in the fio output: %q", strings.TrimSpace(output))
+	}
+	var out fioOutput
+	if err := json.Unmarshal([]byte(output[start:]), &out); err != nil {
+		return result, fmt.Errorf("failed to parse the fio output: %w", err)
+	}
+	if len(out.Jobs) == 0 {
+		return result, fmt.Errorf("no jobs in the fio output")
+	}
+	for _, job := range out.Jobs {
+		result.ReadIOPS += job.Read.IOPS
+		result.ReadBandwidth += job.Read.BW
+		result.ReadOperations += job.Read.TotalIO
+		result.WriteIOPS += job.Write.IOPS
+		result.WriteBandwidth += job.Write.BW
+		result.WriteOperations += job.Write.TotalIO
+		result.ReadClatP50 = max(result.ReadClatP50, fioPercentile(job.Read, "50.000000"))
+		result.ReadClatP99 = max(result.ReadClatP99, fioPercentile(job.Read, "99.000000"))
+		result.ReadClatP999 = max(result.ReadClatP999, fioPercentile(job.Read, "99.900000"))
+		result.WriteClatP50 = max(result.WriteClatP50, fioPercentile(job.Write, "50.000000"))
+		result.WriteClatP99 = max(result.WriteClatP99, fioPercentile(job.Write, "99.000000"))
+		result.WriteClatP999 = max(result.WriteClatP999, fioPercentile(job.Write, "99.900000"))
+	}
+	return result, nil
+}
+
+// fioPercentile returns a completion latency percentile in ms
+func fioPercentile(d fioDirection, percentile string) float64 {
+	return d.ClatNs.Percentile[percentile] / float64(time.Millisecond)
+}
+
+func (v *vmFio) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		v.NormLatencies = append(v.NormLatencies, value.(vmFioMetric))
+		return true
+	})
+	return 0
+}
+
+// getLatency reports the completion latencies of the directions fio exercised, VMs where fio failed are excluded
+func (v *vmFio) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(vmFioMetric)
+	latencies := map[string]float64{}
+	if m.Error != "" {
+		return latencies
+	}
+	if m.ReadOperations > 0 {
+		latencies["ReadClatP50"] = m.ReadClatP50
+		latencies["ReadClatP99"] = m.ReadClatP99
+	}
+	if m.WriteOperations > 0 {
+		latencies["WriteClatP50"] = m.WriteClatP50
+		latencies["WriteClatP99"] = m.WriteClatP99
+	}
+	return latencies
+}
+
+func (v *vmFio) IsCompatible() bool {
+	return slices.Contains(supportedVMFioJobTypes, v.JobConfig.JobType)
+}
@@ -0,0 +1,50 @@
+package measurements
+
+import (
+	"testing"
+)
+
+func TestParseFioOutput(t *testing.T) {
+	output := `fio: note: both iodepth >= 1 and synchronous I/O engine are selected
+{
+  "fio version" : "fio-3.36",
+  "jobs" : [
+    {
+      "jobname" : "kube-burner",
+      "read" : {"iops" : 1200.5, "bw" : 4802, "total_ios" : 72030, "clat_ns" : {"percentile" : {"50.000000" : 1500000, "99.000000" : 8000000, "99.900000" : 12000000}}},
+      "write" : {"iops" : 1199.5, "bw" : 4798, "total_ios" : 71970, "clat_ns" : {"percentile" : {"50.000000" : 2000000, "99.000000" : 9000000, "99.900000" : 15000000}}}
+    },
+    {
+      "jobname" : "kube-burner",
+      "read" : {"iops" : 800, "bw" : 3200, "total_ios" : 48000, "clat_ns" : {"percentile" : {"50.000000" : 1000000, "99.000000" : 10000000, "99.900000" : 11000000}}},
+      "write" : {"iops" : 0, "bw" : 0, "total_ios" : 0}
+    }
+  ]
+}`
+	result, err := parseFioOutput(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := fioResult{
+		ReadIOPS:        2000.5,
+		ReadBandwidth:   8002,
+		ReadClatP50:     1.5,
+		ReadClatP99:     10,
+		ReadClatP999:    12,
+		WriteIOPS:       1199.5,
+		WriteBandwidth:  4798,
+		WriteClatP50:    2,
+		WriteClatP99:    9,
+		WriteClatP999:   15,
+		ReadOperations:  120030,
+		WriteOperations: 71970,
+	}
+	if result != expected {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+	for _, invalid := range []string{"", "fio: command not found", `{"jobs": []}`} {
+		if _, err := parseFioOutput(invalid); err == nil {
+			t.Errorf("expected an error parsing %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
+)
+
+const (
+	vmGuestReadyMeasurementName      = "vmGuestReadyMeasurement"
+	vmGuestReadyQuantilesMeasurement = "vmGuestReadyQuantilesMeasurement"
+	vmiRunningPhase                  = "Running"
+	cloudInitDoneStatus              = "done"
+	cloudInitErrorStatus             = "error"
+	// Maximum delay between the Running phase transition, truncated to the second, and the informer observing it
+	vmiRunningObservationSkew = 2 * time.Second
+)
+
+var (
+	supportedVMGuestReadyJobTypes = []config.JobType{config.CreationJob, config.KubeVirtJob}
+	vmiGVR                        = schema.GroupVersionResource{
+		Group:    "kubevirt.io",
+		Version:  "v1",
+		Resource: "virtualmachineinstances",
+	}
+	vmGVR = schema.GroupVersionResource{
+		Group:    "kubevirt.io",
+		Version:  "v1",
+		Resource: "virtualmachines",
+	}
+)
+
+type vmGuestReadyMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"vmiName"`
+	NodeName   string    `json:"nodeName,omitempty"`
+	Metadata   any       `json:"metadata,omitempty"`
+	// Time from the VMI Running phase to the first successful SSH command, -1 when never reached
+	SSHReadyLatency int `json:"sshReadyLatency"`
+	// Time from the VMI Running phase to cloud-init completion, -1 when not checked or never completed
+	CloudInitLatency int    `json:"cloudInitLatency"`
+	CloudInitStatus  string `json:"cloudInitStatus,omitempty"`
+	SSHAttempts      int    `json:"sshAttempts"`
+}
+
+// vmGuestReadyConfig holds the input variables driving the guest probes
+type vmGuestReadyConfig struct {
+	privateKey     string
+	remoteUser     string
+	labelSelector  labels.Selector
+	timeout        time.Duration
+	pollInterval   time.Duration
+	attemptTimeout time.Duration
+	concurrency    int
+	cloudInit      bool
+	useVirtctl     bool
+}
+
+type vmGuestReady struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	cfg           vmGuestReadyConfig
+	startTime     time.Time
+	// VirtualMachines matching the label selector, VMIs don't inherit their labels
+	vmLister cache.GenericLister
+	// VMI UIDs already probed
+	seen     sync.Map
+	probeWg  sync.WaitGroup
+	probeSem chan struct{}
+	vmClient *vmops.Client
+}
+
+type vmGuestReadyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVMGuestReadyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmGuestReadyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vmf vmGuestReadyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmGuestReady{
+		BaseMeasurement: vmf.NewBaseLatency(jobConfig, clientSet, restConfig, vmGuestReadyMeasurementName, vmGuestReadyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (v *vmGuestReady) setInputVars() error {
+	v.cfg = vmGuestReadyConfig{
+		remoteUser:     "fedora",
+		timeout:        15 * time.Minute,
+		pollInterval:   time.Second,
+		attemptTimeout: 30 * time.Second,
+		concurrency:    20,
+	}
+	// Only the uuid label is always enforced, the VMs restarted by kubevirt jobs carry the job label of the job that created them
+	selector := fmt.Sprintf("%s=%s", config.KubeBurnerLabelUUID, v.Uuid)
+	for _, obj := range v.JobConfig.Objects {
+		for key, val := range obj.InputVars {
+			var err error
+			switch key {
+			case "privateKey":
+				v.cfg.privateKey = fmt.Sprint(val)
+			case "remoteUser":
+				v.cfg.remoteUser = fmt.Sprint(val)
+			case "guestReadyLabelSelector":
+				selector = fmt.Sprintf("%s=%s,%s", config.KubeBurnerLabelUUID, v.Uuid, val)
+			case "guestReadyTimeout":
+				v.cfg.timeout, err = time.ParseDuration(fmt.Sprint(val))
+			case "guestReadyPollInterval":
+				v.cfg.pollInterval, err = time.ParseDuration(fmt.Sprint(val))
+			case "guestReadyConcurrency":
+				_, err = fmt.Sscan(fmt.Sprint(val), &v.cfg.concurrency)
+			case "guestReadyCloudInit":
+				v.cfg.cloudInit = fmt.Sprint(val) == "true"
+			case "useVirtctl":
+				v.cfg.useVirtctl = fmt.Sprint(val) == "true"
+			}
+			if err != nil {
+				return fmt.Errorf("failure parsing %s: %w", key, err)
+			}
+		}
+	}
+	if v.cfg.concurrency < 1 {
+		v.cfg.concurrency = 1
+	}
+	var err error
+	v.cfg.labelSelector, err = labels.Parse(selector)
+	return err
+}
+
+func (v *vmGuestReady) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	v.seen = sync.Map{}
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := v.setInputVars(); err != nil {
+		return err
+	}
+	// Jobs not creating VMs, e.g. stopping them or creating volumes, don't provide the SSH key
+	if v.cfg.privateKey == "" {
+		log.Debugf("No privateKey input variable found in job %s, skipping guest readiness checks", v.JobConfig.Name)
+		return nil
+	}
+	var err error
+	if v.vmClient, err = vmops.NewClient(v.RestConfig); err != nil {
+		return err
+	}
+	v.vmClient.UseVirtctl = v.cfg.useVirtctl
+	v.startTime = time.Now().UTC()
+	v.probeSem = make(chan struct{}, v.cfg.concurrency)
+	v.stopCh = make(chan struct{})
+	vmFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, v.JobConfig.Namespace, func(options *metav1.ListOptions) {
+		options.LabelSelector = v.cfg.labelSelector.String()
+	})
+	v.vmLister = vmFactory.ForResource(vmGVR).Lister()
+	vmFactory.Start(v.stopCh)
+	vmFactory.WaitForCacheSync(v.stopCh)
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, v.JobConfig.Namespace, nil)
+	vmiInformer := factory.ForResource(vmiGVR).Informer()
+	vmiInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: v.handleVMI,
+		UpdateFunc: func(oldObj, newObj any) {
+			v.handleVMI(newObj)
+		},
+	})
+	log.Infof("Starting VM guest readiness watcher for job %s", v.JobConfig.Name)
+	factory.Start(v.stopCh)
+	factory.WaitForCacheSync(v.stopCh)
+	return nil
+}
+
+// handleVMI starts probing the guest once the VMI reaches the Running phase
+func (v *vmGuestReady) handleVMI(obj any) {
+	observedTime := time.Now().UTC()
+	vmi := obj.(*unstructured.Unstructured)
+	transitionTime, running := getVMIRunningTime(vmi)
+	// VMIs whose VirtualMachine isn't cached yet are evaluated again on their next update
+	if !running || !v.matchesVM(vmi) {
+		return
+	}
+	if _, loaded := v.seen.LoadOrStore(vmi.GetUID(), true); loaded {
+		return
+	}
+	// VMIs that were already running before the job started, i.e. not created or restarted by it
+	if transitionTime.Before(v.startTime.Truncate(time.Second)) {
+		return
+	}
+	runningTime := vmiRunningTime(transitionTime, observedTime)
+	nodeName, _, _ := unstructured.NestedString(vmi.Object, "status", "nodeName")
+	m := vmGuestReadyMetric{
+		Timestamp:        runningTime,
+		MetricName:       vmGuestReadyMeasurementName,
+		UUID:             v.Uuid,
+		JobName:          v.JobConfig.Name,
+		Namespace:        vmi.GetNamespace(),
+		Name:             vmi.GetName(),
+		NodeName:         nodeName,
+		Metadata:         v.Metadata,
+		SSHReadyLatency:  -1,
+		CloudInitLatency: -1,
+	}
+	v.Metrics.Store(string(vmi.GetUID()), m)
+	v.probeWg.Add(1)
+	go func() {
+		defer v.probeWg.Done()
+		v.probeSem <- struct{}{}
+		defer func() { <-v.probeSem }()
+		v.Metrics.Store(string(vmi.GetUID()), v.probeGuest(m))
+	}()
+}
+
+// matchesVM checks whether the VirtualMachine owning the VMI is in the cache of VirtualMachines matching the label selector
+func (v *vmGuestReady) matchesVM(vmi *unstructured.Unstructured) bool {
+	_, err := v.vmLister.ByNamespace(vmi.GetNamespace()).Get(vmi.GetName())
+	return err == nil
+}
+
+// probeGuest polls the guest through SSH until a command succeeds and, optionally, cloud-init completes
+func (v *vmGuestReady) probeGuest(m vmGuestReadyMetric) vmGuestReadyMetric {
+	deadline := m.Timestamp.Add(v.cfg.timeout)
+	for time.Now().Before(deadline) {
+		m.SSHAttempts++
+		if _, err := v.remoteCommand(m.Namespace, m.Name, "true"); err == nil {
+			m.SSHReadyLatency = int(time.Since(m.Timestamp).Milliseconds())
+			log.Debugf("VMI %s/%s reachable through SSH after %dms", m.Namespace, m.Name, m.SSHReadyLatency)
+			break
+		}
+		time.Sleep(v.cfg.pollInterval)
+	}
+	if m.SSHReadyLatency < 0 || !v.cfg.cloudInit {
+		return m
+	}
+	for time.Now().Before(deadline) {
+		output, _ := v.remoteCommand(m.Namespace, m.Name, "cloud-init status")
+		if status := parseCloudInitStatus(output); status == cloudInitDoneStatus || status == cloudInitErrorStatus {
+			m.CloudInitStatus = status
+			m.CloudInitLatency = int(time.Since(m.Timestamp).Milliseconds())
+			break
+		}
+		time.Sleep(v.cfg.pollInterval)
+	}
+	return m
+}
+
+func (v *vmGuestReady) remoteCommand(namespace, name, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), v.cfg.attemptTimeout)
+	defer cancel()
+	return v.vmClient.RunCommand(ctx, namespace, name, v.cfg.remoteUser, v.cfg.privateKey, command)
+}
+
+// parseCloudInitStatus extracts the status reported by cloud-init status, e.g. "status: done"
+func parseCloudInitStatus(output string) string {
+	for line := range strings.Lines(output) {
+		if status, found := strings.CutPrefix(strings.TrimSpace(line), "status:"); found {
+			return strings.TrimSpace(status)
+		}
+	}
+	return ""
+}
+
+// getVMIRunningTime returns the time the VMI transitioned to the Running phase, with the second resolution of the phase transition timestamps
+func getVMIRunningTime(vmi *unstructured.Unstructured) (time.Time, bool) {
+	phase, _, _ := unstructured.NestedString(vmi.Object, "status", "phase")
+	if phase != vmiRunningPhase {
+		return time.Time{}, false
+	}
+	transitions, _, _ := unstructured.NestedSlice(vmi.Object, "status", "phaseTransitionTimestamps")
+	for _, t := range transitions {
+		transition, ok := t.(map[string]any)
+		if !ok || transition["phase"] != vmiRunningPhase {
+			continue
+		}
+		ts, _ := transition["phaseTransitionTimestamp"].(string)
+		parsed, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			break
+		}
+		return parsed.UTC(), true
+	}
+	log.Warnf("VMI %s/%s is Running but has no phase transition timestamp, using current time", vmi.GetNamespace(), vmi.GetName())
+	return time.Now().UTC(), true
+}
+
+// vmiRunningTime returns the time the informer observed the Running transition, unless it was observed too late to be
+// accurate, e.g. when listing VMIs that reached Running before the informer synced, where the transition timestamp is used
+func vmiRunningTime(transitionTime, observedTime time.Time) time.Time {
+	if observedTime.Sub(transitionTime) > vmiRunningObservationSkew {
+		return transitionTime
+	}
+	return observedTime
+}
+
+func (v *vmGuestReady) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (v *vmGuestReady) Stop() error {
+	if v.JobConfig.SkipIndexing || v.stopCh == nil {
+		return nil
+	}
+	close(v.stopCh)
+	log.Infof("Waiting for VM guest readiness probes of job %s to complete", v.JobConfig.Name)
+	v.probeWg.Wait()
+	var unreachable []string
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmGuestReadyMetric)
+		if m.SSHReadyLatency < 0 {
+			unreachable = append(unreachable, m.Namespace+"/"+m.Name)
+		}
+		return true
+	})
+	err := v.StopMeasurement(v.normalizeMetrics, v.getLatency)
+	if err != nil {
+		return err
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("%d VMs not reachable through SSH after %v: %v", len(unreachable), v.cfg.timeout, unreachable)
+	}
+	return nil
+}
+
+func (v *vmGuestReady) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmGuestReadyMetric)
+		if m.SSHReadyLatency < 0 {
+			log.Warnf("VMI %s/%s never became reachable through SSH", m.Namespace, m.Name)
+		} else if v.cfg.cloudInit && m.CloudInitLatency < 0 {
+			log.Warnf("VMI %s/%s never completed cloud-init", m.Namespace, m.Name)
+		}
+		v.NormLatencies = append(v.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency excludes the VMs that never became reachable, or never completed cloud-init, from the quantiles
+func (v *vmGuestReady) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(vmGuestReadyMetric)
+	if m.SSHReadyLatency < 0 || (v.cfg.cloudInit && m.CloudInitLatency < 0) {
+		return map[string]float64{}
+	}
+	latencies := map[string]float64{
+		"SSHReadyLatency": float64(m.SSHReadyLatency),
+	}
+	if v.cfg.cloudInit {
+		latencies["CloudInitLatency"] = float64(m.CloudInitLatency)
+	}
+	return latencies
+}
+
+func (v *vmGuestReady) IsCompatible() bool {
+	return slices.Contains(supportedVMGuestReadyJobTypes, v.JobConfig.JobType)
+}
@@ -0,0 +1,57 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseCloudInitStatus(t *testing.T) {
+	tests := map[string]string{
+		"status: done\n":                    cloudInitDoneStatus,
+		"\nstatus: running\n":               "running",
+		"status: error\nextended: degraded": cloudInitErrorStatus,
+		"ssh: connect refused":              "",
+	}
+	for output, expected := range tests {
+		if got := parseCloudInitStatus(output); got != expected {
+			t.Errorf("parseCloudInitStatus(%q) = %q, expected %q", output, got, expected)
+		}
+	}
+}
+
+func TestGetVMIRunningTime(t *testing.T) {
+	vmi := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"phase": "Running",
+			"phaseTransitionTimestamps": []any{
+				map[string]any{"phase": "Scheduled", "phaseTransitionTimestamp": "2025-01-01T10:00:00Z"},
+				map[string]any{"phase": "Running", "phaseTransitionTimestamp": "2025-01-01T10:00:05Z"},
+			},
+		},
+	}}
+	got, running := getVMIRunningTime(vmi)
+	if !running {
+		t.Fatalf("expected VMI to be running")
+	}
+	if expected := time.Date(2025, 1, 1, 10, 0, 5, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("expected running time %v, got %v", expected, got)
+	}
+	unstructured.SetNestedField(vmi.Object, "Scheduled", "status", "phase")
+	if _, running := getVMIRunningTime(vmi); running {
+		t.Errorf("expected VMI not to be running")
+	}
+}
+
+func TestVMIRunningTime(t *testing.T) {
+	transition := time.Date(2025, 1, 1, 10, 0, 5, 0, time.UTC)
+	// Observed right after the transition, within its second resolution
+	if observed := transition.Add(1300 * time.Millisecond); !vmiRunningTime(transition, observed).Equal(observed) {
+		t.Errorf("expected the observed time %v", observed)
+	}
+	// Observed late, e.g. when the informer lists VMIs already running
+	if observed := transition.Add(10 * time.Second); !vmiRunningTime(transition, observed).Equal(transition) {
+		t.Errorf("expected the transition time %v", transition)
+	}
+}
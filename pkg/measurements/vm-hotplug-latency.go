@@ -0,0 +1,536 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
+)
+
+const (
+	vmHotplugLatencyMeasurementName      = "vmHotplugLatencyMeasurement"
+	vmHotplugLatencyQuantilesMeasurement = "vmHotplugLatencyQuantilesMeasurement"
+	hotplugVolumePlug                    = "volumePlug"
+	hotplugVolumeUnplug                  = "volumeUnplug"
+	hotplugCPU                           = "cpu"
+	hotplugMemory                        = "memory"
+	hotplugVolumeReadyPhase              = "Ready"
+	// Share of the hotplugged memory the guest must report, the kernel reserves part of it
+	hotplugGuestMemoryRatio = 0.85
+)
+
+var supportedVMHotplugLatencyJobTypes = []config.JobType{config.KubeVirtJob, config.PatchJob}
+
+type vmHotplugLatencyMetric struct {
+	// Time the change was first observed in the VirtualMachine
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"vmName"`
+	NodeName   string    `json:"nodeName,omitempty"`
+	Operation  string    `json:"operation"`
+	Metadata   any       `json:"metadata,omitempty"`
+	// Time from the change in the VirtualMachine to its reflection in the VMI status, -1 when never reflected
+	VMIStatusLatency int `json:"vmiStatusLatency"`
+	// Time from the change in the VirtualMachine to its reflection in the guest, -1 when never reflected
+	GuestLatency int    `json:"guestLatency"`
+	Error        string `json:"error,omitempty"`
+}
+
+// vmHotplugConfig holds the input variables describing the expected change
+type vmHotplugConfig struct {
+	operation     string
+	volumes       []string
+	sockets       int64
+	memory        resource.Quantity
+	labelSelector string
+	privateKey    string
+	remoteUser    string
+	useVirtctl    bool
+	timeout       time.Duration
+	pollInterval  time.Duration
+}
+
+// vmHotplugState tracks the progress of the change in a VM
+type vmHotplugState struct {
+	requested      time.Time
+	vmiReflected   time.Time
+	guestReflected time.Time
+	nodeName       string
+	err            string
+}
+
+type vmHotplugLatency struct {
+	measurements.BaseMeasurement
+	dynamicClient dynamic.Interface
+	cfg           vmHotplugConfig
+	vmClient      *vmops.Client
+	stopCh        chan struct{}
+	startTime     time.Time
+	mu            sync.Mutex
+	// Progress per namespace/name of the VMs selected by the job
+	states  map[string]*vmHotplugState
+	probeWg sync.WaitGroup
+}
+
+type vmHotplugLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVMHotplugLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmHotplugLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vhf vmHotplugLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmHotplugLatency{
+		BaseMeasurement: vhf.NewBaseLatency(jobConfig, clientSet, restConfig, vmHotplugLatencyMeasurementName, vmHotplugLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates, volumes are gathered from all the objects of the job
+func (v *vmHotplugLatency) setInputVars() error {
+	v.cfg = vmHotplugConfig{
+		remoteUser:   "fedora",
+		timeout:      15 * time.Minute,
+		pollInterval: 2 * time.Second,
+	}
+	for _, obj := range v.JobConfig.Objects {
+		for key, val := range obj.InputVars {
+			var err error
+			switch key {
+			case "hotplugOperation":
+				v.cfg.operation = fmt.Sprint(val)
+			case "volumeName":
+				if !slices.Contains(v.cfg.volumes, fmt.Sprint(val)) {
+					v.cfg.volumes = append(v.cfg.volumes, fmt.Sprint(val))
+				}
+			case "hotplugSockets":
+				v.cfg.sockets, err = strconv.ParseInt(fmt.Sprint(val), 10, 64)
+			case "hotplugMemory":
+				v.cfg.memory, err = resource.ParseQuantity(fmt.Sprint(val))
+			case "hotplugLabelSelector":
+				v.cfg.labelSelector = fmt.Sprint(val)
+			case "hotplugTimeout":
+				v.cfg.timeout, err = time.ParseDuration(fmt.Sprint(val))
+			case "privateKey":
+				v.cfg.privateKey = fmt.Sprint(val)
+			case "remoteUser":
+				v.cfg.remoteUser = fmt.Sprint(val)
+			case "useVirtctl":
+				v.cfg.useVirtctl = fmt.Sprint(val) == "true"
+			}
+			if err != nil {
+				return fmt.Errorf("failure parsing %s: %w", key, err)
+			}
+		}
+	}
+	switch v.cfg.operation {
+	case "":
+	case hotplugVolumePlug, hotplugVolumeUnplug:
+		if len(v.cfg.volumes) == 0 {
+			return fmt.Errorf("hotplug operation %s requires the volumeName input variable", v.cfg.operation)
+		}
+	case hotplugCPU:
+		if v.cfg.sockets <= 0 {
+			return fmt.Errorf("hotplug operation %s requires the hotplugSockets input variable", v.cfg.operation)
+		}
+	case hotplugMemory:
+		if v.cfg.memory.IsZero() {
+			return fmt.Errorf("hotplug operation %s requires the hotplugMemory input variable", v.cfg.operation)
+		}
+	default:
+		return fmt.Errorf("unsupported hotplug operation %s", v.cfg.operation)
+	}
+	if v.cfg.operation != "" && v.cfg.labelSelector == "" {
+		return fmt.Errorf("hotplug operation %s requires the hotplugLabelSelector input variable", v.cfg.operation)
+	}
+	return nil
+}
+
+func (v *vmHotplugLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	v.stopCh = nil
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := v.setInputVars(); err != nil {
+		return err
+	}
+	if v.cfg.operation == "" {
+		log.Debugf("No hotplugOperation input variable found in job %s, skipping hotplug latency", v.JobConfig.Name)
+		return nil
+	}
+	vms, err := v.dynamicClient.Resource(vmGVR).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: v.cfg.labelSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the VirtualMachines matching %s: %w", v.cfg.labelSelector, err)
+	}
+	v.states = make(map[string]*vmHotplugState, len(vms.Items))
+	for _, vm := range vms.Items {
+		v.states[vm.GetNamespace()+"/"+vm.GetName()] = &vmHotplugState{}
+	}
+	if v.cfg.privateKey != "" {
+		if v.vmClient, err = vmops.NewClient(v.RestConfig); err != nil {
+			return err
+		}
+		v.vmClient.UseVirtctl = v.cfg.useVirtctl
+	}
+	v.startTime = time.Now().UTC()
+	v.stopCh = make(chan struct{})
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(v.dynamicClient, 0)
+	factory.ForResource(vmGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			v.handleVM(obj.(*unstructured.Unstructured))
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			v.handleVM(newObj.(*unstructured.Unstructured))
+		},
+	})
+	factory.ForResource(vmiGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			v.handleVMI(obj.(*unstructured.Unstructured))
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			v.handleVMI(newObj.(*unstructured.Unstructured))
+		},
+	})
+	log.Infof("Starting VM hotplug watcher for %d VMs of job %s", len(v.states), v.JobConfig.Name)
+	factory.Start(v.stopCh)
+	factory.WaitForCacheSync(v.stopCh)
+	return nil
+}
+
+// handleVM records when the change is first requested in the VirtualMachine
+func (v *vmHotplugLatency) handleVM(vm *unstructured.Unstructured) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	state, ok := v.states[vm.GetNamespace()+"/"+vm.GetName()]
+	if !ok || !state.requested.IsZero() {
+		return
+	}
+	if hotplugRequested(vm, v.cfg) {
+		state.requested = time.Now().UTC()
+	}
+}
+
+// handleVMI records when the change is reflected in the VMI status and starts probing the guest
+func (v *vmHotplugLatency) handleVMI(vmi *unstructured.Unstructured) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	key := vmi.GetNamespace() + "/" + vmi.GetName()
+	state, ok := v.states[key]
+	if !ok || state.requested.IsZero() || !state.vmiReflected.IsZero() {
+		return
+	}
+	if !hotplugReflectedInVMI(vmi, v.cfg) {
+		return
+	}
+	state.vmiReflected = time.Now().UTC()
+	state.nodeName, _, _ = unstructured.NestedString(vmi.Object, "status", "nodeName")
+	if v.vmClient == nil {
+		return
+	}
+	vcpus := v.cfg.sockets * cpuTopologyValue(vmi, "cores") * cpuTopologyValue(vmi, "threads")
+	deadline := state.requested.Add(v.cfg.timeout)
+	v.probeWg.Add(1)
+	go func() {
+		defer v.probeWg.Done()
+		guestReflected, err := v.probeGuest(vmi.GetNamespace(), vmi.GetName(), deadline, vcpus)
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		state.guestReflected = guestReflected
+		if err != nil {
+			state.err = err.Error()
+		}
+	}()
+}
+
+// probeGuest polls the guest through SSH until the change is visible or the deadline expires
+func (v *vmHotplugLatency) probeGuest(namespace, name string, deadline time.Time, vcpus int64) (time.Time, error) {
+	command := "ls /dev/disk/by-id/"
+	switch v.cfg.operation {
+	case hotplugCPU:
+		command = "nproc"
+	case hotplugMemory:
+		command = "grep MemTotal /proc/meminfo"
+	}
+	var err error
+	for time.Now().Before(deadline) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		var output string
+		output, err = v.vmClient.RunCommand(ctx, namespace, name, v.cfg.remoteUser, v.cfg.privateKey, command)
+		cancel()
+		if err == nil {
+			if err = hotplugReflectedInGuest(output, v.cfg, vcpus); err == nil {
+				return time.Now().UTC(), nil
+			}
+		}
+		time.Sleep(v.cfg.pollInterval)
+	}
+	return time.Time{}, fmt.Errorf("change not reflected in the guest: %w", err)
+}
+
+func (v *vmHotplugLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// Stop waits for the change to be reflected in every VM, or for the timeout to expire
+func (v *vmHotplugLatency) Stop() error {
+	if v.JobConfig.SkipIndexing || v.stopCh == nil {
+		return nil
+	}
+	log.Infof("Waiting for the %s hotplug of job %s to be reflected in the VMs", v.cfg.operation, v.JobConfig.Name)
+	deadline := v.startTime.Add(v.cfg.timeout)
+	for time.Now().Before(deadline) && !v.allReflected() {
+		time.Sleep(v.cfg.pollInterval)
+	}
+	close(v.stopCh)
+	v.probeWg.Wait()
+	var failed []string
+	v.mu.Lock()
+	for key, state := range v.states {
+		namespace, name, _ := strings.Cut(key, "/")
+		m := vmHotplugLatencyMetric{
+			Timestamp:        state.requested,
+			MetricName:       vmHotplugLatencyMeasurementName,
+			UUID:             v.Uuid,
+			JobName:          v.JobConfig.Name,
+			Namespace:        namespace,
+			Name:             name,
+			NodeName:         state.nodeName,
+			Operation:        v.cfg.operation,
+			Metadata:         v.Metadata,
+			VMIStatusLatency: -1,
+			GuestLatency:     -1,
+			Error:            state.err,
+		}
+		switch {
+		case state.requested.IsZero():
+			m.Timestamp = v.startTime
+			m.Error = "change never requested in the VirtualMachine"
+		case state.vmiReflected.IsZero():
+			m.Error = "change not reflected in the VMI status"
+		default:
+			m.VMIStatusLatency = int(state.vmiReflected.Sub(state.requested).Milliseconds())
+			if !state.guestReflected.IsZero() {
+				m.GuestLatency = int(state.guestReflected.Sub(state.requested).Milliseconds())
+			}
+		}
+		if m.Error != "" {
+			failed = append(failed, key)
+		}
+		v.Metrics.Store(key, m)
+	}
+	v.mu.Unlock()
+	if err := v.StopMeasurement(v.normalizeMetrics, v.getLatency); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("%s hotplug not reflected in %d VMs after %v: %v", v.cfg.operation, len(failed), v.cfg.timeout, failed)
+	}
+	return nil
+}
+
+// allReflected checks whether every VM reflects the change in the VMI status and, when probed, in the guest
+func (v *vmHotplugLatency) allReflected() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, state := range v.states {
+		if state.vmiReflected.IsZero() || (v.vmClient != nil && state.guestReflected.IsZero() && state.err == "") {
+			return false
+		}
+	}
+	return true
+}
+
+// hotplugRequested checks whether the VirtualMachine requests the change, either through a pending volume request or its spec
+func hotplugRequested(vm *unstructured.Unstructured, cfg vmHotplugConfig) bool {
+	switch cfg.operation {
+	case hotplugVolumePlug, hotplugVolumeUnplug:
+		requestKey := "addVolumeOptions"
+		if cfg.operation == hotplugVolumeUnplug {
+			requestKey = "removeVolumeOptions"
+		}
+		requests, _, _ := unstructured.NestedSlice(vm.Object, "status", "volumeRequests")
+		for _, r := range requests {
+			request, _ := r.(map[string]any)
+			if name, _, _ := unstructured.NestedString(request, requestKey, "name"); slices.Contains(cfg.volumes, name) {
+				return true
+			}
+		}
+		volumes := volumeNames(vm.Object, "spec", "template", "spec", "volumes")
+		for _, name := range cfg.volumes {
+			if slices.Contains(volumes, name) == (cfg.operation == hotplugVolumePlug) {
+				return true
+			}
+		}
+	case hotplugCPU:
+		sockets, _, _ := unstructured.NestedInt64(vm.Object, "spec", "template", "spec", "domain", "cpu", "sockets")
+		return sockets == cfg.sockets
+	case hotplugMemory:
+		guest, _, _ := unstructured.NestedString(vm.Object, "spec", "template", "spec", "domain", "memory", "guest")
+		quantity, err := resource.ParseQuantity(guest)
+		return err == nil && quantity.Cmp(cfg.memory) == 0
+	}
+	return false
+}
+
+// hotplugReflectedInVMI checks whether the VMI status reports the change
+func hotplugReflectedInVMI(vmi *unstructured.Unstructured, cfg vmHotplugConfig) bool {
+	switch cfg.operation {
+	case hotplugVolumePlug, hotplugVolumeUnplug:
+		ready := map[string]bool{}
+		statuses, _, _ := unstructured.NestedSlice(vmi.Object, "status", "volumeStatus")
+		for _, s := range statuses {
+			status, _ := s.(map[string]any)
+			name, _, _ := unstructured.NestedString(status, "name")
+			phase, _, _ := unstructured.NestedString(status, "phase")
+			ready[name] = phase == hotplugVolumeReadyPhase
+		}
+		for _, name := range cfg.volumes {
+			_, present := ready[name]
+			if cfg.operation == hotplugVolumePlug && !ready[name] || cfg.operation == hotplugVolumeUnplug && present {
+				return false
+			}
+		}
+		return true
+	case hotplugCPU:
+		sockets, _, _ := unstructured.NestedInt64(vmi.Object, "status", "currentCPUTopology", "sockets")
+		return sockets == cfg.sockets
+	case hotplugMemory:
+		guest, _, _ := unstructured.NestedString(vmi.Object, "status", "memory", "guestCurrent")
+		quantity, err := resource.ParseQuantity(guest)
+		return err == nil && quantity.Cmp(cfg.memory) == 0
+	}
+	return false
+}
+
+// hotplugReflectedInGuest checks the output of the guest command probing the change, returning why it isn't reflected yet
+func hotplugReflectedInGuest(output string, cfg vmHotplugConfig, vcpus int64) error {
+	switch cfg.operation {
+	case hotplugVolumePlug, hotplugVolumeUnplug:
+		disks := strings.Fields(output)
+		for _, name := range cfg.volumes {
+			// Disks are exposed by serial, e.g. scsi-0QEMU_QEMU_HARDDISK_<serial> or virtio-<serial>
+			found := slices.ContainsFunc(disks, func(disk string) bool {
+				return strings.HasSuffix(disk, "_"+name) || strings.HasSuffix(disk, "-"+name)
+			})
+			if found != (cfg.operation == hotplugVolumePlug) {
+				return fmt.Errorf("disk %s present: %v", name, found)
+			}
+		}
+	case hotplugCPU:
+		nproc, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unexpected nproc output: %q", strings.TrimSpace(output))
+		}
+		if nproc != vcpus {
+			return fmt.Errorf("%d vCPUs online, expected %d", nproc, vcpus)
+		}
+	case hotplugMemory:
+		fields := strings.Fields(output)
+		if len(fields) < 2 {
+			return fmt.Errorf("unexpected meminfo output: %q", strings.TrimSpace(output))
+		}
+		memTotalKiB, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("unexpected meminfo output: %q", strings.TrimSpace(output))
+		}
+		if expected := float64(cfg.memory.Value()) * hotplugGuestMemoryRatio; float64(memTotalKiB<<10) < expected {
+			return fmt.Errorf("%dKiB of memory in the guest, expected at least %.0fKiB", memTotalKiB, expected/1024)
+		}
+	}
+	return nil
+}
+
+func volumeNames(obj map[string]any, fields ...string) []string {
+	volumes, _, _ := unstructured.NestedSlice(obj, fields...)
+	names := make([]string, 0, len(volumes))
+	for _, v := range volumes {
+		volume, _ := v.(map[string]any)
+		if name, _, _ := unstructured.NestedString(volume, "name"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// cpuTopologyValue returns the cores or threads per socket of the VMI, 1 when not set
+func cpuTopologyValue(vmi *unstructured.Unstructured, field string) int64 {
+	value, found, _ := unstructured.NestedInt64(vmi.Object, "spec", "domain", "cpu", field)
+	if !found || value < 1 {
+		return 1
+	}
+	return value
+}
+
+func (v *vmHotplugLatency) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmHotplugLatencyMetric)
+		if m.VMIStatusLatency < 0 {
+			log.Warnf("%s hotplug of VM %s/%s never reflected in the VMI status", m.Operation, m.Namespace, m.Name)
+		}
+		v.NormLatencies = append(v.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency excludes the hotplugs never reflected in the VMI status from the quantiles
+func (v *vmHotplugLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(vmHotplugLatencyMetric)
+	if m.VMIStatusLatency < 0 {
+		return map[string]float64{}
+	}
+	latencies := map[string]float64{
+		"VMIStatusLatency": float64(m.VMIStatusLatency),
+	}
+	if m.GuestLatency >= 0 {
+		latencies["GuestLatency"] = float64(m.GuestLatency)
+	}
+	return latencies
+}
+
+func (v *vmHotplugLatency) IsCompatible() bool {
+	return slices.Contains(supportedVMHotplugLatencyJobTypes, v.JobConfig.JobType)
+}
@@ -0,0 +1,124 @@
+package measurements
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestHotplugRequested(t *testing.T) {
+	volumes := []string{"hotplug-1", "hotplug-2"}
+	tests := map[string]struct {
+		cfg      vmHotplugConfig
+		object   map[string]any
+		expected bool
+	}{
+		"plugRequest": {
+			cfg:      vmHotplugConfig{operation: hotplugVolumePlug, volumes: volumes},
+			object:   map[string]any{"status": map[string]any{"volumeRequests": []any{map[string]any{"addVolumeOptions": map[string]any{"name": "hotplug-2"}}}}},
+			expected: true,
+		},
+		"plugInSpec": {
+			cfg:      vmHotplugConfig{operation: hotplugVolumePlug, volumes: volumes},
+			object:   vmSpecWithVolumes("rootdisk", "hotplug-1"),
+			expected: true,
+		},
+		"plugPending": {
+			cfg:    vmHotplugConfig{operation: hotplugVolumePlug, volumes: volumes},
+			object: vmSpecWithVolumes("rootdisk"),
+		},
+		"unplugPending": {
+			cfg:    vmHotplugConfig{operation: hotplugVolumeUnplug, volumes: volumes},
+			object: vmSpecWithVolumes("rootdisk", "hotplug-1", "hotplug-2"),
+		},
+		"unplugRemoved": {
+			cfg:      vmHotplugConfig{operation: hotplugVolumeUnplug, volumes: volumes},
+			object:   vmSpecWithVolumes("rootdisk", "hotplug-2"),
+			expected: true,
+		},
+		"cpu": {
+			cfg:      vmHotplugConfig{operation: hotplugCPU, sockets: 2},
+			object:   map[string]any{"spec": map[string]any{"template": map[string]any{"spec": map[string]any{"domain": map[string]any{"cpu": map[string]any{"sockets": int64(2)}}}}}},
+			expected: true,
+		},
+		"memory": {
+			cfg:      vmHotplugConfig{operation: hotplugMemory, memory: resource.MustParse("2Gi")},
+			object:   map[string]any{"spec": map[string]any{"template": map[string]any{"spec": map[string]any{"domain": map[string]any{"memory": map[string]any{"guest": "2048Mi"}}}}}},
+			expected: true,
+		},
+	}
+	for name, tc := range tests {
+		if got := hotplugRequested(&unstructured.Unstructured{Object: tc.object}, tc.cfg); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, got)
+		}
+	}
+}
+
+func TestHotplugReflectedInVMI(t *testing.T) {
+	volumeStatus := func(phases ...string) map[string]any {
+		statuses := []any{map[string]any{"name": "rootdisk"}}
+		for i, phase := range phases {
+			statuses = append(statuses, map[string]any{"name": []string{"hotplug-1", "hotplug-2"}[i], "phase": phase})
+		}
+		return map[string]any{"status": map[string]any{"volumeStatus": statuses}}
+	}
+	plug := vmHotplugConfig{operation: hotplugVolumePlug, volumes: []string{"hotplug-1", "hotplug-2"}}
+	unplug := vmHotplugConfig{operation: hotplugVolumeUnplug, volumes: plug.volumes}
+	tests := map[string]struct {
+		cfg      vmHotplugConfig
+		object   map[string]any
+		expected bool
+	}{
+		"plugReady":    {cfg: plug, object: volumeStatus("Ready", "Ready"), expected: true},
+		"plugAttached": {cfg: plug, object: volumeStatus("Ready", "AttachedToNode")},
+		"plugMissing":  {cfg: plug, object: volumeStatus("Ready")},
+		"unplugDone":   {cfg: unplug, object: volumeStatus(), expected: true},
+		"unplugActive": {cfg: unplug, object: volumeStatus("Detaching")},
+		"cpu": {
+			cfg:      vmHotplugConfig{operation: hotplugCPU, sockets: 2},
+			object:   map[string]any{"status": map[string]any{"currentCPUTopology": map[string]any{"sockets": int64(2)}}},
+			expected: true,
+		},
+		"memoryPending": {
+			cfg:    vmHotplugConfig{operation: hotplugMemory, memory: resource.MustParse("2Gi")},
+			object: map[string]any{"status": map[string]any{"memory": map[string]any{"guestCurrent": "1Gi"}}},
+		},
+	}
+	for name, tc := range tests {
+		if got := hotplugReflectedInVMI(&unstructured.Unstructured{Object: tc.object}, tc.cfg); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", name, tc.expected, got)
+		}
+	}
+}
+
+func TestHotplugReflectedInGuest(t *testing.T) {
+	disks := "scsi-0QEMU_QEMU_HARDDISK_hotplug-1\nscsi-0QEMU_QEMU_HARDDISK_hotplug-10\nvirtio-rootdisk\n"
+	tests := map[string]struct {
+		output   string
+		cfg      vmHotplugConfig
+		vcpus    int64
+		expected bool
+	}{
+		"plugged":        {output: disks, cfg: vmHotplugConfig{operation: hotplugVolumePlug, volumes: []string{"hotplug-1", "hotplug-10"}}, expected: true},
+		"pluggedMissing": {output: disks, cfg: vmHotplugConfig{operation: hotplugVolumePlug, volumes: []string{"hotplug-2"}}},
+		"unplugged":      {output: "virtio-rootdisk\n", cfg: vmHotplugConfig{operation: hotplugVolumeUnplug, volumes: []string{"hotplug-1"}}, expected: true},
+		"cpu":            {output: "4\n", cfg: vmHotplugConfig{operation: hotplugCPU}, vcpus: 4, expected: true},
+		"cpuPending":     {output: "2\n", cfg: vmHotplugConfig{operation: hotplugCPU}, vcpus: 4},
+		"memory":         {output: "MemTotal:        1974468 kB\n", cfg: vmHotplugConfig{operation: hotplugMemory, memory: resource.MustParse("2Gi")}, expected: true},
+		"memoryPending":  {output: "MemTotal:         960484 kB\n", cfg: vmHotplugConfig{operation: hotplugMemory, memory: resource.MustParse("2Gi")}},
+	}
+	for name, tc := range tests {
+		if err := hotplugReflectedInGuest(tc.output, tc.cfg, tc.vcpus); (err == nil) != tc.expected {
+			t.Errorf("%s: expected reflected %v, got error %v", name, tc.expected, err)
+		}
+	}
+}
+
+func vmSpecWithVolumes(names ...string) map[string]any {
+	volumes := make([]any, 0, len(names))
+	for _, name := range names {
+		volumes = append(volumes, map[string]any{"name": name})
+	}
+	return map[string]any{"spec": map[string]any{"template": map[string]any{"spec": map[string]any{"volumes": volumes}}}}
+}
@@ -0,0 +1,477 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/vmops"
+)
+
+const (
+	vmMigrationDowntimeMeasurementName      = "vmMigrationDowntimeMeasurement"
+	vmMigrationDowntimeQuantilesMeasurement = "vmMigrationDowntimeQuantilesMeasurement"
+	migrationProbeLogPathPattern            = "/tmp/kube-burner-migration-probe-%s.log"
+)
+
+var supportedVMMigrationDowntimeJobTypes = []config.JobType{config.KubeVirtJob}
+
+type vmMigrationDowntimeMetric struct {
+	// Time the probe started
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"vmName"`
+	SourceNode string    `json:"sourceNode,omitempty"`
+	TargetNode string    `json:"targetNode,omitempty"`
+	// VM probing the migrated VM, which isn't migrated
+	ProbeSource string `json:"probeSource"`
+	// Address of the migrated VM before the migration and whether it was kept
+	IPAddress          string `json:"ipAddress"`
+	IPPreserved        bool   `json:"ipPreserved"`
+	MigrationSucceeded bool   `json:"migrationSucceeded"`
+	// Milliseconds from the start to the end of the migration, -1 when not completed
+	MigrationDuration int `json:"migrationDuration"`
+	ProbesSent        int `json:"probesSent"`
+	ProbesLost        int `json:"probesLost"`
+	// Percentage of probes without reply
+	PacketLoss float64 `json:"packetLoss"`
+	// Longest time in milliseconds without replies to the probes
+	Downtime int    `json:"downtime"`
+	Metadata any    `json:"metadata,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type vmMigrationDowntimeConfig struct {
+	// VMIs migrated by the job, which are probed
+	labelSelector string
+	// VMIs running the probes, spread across the migrated VMIs of their namespace
+	sourceSelector string
+	interval       time.Duration
+	settle         time.Duration
+	timeout        time.Duration
+	privateKey     string
+	remoteUser     string
+	useVirtctl     bool
+	concurrency    int
+}
+
+type vmMigrationDowntime struct {
+	measurements.BaseMeasurement
+	dynamicClient dynamic.Interface
+	cfg           vmMigrationDowntimeConfig
+	vmClient      *vmops.Client
+	probing       bool
+}
+
+type vmMigrationDowntimeMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVMMigrationDowntimeMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmMigrationDowntimeMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vmf vmMigrationDowntimeMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmMigrationDowntime{
+		BaseMeasurement: vmf.NewBaseLatency(jobConfig, clientSet, restConfig, vmMigrationDowntimeMeasurementName, vmMigrationDowntimeQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+// Read input variables from job templates
+func (v *vmMigrationDowntime) setInputVars() error {
+	v.cfg = vmMigrationDowntimeConfig{
+		remoteUser:  "fedora",
+		interval:    200 * time.Millisecond,
+		settle:      10 * time.Second,
+		timeout:     30 * time.Minute,
+		concurrency: 20,
+	}
+	for _, obj := range v.JobConfig.Objects {
+		for key, val := range obj.InputVars {
+			var err error
+			switch key {
+			case "probeLabelSelector":
+				v.cfg.labelSelector = fmt.Sprint(val)
+			case "probeSourceLabelSelector":
+				v.cfg.sourceSelector = fmt.Sprint(val)
+			case "probeInterval":
+				v.cfg.interval, err = time.ParseDuration(fmt.Sprint(val))
+			case "probeSettle":
+				v.cfg.settle, err = time.ParseDuration(fmt.Sprint(val))
+			case "probeTimeout":
+				v.cfg.timeout, err = time.ParseDuration(fmt.Sprint(val))
+			case "probeConcurrency":
+				_, err = fmt.Sscan(fmt.Sprint(val), &v.cfg.concurrency)
+			case "privateKey":
+				v.cfg.privateKey = fmt.Sprint(val)
+			case "remoteUser":
+				v.cfg.remoteUser = fmt.Sprint(val)
+			case "useVirtctl":
+				v.cfg.useVirtctl = fmt.Sprint(val) == "true"
+			}
+			if err != nil {
+				return fmt.Errorf("failure parsing %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Start runs a ping to every VM about to be migrated from a probe source of its namespace, so that the probes don't depend on the migrated guest
+func (v *vmMigrationDowntime) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	v.probing = false
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	if err := v.setInputVars(); err != nil {
+		return err
+	}
+	if v.cfg.privateKey == "" || v.cfg.labelSelector == "" || v.cfg.sourceSelector == "" {
+		log.Debugf("No privateKey, probeLabelSelector or probeSourceLabelSelector input variables found in job %s, skipping migration downtime", v.JobConfig.Name)
+		return nil
+	}
+	var err error
+	if v.vmClient, err = vmops.NewClient(v.RestConfig); err != nil {
+		return err
+	}
+	v.vmClient.UseVirtctl = v.cfg.useVirtctl
+	vmis, err := v.dynamicClient.Resource(vmiGVR).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: v.cfg.labelSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the VMIs to migrate: %w", err)
+	}
+	sources, err := v.dynamicClient.Resource(vmiGVR).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: v.cfg.sourceSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the probe source VMIs: %w", err)
+	}
+	probeSources := assignProbeSources(vmis.Items, sources.Items)
+	log.Infof("Starting migration downtime probes to %d VMs of job %s", len(vmis.Items), v.JobConfig.Name)
+	v.probing = true
+	sem := make(chan struct{}, v.cfg.concurrency)
+	var wg sync.WaitGroup
+	for _, vmi := range vmis.Items {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			m := vmMigrationDowntimeMetric{
+				Timestamp:         time.Now().UTC(),
+				MetricName:        vmMigrationDowntimeMeasurementName,
+				Namespace:         vmi.GetNamespace(),
+				Name:              vmi.GetName(),
+				IPAddress:         vmiIPAddress(&vmi),
+				MigrationDuration: -1,
+				Downtime:          -1,
+			}
+			m.SourceNode, _, _ = unstructured.NestedString(vmi.Object, "status", "nodeName")
+			m.ProbeSource = probeSources[m.Namespace+"/"+m.Name]
+			if err := v.startProbe(&m); err != nil {
+				m.Error = err.Error()
+				log.Warnf("Failed to start migration downtime probe to VM %s/%s: %v", m.Namespace, m.Name, err)
+			}
+			v.Metrics.Store(m.Namespace+"/"+m.Name, m)
+		})
+	}
+	wg.Wait()
+	return nil
+}
+
+// assignProbeSources spreads the probe sources of each namespace across its migrated VMIs, sources being migrated are never used
+func assignProbeSources(vmis, sources []unstructured.Unstructured) map[string]string {
+	migrated := map[string]bool{}
+	for _, vmi := range vmis {
+		migrated[vmi.GetNamespace()+"/"+vmi.GetName()] = true
+	}
+	sourcesByNamespace := map[string][]string{}
+	for _, source := range sources {
+		if !migrated[source.GetNamespace()+"/"+source.GetName()] {
+			sourcesByNamespace[source.GetNamespace()] = append(sourcesByNamespace[source.GetNamespace()], source.GetName())
+		}
+	}
+	vmis = slices.Clone(vmis)
+	slices.SortFunc(vmis, func(a, b unstructured.Unstructured) int {
+		return strings.Compare(a.GetNamespace()+"/"+a.GetName(), b.GetNamespace()+"/"+b.GetName())
+	})
+	probeSources := map[string]string{}
+	assigned := map[string]int{}
+	for _, vmi := range vmis {
+		namespaceSources := sourcesByNamespace[vmi.GetNamespace()]
+		if len(namespaceSources) == 0 {
+			continue
+		}
+		slices.Sort(namespaceSources)
+		probeSources[vmi.GetNamespace()+"/"+vmi.GetName()] = namespaceSources[assigned[vmi.GetNamespace()]%len(namespaceSources)]
+		assigned[vmi.GetNamespace()]++
+	}
+	return probeSources
+}
+
+// startProbe starts a timestamped ping to the migrated VM in the background of the probe source, reporting the probes without reply
+func (v *vmMigrationDowntime) startProbe(m *vmMigrationDowntimeMetric) error {
+	if m.ProbeSource == "" {
+		return fmt.Errorf("no probe source found in namespace %s", m.Namespace)
+	}
+	if m.IPAddress == "" {
+		return fmt.Errorf("the VM has no IP address")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	// A source may probe several migrated VMs, each ping is told apart by its destination
+	command := fmt.Sprintf("sudo sh -c 'pkill -f \"^ping .* %s$\"; setsid ping -D -O -i %g %s > %s 2>&1 < /dev/null &'",
+		m.IPAddress, v.cfg.interval.Seconds(), m.IPAddress, fmt.Sprintf(migrationProbeLogPathPattern, m.IPAddress))
+	_, err := v.vmClient.RunCommand(ctx, m.Namespace, m.ProbeSource, v.cfg.remoteUser, v.cfg.privateKey, command)
+	return err
+}
+
+// vmiIPAddress returns the address of the first interface of the VMI
+func vmiIPAddress(vmi *unstructured.Unstructured) string {
+	interfaces, _, _ := unstructured.NestedSlice(vmi.Object, "status", "interfaces")
+	for _, i := range interfaces {
+		if iface, ok := i.(map[string]any); ok {
+			if ip, _ := iface["ipAddress"].(string); ip != "" {
+				return ip
+			}
+		}
+	}
+	return ""
+}
+
+func (v *vmMigrationDowntime) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// Stop waits for the migrations to finish, lets the probes run for a while and collects their results
+func (v *vmMigrationDowntime) Stop() error {
+	if v.JobConfig.SkipIndexing || !v.probing {
+		return nil
+	}
+	log.Infof("Waiting for the migrations of job %s to finish before collecting the downtime probes", v.JobConfig.Name)
+	deadline := time.Now().Add(v.cfg.timeout)
+	for time.Now().Before(deadline) && !v.migrationsFinished() {
+		time.Sleep(5 * time.Second)
+	}
+	time.Sleep(v.cfg.settle)
+	sem := make(chan struct{}, v.cfg.concurrency)
+	var wg sync.WaitGroup
+	v.Metrics.Range(func(key, value any) bool {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			v.Metrics.Store(key, v.collectProbe(value.(vmMigrationDowntimeMetric)))
+		})
+		return true
+	})
+	wg.Wait()
+	var failed []string
+	v.Metrics.Range(func(key, value any) bool {
+		if m := value.(vmMigrationDowntimeMetric); m.Error != "" {
+			failed = append(failed, m.Namespace+"/"+m.Name)
+		}
+		return true
+	})
+	if err := v.StopMeasurement(v.normalizeMetrics, v.getLatency); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("migration downtime couldn't be measured or the migration failed in %d VMs: %v", len(failed), failed)
+	}
+	return nil
+}
+
+// migrationsFinished returns true once the migration state of every probed VMI is completed
+func (v *vmMigrationDowntime) migrationsFinished() bool {
+	finished := true
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmMigrationDowntimeMetric)
+		vmi, err := v.dynamicClient.Resource(vmiGVR).Namespace(m.Namespace).Get(context.TODO(), m.Name, metav1.GetOptions{})
+		if err != nil {
+			return true
+		}
+		completed, _, _ := unstructured.NestedBool(vmi.Object, "status", "migrationState", "completed")
+		startTimestamp, _, _ := unstructured.NestedString(vmi.Object, "status", "migrationState", "startTimestamp")
+		started, _ := time.Parse(time.RFC3339, startTimestamp)
+		// Migrations from previous jobs are reported until a new one starts
+		finished = completed && !started.Before(m.Timestamp.Truncate(time.Second))
+		return finished
+	})
+	return finished
+}
+
+// collectProbe stops the ping in the probe source, parses its output and fills the migration state
+func (v *vmMigrationDowntime) collectProbe(m vmMigrationDowntimeMetric) vmMigrationDowntimeMetric {
+	m.UUID = v.Uuid
+	m.JobName = v.JobConfig.Name
+	m.Metadata = v.Metadata
+	vmi, err := v.dynamicClient.Resource(vmiGVR).Namespace(m.Namespace).Get(context.TODO(), m.Name, metav1.GetOptions{})
+	if err != nil {
+		m.Error = fmt.Sprintf("failed to get the VMI: %v", err)
+		return m
+	}
+	ip := setVMIMigrationState(&m, vmi)
+	// The probe didn't start
+	if m.Error != "" {
+		return m
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	command := fmt.Sprintf("sudo pkill -INT -f \"^ping .* %s$\"; sleep 1; cat %s", m.IPAddress, fmt.Sprintf(migrationProbeLogPathPattern, m.IPAddress))
+	output, err := v.vmClient.RunCommand(ctx, m.Namespace, m.ProbeSource, v.cfg.remoteUser, v.cfg.privateKey, command)
+	if err == nil {
+		var downtime time.Duration
+		m.ProbesSent, m.ProbesLost, downtime, err = parsePingLog(output)
+		m.Downtime = int(downtime.Milliseconds())
+		if m.ProbesSent > 0 {
+			m.PacketLoss = float64(m.ProbesLost) * 100 / float64(m.ProbesSent)
+		}
+	}
+	switch {
+	case err != nil:
+		m.Error = fmt.Sprintf("failed to collect the probe results: %v", err)
+	case !m.MigrationSucceeded:
+		m.Error = "migration didn't succeed"
+	case m.IPAddress != "" && ip != "" && !m.IPPreserved:
+		m.Error = fmt.Sprintf("IP address changed from %s to %s", m.IPAddress, ip)
+	}
+	if m.Error != "" {
+		log.Warnf("VM %s/%s migration downtime: %s", m.Namespace, m.Name, m.Error)
+	}
+	return m
+}
+
+// setVMIMigrationState fills the nodes, duration and result of the last migration of the VMI, returning its current IP address
+func setVMIMigrationState(m *vmMigrationDowntimeMetric, vmi *unstructured.Unstructured) string {
+	state, _, _ := unstructured.NestedMap(vmi.Object, "status", "migrationState")
+	if sourceNode, _, _ := unstructured.NestedString(state, "sourceNode"); sourceNode != "" {
+		m.SourceNode = sourceNode
+	}
+	m.TargetNode, _, _ = unstructured.NestedString(state, "targetNode")
+	completed, _, _ := unstructured.NestedBool(state, "completed")
+	failed, _, _ := unstructured.NestedBool(state, "failed")
+	m.MigrationSucceeded = completed && !failed
+	startTimestamp, _, _ := unstructured.NestedString(state, "startTimestamp")
+	endTimestamp, _, _ := unstructured.NestedString(state, "endTimestamp")
+	start, startErr := time.Parse(time.RFC3339, startTimestamp)
+	end, endErr := time.Parse(time.RFC3339, endTimestamp)
+	if completed && startErr == nil && endErr == nil {
+		m.MigrationDuration = int(end.Sub(start).Milliseconds())
+	}
+	ip := vmiIPAddress(vmi)
+	m.IPPreserved = m.IPAddress != "" && ip == m.IPAddress
+	return ip
+}
+
+// parsePingLog parses the output of ping -D -O, returning the probes sent and lost and the longest time without replies
+func parsePingLog(output string) (int, int, time.Duration, error) {
+	replies := map[int]float64{}
+	var maxSeq int
+	var lastTimestamp float64
+	for line := range strings.Lines(output) {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		end := strings.Index(line, "]")
+		_, seqField, found := strings.Cut(line, "icmp_seq=")
+		if end < 0 || !found {
+			continue
+		}
+		timestamp, err := strconv.ParseFloat(line[1:end], 64)
+		if err != nil {
+			continue
+		}
+		seq, err := strconv.Atoi(strings.Fields(seqField)[0])
+		if err != nil {
+			continue
+		}
+		maxSeq = max(maxSeq, seq)
+		lastTimestamp = max(lastTimestamp, timestamp)
+		// Only the first reply counts, duplicates are reported as well
+		if _, ok := replies[seq]; !ok && strings.Contains(line, " bytes from ") {
+			replies[seq] = timestamp
+		}
+	}
+	if maxSeq == 0 {
+		return 0, 0, 0, fmt.Errorf("no probes found in the ping output: %q", strings.TrimSpace(output))
+	}
+	if len(replies) == 0 {
+		return maxSeq, maxSeq, 0, fmt.Errorf("no replies to the %d probes sent", maxSeq)
+	}
+	seqs := make([]int, 0, len(replies))
+	for seq := range replies {
+		seqs = append(seqs, seq)
+	}
+	slices.Sort(seqs)
+	var downtime float64
+	for i := 1; i < len(seqs); i++ {
+		if seqs[i]-seqs[i-1] > 1 {
+			downtime = max(downtime, replies[seqs[i]]-replies[seqs[i-1]])
+		}
+	}
+	// Probes still unanswered when ping was stopped
+	if last := seqs[len(seqs)-1]; last < maxSeq {
+		downtime = max(downtime, lastTimestamp-replies[last])
+	}
+	return maxSeq, maxSeq - len(replies), time.Duration(downtime * float64(time.Second)), nil
+}
+
+func (v *vmMigrationDowntime) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		v.NormLatencies = append(v.NormLatencies, value.(vmMigrationDowntimeMetric))
+		return true
+	})
+	return 0
+}
+
+// getLatency only accounts for successful migrations with probe results, the packet loss percentage
+// isn't a latency and is only reported in the migration documents
+func (v *vmMigrationDowntime) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(vmMigrationDowntimeMetric)
+	if m.Error != "" {
+		return map[string]float64{}
+	}
+	return map[string]float64{
+		"Downtime":          float64(m.Downtime),
+		"MigrationDuration": float64(m.MigrationDuration),
+	}
+}
+
+func (v *vmMigrationDowntime) IsCompatible() bool {
+	return slices.Contains(supportedVMMigrationDowntimeJobTypes, v.JobConfig.JobType)
+}
@@ -0,0 +1,108 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParsePingLog(t *testing.T) {
+	output := `PING 10.132.0.5 (10.132.0.5) 56(84) bytes of data.
+[1700000000.000000] 64 bytes from 10.132.0.5: icmp_seq=1 ttl=64 time=0.412 ms
+[1700000000.200000] 64 bytes from 10.132.0.5: icmp_seq=2 ttl=64 time=0.398 ms
+[1700000000.600000] no answer yet for icmp_seq=3
+[1700000000.800000] no answer yet for icmp_seq=4
+[1700000001.000000] 64 bytes from 10.132.0.5: icmp_seq=5 ttl=64 time=0.501 ms
+[1700000001.000100] 64 bytes from 10.132.0.5: icmp_seq=5 ttl=64 time=0.601 ms (DUP!)
+[1700000001.200000] 64 bytes from 10.132.0.5: icmp_seq=6 ttl=64 time=0.455 ms
+
+--- 10.132.0.5 ping statistics ---
+6 packets transmitted, 4 received, 33.3333% packet loss, time 1200ms
+`
+	sent, lost, downtime, err := parsePingLog(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 6 || lost != 2 {
+		t.Errorf("expected 6 probes sent and 2 lost, got %d and %d", sent, lost)
+	}
+	if downtime.Round(time.Millisecond) != 800*time.Millisecond {
+		t.Errorf("expected 800ms downtime, got %v", downtime)
+	}
+}
+
+func TestParsePingLogTrailingLoss(t *testing.T) {
+	output := `[1700000000.000000] 64 bytes from 10.132.0.5: icmp_seq=1 ttl=64 time=0.412 ms
+[1700000000.400000] no answer yet for icmp_seq=2
+[1700000000.600000] From 10.132.0.1 icmp_seq=3 Destination Host Unreachable
+`
+	sent, lost, downtime, err := parsePingLog(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 3 || lost != 2 || downtime.Round(time.Millisecond) != 600*time.Millisecond {
+		t.Errorf("unexpected results sent %d lost %d downtime %v", sent, lost, downtime)
+	}
+	if _, _, _, err := parsePingLog("[1700000000.400000] no answer yet for icmp_seq=1\n"); err == nil {
+		t.Errorf("expected an error without replies")
+	}
+	if _, _, _, err := parsePingLog("ping: connect: Network is unreachable\n"); err == nil {
+		t.Errorf("expected an error without probes")
+	}
+}
+
+func TestSetVMIMigrationState(t *testing.T) {
+	vmi := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"interfaces": []any{map[string]any{"ipAddress": "10.132.0.7"}},
+			"migrationState": map[string]any{
+				"sourceNode":     "worker-0",
+				"targetNode":     "worker-1",
+				"startTimestamp": "2025-01-01T10:00:00Z",
+				"endTimestamp":   "2025-01-01T10:00:12Z",
+				"completed":      true,
+			},
+		},
+	}}
+	m := vmMigrationDowntimeMetric{IPAddress: "10.132.0.7", MigrationDuration: -1}
+	if ip := setVMIMigrationState(&m, vmi); ip != "10.132.0.7" {
+		t.Errorf("unexpected IP address %s", ip)
+	}
+	if !m.MigrationSucceeded || m.MigrationDuration != 12000 || !m.IPPreserved {
+		t.Errorf("unexpected migration state succeeded %v duration %dms IP preserved %v", m.MigrationSucceeded, m.MigrationDuration, m.IPPreserved)
+	}
+	if m.SourceNode != "worker-0" || m.TargetNode != "worker-1" {
+		t.Errorf("unexpected nodes %s -> %s", m.SourceNode, m.TargetNode)
+	}
+	m = vmMigrationDowntimeMetric{IPAddress: "10.132.0.3", MigrationDuration: -1}
+	setVMIMigrationState(&m, vmi)
+	if m.IPPreserved {
+		t.Errorf("expected the IP address change to be detected")
+	}
+}
+
+func TestAssignProbeSources(t *testing.T) {
+	vmi := func(namespace, name string) unstructured.Unstructured {
+		obj := unstructured.Unstructured{Object: map[string]any{}}
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+	migrated := []unstructured.Unstructured{vmi("ns-1", "server-b"), vmi("ns-1", "server-a"), vmi("ns-2", "server"), vmi("ns-3", "client-1")}
+	sources := []unstructured.Unstructured{vmi("ns-1", "client-2"), vmi("ns-1", "client-1"), vmi("ns-2", "client-1"), vmi("ns-3", "client-1")}
+	probeSources := assignProbeSources(migrated, sources)
+	expected := map[string]string{
+		"ns-1/server-a": "client-1",
+		"ns-1/server-b": "client-2",
+		"ns-2/server":   "client-1",
+	}
+	if len(probeSources) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, probeSources)
+	}
+	for vm, source := range expected {
+		if probeSources[vm] != source {
+			t.Errorf("expected %s to be probed from %s, got %q", vm, source, probeSources[vm])
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	vmSnapshotRestoreLatencyMeasurementName      = "vmSnapshotRestoreLatencyMeasurement"
+	vmSnapshotRestoreLatencyQuantilesMeasurement = "vmSnapshotRestoreLatencyQuantilesMeasurement"
+	vmSnapshotKind                               = "VirtualMachineSnapshot"
+	vmRestoreKind                                = "VirtualMachineRestore"
+)
+
+var (
+	supportedVMSnapshotRestoreLatencyJobTypes = []config.JobType{config.CreationJob}
+	vmSnapshotGVR                             = schema.GroupVersionResource{
+		Group:    "snapshot.kubevirt.io",
+		Version:  "v1beta1",
+		Resource: "virtualmachinesnapshots",
+	}
+	vmRestoreGVR = schema.GroupVersionResource{
+		Group:    "snapshot.kubevirt.io",
+		Version:  "v1beta1",
+		Resource: "virtualmachinerestores",
+	}
+)
+
+type vmSnapshotRestoreMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	// Source VirtualMachine of the snapshot or target VirtualMachine of the restore
+	VMName string `json:"vmName"`
+	// VirtualMachineSnapshot restored, only set in restores
+	SnapshotName string `json:"snapshotName,omitempty"`
+	Phase        string `json:"phase,omitempty"`
+	Metadata     any    `json:"metadata,omitempty"`
+	// Snapshot ready to use or restore complete
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+	// Milliseconds from the creation, -1 when not reached.
+	// The snapshot taken latency is only reported by snapshots
+	SnapshotTakenLatency int `json:"snapshotTakenLatency"`
+	ReadyLatency         int `json:"readyLatency"`
+}
+
+type vmSnapshotRestoreLatency struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	startTime     time.Time
+}
+
+type vmSnapshotRestoreLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVMSnapshotRestoreLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmSnapshotRestoreLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vmf vmSnapshotRestoreLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmSnapshotRestoreLatency{
+		BaseMeasurement: vmf.NewBaseLatency(jobConfig, clientSet, restConfig, vmSnapshotRestoreLatencyMeasurementName, vmSnapshotRestoreLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+func (v *vmSnapshotRestoreLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	v.startTime = time.Now().UTC().Truncate(time.Second)
+	v.stopCh = make(chan struct{})
+	namespace := v.JobConfig.Namespace
+	if v.JobConfig.NamespacedIterations {
+		namespace = metav1.NamespaceAll
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, namespace, nil)
+	for kind, gvr := range map[string]schema.GroupVersionResource{vmSnapshotKind: vmSnapshotGVR, vmRestoreKind: vmRestoreGVR} {
+		handler := v.handler(kind)
+		factory.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: handler,
+			UpdateFunc: func(oldObj, newObj any) {
+				handler(newObj)
+			},
+		})
+	}
+	log.Infof("Starting VirtualMachineSnapshot and VirtualMachineRestore latency watcher for job %s", v.JobConfig.Name)
+	factory.Start(v.stopCh)
+	factory.WaitForCacheSync(v.stopCh)
+	return nil
+}
+
+// handler returns the event handler recording the first time each milestone of the objects of the given kind is observed
+func (v *vmSnapshotRestoreLatency) handler(kind string) func(obj any) {
+	return func(obj any) {
+		u := obj.(*unstructured.Unstructured)
+		created := u.GetCreationTimestamp().UTC()
+		if created.Before(v.startTime) {
+			return
+		}
+		now := time.Now().UTC()
+		var m vmSnapshotRestoreMetric
+		if val, ok := v.Metrics.Load(string(u.GetUID())); ok {
+			m = val.(vmSnapshotRestoreMetric)
+			if m.Ready {
+				return
+			}
+		} else {
+			m = vmSnapshotRestoreMetric{
+				Timestamp:            created,
+				MetricName:           vmSnapshotRestoreLatencyMeasurementName,
+				Namespace:            u.GetNamespace(),
+				Name:                 u.GetName(),
+				Kind:                 kind,
+				SnapshotTakenLatency: -1,
+				ReadyLatency:         -1,
+			}
+			if kind == vmSnapshotKind {
+				m.VMName, _, _ = unstructured.NestedString(u.Object, "spec", "source", "name")
+			} else {
+				m.VMName, _, _ = unstructured.NestedString(u.Object, "spec", "target", "name")
+				m.SnapshotName, _, _ = unstructured.NestedString(u.Object, "spec", "virtualMachineSnapshotName")
+			}
+		}
+		status, _, _ := unstructured.NestedMap(u.Object, "status")
+		if kind == vmSnapshotKind {
+			setVMSnapshotStatus(&m, status, now)
+		} else {
+			setVMRestoreStatus(&m, status, now)
+		}
+		if m.Ready {
+			log.Debugf("%s %s/%s ready after %dms", m.Kind, m.Namespace, m.Name, m.ReadyLatency)
+		}
+		v.Metrics.Store(string(u.GetUID()), m)
+	}
+}
+
+// setVMSnapshotStatus updates the milestones reached according to the VirtualMachineSnapshot status observed at the given time
+func setVMSnapshotStatus(m *vmSnapshotRestoreMetric, status map[string]any, observed time.Time) {
+	m.Phase, _, _ = unstructured.NestedString(status, "phase")
+	// creationTime is the time the VolumeSnapshots of all the volumes were taken
+	if creationTime, _, _ := unstructured.NestedString(status, "creationTime"); creationTime != "" && m.SnapshotTakenLatency == -1 {
+		if taken, err := time.Parse(time.RFC3339, creationTime); err == nil {
+			m.SnapshotTakenLatency = int(taken.Sub(m.Timestamp).Milliseconds())
+		}
+	}
+	if message, _, _ := unstructured.NestedString(status, "error", "message"); message != "" {
+		m.Error = message
+	}
+	if ready, _, _ := unstructured.NestedBool(status, "readyToUse"); ready {
+		m.Ready = true
+		m.ReadyLatency = int(observed.Sub(m.Timestamp).Milliseconds())
+	}
+}
+
+// setVMRestoreStatus updates the milestones reached according to the VirtualMachineRestore status observed at the given time
+func setVMRestoreStatus(m *vmSnapshotRestoreMetric, status map[string]any, observed time.Time) {
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		if reason, _ := condition["reason"].(string); reason != "" {
+			m.Phase = reason
+		}
+		if condition["status"] == "False" {
+			m.Error, _ = condition["message"].(string)
+		}
+	}
+	if complete, _, _ := unstructured.NestedBool(status, "complete"); complete {
+		m.Ready = true
+		m.Error = ""
+		m.ReadyLatency = int(observed.Sub(m.Timestamp).Milliseconds())
+	}
+}
+
+func (v *vmSnapshotRestoreLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (v *vmSnapshotRestoreLatency) Stop() error {
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(v.stopCh)
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmSnapshotRestoreMetric)
+		if !m.Ready {
+			log.Warnf("%s %s/%s wasn't ready when the job finished: %s", m.Kind, m.Namespace, m.Name, m.Error)
+		}
+		return true
+	})
+	return v.StopMeasurement(v.normalizeMetrics, v.getLatency)
+}
+
+func (v *vmSnapshotRestoreLatency) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmSnapshotRestoreMetric)
+		m.UUID = v.Uuid
+		m.JobName = v.JobConfig.Name
+		m.Metadata = v.Metadata
+		v.NormLatencies = append(v.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency only accounts for ready objects, snapshots and restores are reported as different quantiles
+func (v *vmSnapshotRestoreLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(vmSnapshotRestoreMetric)
+	if !m.Ready {
+		return map[string]float64{}
+	}
+	if m.Kind == vmRestoreKind {
+		return map[string]float64{
+			"RestoreCompleteLatency": float64(m.ReadyLatency),
+		}
+	}
+	return map[string]float64{
+		"SnapshotTakenLatency": float64(m.SnapshotTakenLatency),
+		"SnapshotReadyLatency": float64(m.ReadyLatency),
+	}
+}
+
+func (v *vmSnapshotRestoreLatency) IsCompatible() bool {
+	return slices.Contains(supportedVMSnapshotRestoreLatencyJobTypes, v.JobConfig.JobType)
+}
@@ -0,0 +1,60 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetVMSnapshotStatus(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := vmSnapshotRestoreMetric{
+		Timestamp:            created,
+		Kind:                 vmSnapshotKind,
+		SnapshotTakenLatency: -1,
+		ReadyLatency:         -1,
+	}
+	setVMSnapshotStatus(&m, map[string]any{
+		"phase":      "InProgress",
+		"readyToUse": false,
+	}, created.Add(time.Second))
+	if m.Ready || m.ReadyLatency != -1 || m.Phase != "InProgress" {
+		t.Errorf("expected the snapshot to be in progress, got phase %s ready %v", m.Phase, m.Ready)
+	}
+	setVMSnapshotStatus(&m, map[string]any{
+		"phase":        "Succeeded",
+		"creationTime": "2025-01-01T10:00:03Z",
+		"readyToUse":   true,
+	}, created.Add(4*time.Second))
+	if !m.Ready || m.SnapshotTakenLatency != 3000 || m.ReadyLatency != 4000 {
+		t.Errorf("unexpected latencies taken %dms ready %dms", m.SnapshotTakenLatency, m.ReadyLatency)
+	}
+}
+
+func TestSetVMRestoreStatus(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := vmSnapshotRestoreMetric{
+		Timestamp:            created,
+		Kind:                 vmRestoreKind,
+		SnapshotTakenLatency: -1,
+		ReadyLatency:         -1,
+	}
+	setVMRestoreStatus(&m, map[string]any{
+		"complete": false,
+		"conditions": []any{
+			map[string]any{"type": "Progressing", "status": "True", "reason": "Operation in progress"},
+			map[string]any{"type": "Ready", "status": "False", "reason": "Operation in progress", "message": "waiting for target VM to be powered off"},
+		},
+	}, created.Add(time.Second))
+	if m.Ready || m.Error != "waiting for target VM to be powered off" {
+		t.Errorf("expected the restore to be waiting, got ready %v error %q", m.Ready, m.Error)
+	}
+	setVMRestoreStatus(&m, map[string]any{
+		"complete": true,
+		"conditions": []any{
+			map[string]any{"type": "Ready", "status": "True", "reason": "Operation complete"},
+		},
+	}, created.Add(6*time.Second))
+	if !m.Ready || m.ReadyLatency != 6000 || m.Error != "" || m.Phase != "Operation complete" {
+		t.Errorf("unexpected restore state ready %v after %dms, phase %s error %q", m.Ready, m.ReadyLatency, m.Phase, m.Error)
+	}
+}
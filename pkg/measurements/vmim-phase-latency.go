@@ -0,0 +1,399 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/prometheus"
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	vmimPhaseLatencyMeasurementName      = "vmimPhaseLatencyMeasurement"
+	vmimPhaseLatencyQuantilesMeasurement = "vmimPhaseLatencyQuantilesMeasurement"
+	vmimPhaseNodePairSummaryName         = "vmimPhaseNodePairSummary"
+	vmimSucceededPhase                   = "Succeeded"
+	vmimFailedPhase                      = "Failed"
+	// Bytes processed by the migration, exposed by virt-handler per VMI
+	vmimDataProcessedQuery = `max_over_time(kubevirt_vmi_migration_data_processed_bytes{namespace="%s",name="%s"}[%ds])`
+)
+
+var (
+	supportedVMIMPhaseLatencyJobTypes = []config.JobType{config.CreationJob, config.KubeVirtJob}
+	vmimGVR                           = schema.GroupVersionResource{
+		Group:    "kubevirt.io",
+		Version:  "v1",
+		Resource: "virtualmachineinstancemigrations",
+	}
+	// Migration phases in the order they are traversed, each one lasts until the next reached phase
+	vmimPhases = []string{"Pending", "Scheduling", "Scheduled", "PreparingTarget", "TargetReady", "Running"}
+)
+
+type vmimPhaseMetric struct {
+	Timestamp       time.Time `json:"timestamp"`
+	MetricName      string    `json:"metricName"`
+	UUID            string    `json:"uuid"`
+	JobName         string    `json:"jobName,omitempty"`
+	Namespace       string    `json:"namespace,omitempty"`
+	Name            string    `json:"vmimName,omitempty"`
+	VMIName         string    `json:"vmiName,omitempty"`
+	SourceNode      string    `json:"sourceNode,omitempty"`
+	TargetNode      string    `json:"targetNode,omitempty"`
+	NodePair        string    `json:"nodePair,omitempty"`
+	Metadata        any       `json:"metadata,omitempty"`
+	Phase           string    `json:"phase,omitempty"`
+	Mode            string    `json:"migrationMode,omitempty"`
+	MigrationPolicy string    `json:"migrationPolicy,omitempty"`
+	FailureReason   string    `json:"failureReason,omitempty"`
+	// Time spent in each phase, -1 when the phase wasn't reached
+	PendingDuration         int `json:"pendingDuration"`
+	SchedulingDuration      int `json:"schedulingDuration"`
+	ScheduledDuration       int `json:"scheduledDuration"`
+	PreparingTargetDuration int `json:"preparingTargetDuration"`
+	TargetReadyDuration     int `json:"targetReadyDuration"`
+	RunningDuration         int `json:"runningDuration"`
+	// Time from the migration creation to its completion, -1 when not completed
+	TotalLatency int `json:"totalLatency"`
+	// Data processed by the migration, -1 when not collected. The node pair summaries add up their migrations
+	DataProcessedBytes int64 `json:"dataProcessedBytes"`
+	// Node pair summary fields
+	Migrations     int            `json:"migrations,omitempty"`
+	Succeeded      int            `json:"succeeded,omitempty"`
+	Failed         int            `json:"failed,omitempty"`
+	FailureReasons map[string]int `json:"failureReasons,omitempty"`
+}
+
+type vmimPhaseLatency struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	startTime     time.Time
+	prometheus    *prometheus.Prometheus
+	comparison    *VMIMPolicyComparison
+}
+
+type vmimPhaseLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+	prometheus *prometheus.Prometheus
+	comparison *VMIMPolicyComparison
+}
+
+func NewVMIMPhaseLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return vmimPhaseLatencyMeasurementFactory{
+		BaseMeasurementFactory: measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+// NewVMIMPhaseLatencyFactory returns a vmimPhaseLatency factory whose measurements read the data processed by every migration
+// from Prometheus, and report their results to the comparison when not nil
+func NewVMIMPhaseLatencyFactory(client *prometheus.Prometheus, comparison *VMIMPolicyComparison) measurements.NewMeasurementFactory {
+	return func(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+		return vmimPhaseLatencyMeasurementFactory{
+			BaseMeasurementFactory: measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+			prometheus:             client,
+			comparison:             comparison,
+		}, nil
+	}
+}
+
+func (vmf vmimPhaseLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &vmimPhaseLatency{
+		BaseMeasurement: vmf.NewBaseLatency(jobConfig, clientSet, restConfig, vmimPhaseLatencyMeasurementName, vmimPhaseLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+		prometheus:      vmf.prometheus,
+		comparison:      vmf.comparison,
+	}
+}
+
+func (v *vmimPhaseLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	// Phase transition timestamps have second precision
+	v.startTime = time.Now().UTC().Truncate(time.Second)
+	v.stopCh = make(chan struct{})
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, v.JobConfig.Namespace, nil)
+	vmimInformer := factory.ForResource(vmimGVR).Informer()
+	vmimInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: v.handleVMIM,
+		UpdateFunc: func(oldObj, newObj any) {
+			v.handleVMIM(newObj)
+		},
+	})
+	log.Infof("Starting VMIM phase latency watcher for job %s", v.JobConfig.Name)
+	factory.Start(v.stopCh)
+	factory.WaitForCacheSync(v.stopCh)
+	return nil
+}
+
+// handleVMIM refreshes the metric of migrations created during the job, the migration state is read once completed
+func (v *vmimPhaseLatency) handleVMIM(obj any) {
+	vmim := obj.(*unstructured.Unstructured)
+	created := vmim.GetCreationTimestamp().UTC()
+	if created.Before(v.startTime) {
+		return
+	}
+	if val, ok := v.Metrics.Load(string(vmim.GetUID())); ok && isVMIMFinalPhase(val.(vmimPhaseMetric).Phase) {
+		return
+	}
+	phase, _, _ := unstructured.NestedString(vmim.Object, "status", "phase")
+	vmiName, _, _ := unstructured.NestedString(vmim.Object, "spec", "vmiName")
+	m := vmimPhaseMetric{
+		Timestamp:          created,
+		MetricName:         vmimPhaseLatencyMeasurementName,
+		Namespace:          vmim.GetNamespace(),
+		Name:               vmim.GetName(),
+		VMIName:            vmiName,
+		Phase:              phase,
+		DataProcessedBytes: -1,
+	}
+	setVMIMPhaseDurations(&m, getVMIMPhaseTransitions(vmim))
+	if isVMIMFinalPhase(phase) {
+		v.setMigrationState(&m, vmim)
+		log.Debugf("VMIM %s/%s %s after %dms", m.Namespace, m.Name, phase, m.TotalLatency)
+	}
+	v.Metrics.Store(string(vmim.GetUID()), m)
+}
+
+// setMigrationState fills the nodes and failure details from the VMIM migration state, or the VMI one on older KubeVirt versions
+func (v *vmimPhaseLatency) setMigrationState(m *vmimPhaseMetric, vmim *unstructured.Unstructured) {
+	state, found, _ := unstructured.NestedMap(vmim.Object, "status", "migrationState")
+	if !found {
+		vmi, err := v.dynamicClient.Resource(vmiGVR).Namespace(m.Namespace).Get(context.TODO(), m.VMIName, metav1.GetOptions{})
+		if err != nil {
+			log.Warnf("Unable to get VMI %s/%s migration state: %v", m.Namespace, m.VMIName, err)
+			return
+		}
+		state, found, _ = unstructured.NestedMap(vmi.Object, "status", "migrationState")
+		if migrationUID, _, _ := unstructured.NestedString(state, "migrationUid"); !found || migrationUID != string(vmim.GetUID()) {
+			log.Warnf("VMI %s/%s migration state doesn't belong to VMIM %s", m.Namespace, m.VMIName, m.Name)
+			return
+		}
+	}
+	m.SourceNode, _, _ = unstructured.NestedString(state, "sourceNode")
+	m.TargetNode, _, _ = unstructured.NestedString(state, "targetNode")
+	m.Mode, _, _ = unstructured.NestedString(state, "mode")
+	m.MigrationPolicy, _, _ = unstructured.NestedString(state, "migrationPolicyName")
+	m.FailureReason, _, _ = unstructured.NestedString(state, "failureReason")
+	// Migrations failing before a target node is picked don't belong to any node pair
+	if m.SourceNode != "" && m.TargetNode != "" {
+		m.NodePair = m.SourceNode + "->" + m.TargetNode
+	}
+}
+
+// setDataProcessed reads from Prometheus the highest amount of data processed by the migration between its creation and a scrape interval after its completion
+func (v *vmimPhaseLatency) setDataProcessed(m *vmimPhaseMetric) {
+	if v.prometheus == nil || m.TotalLatency < 0 {
+		return
+	}
+	end := m.Timestamp.Add(time.Duration(m.TotalLatency)*time.Millisecond + time.Minute)
+	if now := time.Now().UTC(); end.After(now) {
+		end = now
+	}
+	query := fmt.Sprintf(vmimDataProcessedQuery, m.Namespace, m.VMIName, int(end.Sub(m.Timestamp).Seconds())+1)
+	value, err := v.prometheus.Query(query, end)
+	if err != nil {
+		log.Warnf("Unable to get the data processed by VMIM %s/%s: %v", m.Namespace, m.Name, err)
+		return
+	}
+	if vector, ok := value.(model.Vector); ok && len(vector) > 0 {
+		m.DataProcessedBytes = int64(vector[0].Value)
+	}
+}
+
+func isVMIMFinalPhase(phase string) bool {
+	return phase == vmimSucceededPhase || phase == vmimFailedPhase
+}
+
+// getVMIMPhaseTransitions returns the time each phase was reached
+func getVMIMPhaseTransitions(vmim *unstructured.Unstructured) map[string]time.Time {
+	transitions := make(map[string]time.Time)
+	phaseTransitions, _, _ := unstructured.NestedSlice(vmim.Object, "status", "phaseTransitionTimestamps")
+	for _, t := range phaseTransitions {
+		transition, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		phase, _ := transition["phase"].(string)
+		ts, _ := transition["phaseTransitionTimestamp"].(string)
+		if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+			transitions[phase] = parsed.UTC()
+		}
+	}
+	return transitions
+}
+
+// setVMIMPhaseDurations computes how long the migration stayed in each phase, the Pending phase starts with the migration creation
+func setVMIMPhaseDurations(m *vmimPhaseMetric, transitions map[string]time.Time) {
+	if _, ok := transitions[vmimPhases[0]]; !ok {
+		transitions[vmimPhases[0]] = m.Timestamp
+	}
+	durations := make([]int, len(vmimPhases))
+	for i, phase := range vmimPhases {
+		durations[i] = -1
+		start, reached := transitions[phase]
+		if !reached {
+			continue
+		}
+		for _, next := range slices.Concat(vmimPhases[i+1:], []string{vmimSucceededPhase, vmimFailedPhase}) {
+			if end, ok := transitions[next]; ok {
+				durations[i] = int(end.Sub(start).Milliseconds())
+				break
+			}
+		}
+	}
+	m.PendingDuration, m.SchedulingDuration, m.ScheduledDuration = durations[0], durations[1], durations[2]
+	m.PreparingTargetDuration, m.TargetReadyDuration, m.RunningDuration = durations[3], durations[4], durations[5]
+	m.TotalLatency = -1
+	for _, final := range []string{vmimSucceededPhase, vmimFailedPhase} {
+		if end, ok := transitions[final]; ok {
+			m.TotalLatency = int(end.Sub(m.Timestamp).Milliseconds())
+		}
+	}
+}
+
+func (v *vmimPhaseLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (v *vmimPhaseLatency) Stop() error {
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(v.stopCh)
+	summaries := make(map[string]*vmimPhaseMetric)
+	// Results of the policies migrated by this job, the comparison adds up every job
+	var jobComparison *VMIMPolicyComparison
+	if v.comparison != nil {
+		jobComparison = NewVMIMPolicyComparison()
+	}
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(vmimPhaseMetric)
+		v.setDataProcessed(&m)
+		v.Metrics.Store(key, m)
+		if v.comparison != nil {
+			v.comparison.add(m)
+			jobComparison.add(m)
+		}
+		if !isVMIMFinalPhase(m.Phase) {
+			log.Warnf("VMIM %s/%s didn't complete, last phase %s", m.Namespace, m.Name, m.Phase)
+		}
+		if m.NodePair == "" {
+			return true
+		}
+		summary, ok := summaries[m.NodePair]
+		if !ok {
+			summary = &vmimPhaseMetric{
+				Timestamp:      time.Now().UTC(),
+				MetricName:     vmimPhaseNodePairSummaryName,
+				SourceNode:     m.SourceNode,
+				TargetNode:     m.TargetNode,
+				NodePair:       m.NodePair,
+				FailureReasons: make(map[string]int),
+			}
+			summaries[m.NodePair] = summary
+		}
+		summary.Migrations++
+		if m.DataProcessedBytes > 0 {
+			summary.DataProcessedBytes += m.DataProcessedBytes
+		}
+		switch m.Phase {
+		case vmimSucceededPhase:
+			summary.Succeeded++
+		case vmimFailedPhase:
+			summary.Failed++
+			summary.FailureReasons[m.FailureReason]++
+		}
+		return true
+	})
+	for nodePair, summary := range summaries {
+		log.Infof("Migrations %s: %d total, %d succeeded, %d failed", nodePair, summary.Migrations, summary.Succeeded, summary.Failed)
+		v.Metrics.Store(vmimPhaseNodePairSummaryName+"/"+nodePair, *summary)
+	}
+	if jobComparison != nil {
+		for _, m := range jobComparison.metrics() {
+			v.Metrics.Store(vmimPolicyComparisonName+"/"+m.MigrationPolicy, m)
+		}
+	}
+	return v.StopMeasurement(v.normalizeMetrics, v.getLatency)
+}
+
+func (v *vmimPhaseLatency) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		switch m := value.(type) {
+		case vmimPhaseMetric:
+			m.UUID = v.Uuid
+			m.JobName = v.JobConfig.Name
+			m.Metadata = v.Metadata
+			v.NormLatencies = append(v.NormLatencies, m)
+		case vmimPolicyComparisonMetric:
+			m.UUID = v.Uuid
+			m.JobName = v.JobConfig.Name
+			m.Metadata = v.Metadata
+			v.NormLatencies = append(v.NormLatencies, m)
+		}
+		return true
+	})
+	return 0
+}
+
+// getLatency only accounts for successful migrations, failed ones are indexed with their failure reason.
+// Phases that weren't observed, reported as -1, are left out
+func (v *vmimPhaseLatency) getLatency(normLatency any) map[string]float64 {
+	m, ok := normLatency.(vmimPhaseMetric)
+	latencies := map[string]float64{}
+	if !ok || m.MetricName != vmimPhaseLatencyMeasurementName || m.Phase != vmimSucceededPhase {
+		return latencies
+	}
+	for name, latency := range map[string]int{
+		"PendingDuration":         m.PendingDuration,
+		"SchedulingDuration":      m.SchedulingDuration,
+		"ScheduledDuration":       m.ScheduledDuration,
+		"PreparingTargetDuration": m.PreparingTargetDuration,
+		"TargetReadyDuration":     m.TargetReadyDuration,
+		"RunningDuration":         m.RunningDuration,
+		"TotalLatency":            m.TotalLatency,
+	} {
+		if latency >= 0 {
+			latencies[name] = float64(latency)
+		}
+	}
+	return latencies
+}
+
+func (v *vmimPhaseLatency) IsCompatible() bool {
+	return slices.Contains(supportedVMIMPhaseLatencyJobTypes, v.JobConfig.JobType)
+}
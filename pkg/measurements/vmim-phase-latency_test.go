@@ -0,0 +1,117 @@
+package measurements
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestVMIMPhaseDurations(t *testing.T) {
+	vmim := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"phase": "Succeeded",
+			"phaseTransitionTimestamps": []any{
+				map[string]any{"phase": "Pending", "phaseTransitionTimestamp": "2025-01-01T10:00:00Z"},
+				map[string]any{"phase": "Scheduling", "phaseTransitionTimestamp": "2025-01-01T10:00:01Z"},
+				map[string]any{"phase": "Scheduled", "phaseTransitionTimestamp": "2025-01-01T10:00:04Z"},
+				map[string]any{"phase": "PreparingTarget", "phaseTransitionTimestamp": "2025-01-01T10:00:05Z"},
+				// TargetReady isn't always observed
+				map[string]any{"phase": "Running", "phaseTransitionTimestamp": "2025-01-01T10:00:07Z"},
+				map[string]any{"phase": "Succeeded", "phaseTransitionTimestamp": "2025-01-01T10:00:17Z"},
+			},
+		},
+	}}
+	m := vmimPhaseMetric{Timestamp: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)}
+	setVMIMPhaseDurations(&m, getVMIMPhaseTransitions(vmim))
+	expected := vmimPhaseMetric{
+		Timestamp:               m.Timestamp,
+		PendingDuration:         1000,
+		SchedulingDuration:      3000,
+		ScheduledDuration:       1000,
+		PreparingTargetDuration: 2000,
+		TargetReadyDuration:     -1,
+		RunningDuration:         10000,
+		TotalLatency:            17000,
+	}
+	if m.PendingDuration != expected.PendingDuration || m.SchedulingDuration != expected.SchedulingDuration ||
+		m.ScheduledDuration != expected.ScheduledDuration || m.PreparingTargetDuration != expected.PreparingTargetDuration ||
+		m.TargetReadyDuration != expected.TargetReadyDuration || m.RunningDuration != expected.RunningDuration ||
+		m.TotalLatency != expected.TotalLatency {
+		t.Errorf("expected durations %+v, got %+v", expected, m)
+	}
+}
+
+func TestVMIMPhaseDurationsInProgress(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := vmimPhaseMetric{Timestamp: created}
+	setVMIMPhaseDurations(&m, map[string]time.Time{
+		"Scheduling": created.Add(2 * time.Second),
+	})
+	if m.PendingDuration != 2000 {
+		t.Errorf("expected pending duration from the creation time, got %d", m.PendingDuration)
+	}
+	if m.SchedulingDuration != -1 || m.TotalLatency != -1 {
+		t.Errorf("expected unfinished phases to be -1, got scheduling %d total %d", m.SchedulingDuration, m.TotalLatency)
+	}
+}
+
+func TestVMIMPolicyComparisonTable(t *testing.T) {
+	comparison := NewVMIMPolicyComparison()
+	for _, latency := range []int{1000, 2000, 3000, 4000} {
+		comparison.add(vmimPhaseMetric{MigrationPolicy: "bw-64mi", Phase: vmimSucceededPhase, TotalLatency: latency})
+	}
+	comparison.add(vmimPhaseMetric{MigrationPolicy: "bw-64mi", Phase: vmimFailedPhase, FailureReason: "timeout"})
+	comparison.add(vmimPhaseMetric{Phase: "Running"})
+	lines := strings.Split(strings.TrimSpace(comparison.Table()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 policies, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if fields := strings.Fields(lines[1]); !slices.Equal(fields, []string{"bw-64mi", "4", "1", "0", "2s", "4s", "4s", "4s", "timeout", "(1)"}) {
+		t.Errorf("unexpected bw-64mi row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); !slices.Equal(fields, []string{vmimNoMigrationPolicy, "0", "0", "1", "-", "-", "-", "-"}) {
+		t.Errorf("unexpected row without policy %q", lines[2])
+	}
+}
+
+func TestVMIMMigrationStateNodePair(t *testing.T) {
+	v := &vmimPhaseLatency{}
+	vmim := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{
+			"migrationState": map[string]any{"sourceNode": "worker-0", "targetNode": "worker-1", "mode": "PreCopy"},
+		},
+	}}
+	m := vmimPhaseMetric{}
+	v.setMigrationState(&m, vmim)
+	if m.NodePair != "worker-0->worker-1" || m.Mode != "PreCopy" {
+		t.Errorf("unexpected node pair %q and mode %q", m.NodePair, m.Mode)
+	}
+	// Migrations failing before the target is scheduled don't have a target node
+	unstructured.RemoveNestedField(vmim.Object, "status", "migrationState", "targetNode")
+	m = vmimPhaseMetric{}
+	v.setMigrationState(&m, vmim)
+	if m.SourceNode != "worker-0" || m.NodePair != "" {
+		t.Errorf("expected only the source node, got source %q and node pair %q", m.SourceNode, m.NodePair)
+	}
+}
+
+func TestVMIMPolicyComparisonMetrics(t *testing.T) {
+	comparison := NewVMIMPolicyComparison()
+	for _, latency := range []int{4000, 1000, 3000, 2000} {
+		comparison.add(vmimPhaseMetric{MigrationPolicy: "bw-64mi", Phase: vmimSucceededPhase, TotalLatency: latency})
+	}
+	comparison.add(vmimPhaseMetric{MigrationPolicy: "bw-32mi", Phase: vmimFailedPhase, FailureReason: "timeout"})
+	metrics := comparison.metrics()
+	if len(metrics) != 2 {
+		t.Fatalf("expected a document per policy, got %+v", metrics)
+	}
+	if m := metrics[0]; m.MigrationPolicy != "bw-32mi" || m.Failed != 1 || m.FailureReasons["timeout"] != 1 || m.P99TotalLatency != -1 {
+		t.Errorf("unexpected bw-32mi document %+v", m)
+	}
+	if m := metrics[1]; m.MetricName != vmimPolicyComparisonName || m.Succeeded != 4 || m.P50TotalLatency != 2000 || m.P95TotalLatency != 4000 || m.MaxTotalLatency != 4000 {
+		t.Errorf("unexpected bw-64mi document %+v", m)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	vmimNoMigrationPolicy    = "none"
+	vmimPolicyComparisonName = "vmimPolicyComparison"
+)
+
+// VMIMPolicyComparison aggregates the vmimPhaseLatency results of every job per MigrationPolicy
+type VMIMPolicyComparison struct {
+	mu      sync.Mutex
+	results map[string]*vmimPolicyResult
+}
+
+// vmimPolicyComparisonMetric is the indexed result of a MigrationPolicy, latencies are -1 without successful migrations
+type vmimPolicyComparisonMetric struct {
+	Timestamp       time.Time      `json:"timestamp"`
+	MetricName      string         `json:"metricName"`
+	UUID            string         `json:"uuid"`
+	JobName         string         `json:"jobName,omitempty"`
+	Metadata        any            `json:"metadata,omitempty"`
+	MigrationPolicy string         `json:"migrationPolicy"`
+	Succeeded       int            `json:"succeeded"`
+	Failed          int            `json:"failed"`
+	Incomplete      int            `json:"incomplete"`
+	P50TotalLatency int            `json:"p50TotalLatency"`
+	P95TotalLatency int            `json:"p95TotalLatency"`
+	P99TotalLatency int            `json:"p99TotalLatency"`
+	MaxTotalLatency int            `json:"maxTotalLatency"`
+	FailureReasons  map[string]int `json:"failureReasons,omitempty"`
+}
+
+type vmimPolicyResult struct {
+	totalLatencies []int
+	failed         int
+	incomplete     int
+	failureReasons map[string]int
+}
+
+func NewVMIMPolicyComparison() *VMIMPolicyComparison {
+	return &VMIMPolicyComparison{results: make(map[string]*vmimPolicyResult)}
+}
+
+func (c *VMIMPolicyComparison) add(m vmimPhaseMetric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	policy := m.MigrationPolicy
+	if policy == "" {
+		policy = vmimNoMigrationPolicy
+	}
+	result, ok := c.results[policy]
+	if !ok {
+		result = &vmimPolicyResult{failureReasons: make(map[string]int)}
+		c.results[policy] = result
+	}
+	switch m.Phase {
+	case vmimSucceededPhase:
+		result.totalLatencies = append(result.totalLatencies, m.TotalLatency)
+	case vmimFailedPhase:
+		result.failed++
+		result.failureReasons[m.FailureReason]++
+	default:
+		result.incomplete++
+	}
+}
+
+// Table renders the migration durations and failures of each MigrationPolicy
+func (c *VMIMPolicyComparison) Table() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POLICY\tSUCCEEDED\tFAILED\tINCOMPLETE\tP50\tP95\tP99\tMAX\tFAILURE REASONS")
+	for _, policy := range slices.Sorted(maps.Keys(c.results)) {
+		result := c.results[policy]
+		slices.Sort(result.totalLatencies)
+		var reasons []string
+		for _, reason := range slices.Sorted(maps.Keys(result.failureReasons)) {
+			reasons = append(reasons, fmt.Sprintf("%s (%d)", reason, result.failureReasons[reason]))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", policy, len(result.totalLatencies), result.failed, result.incomplete,
+			percentileDuration(result.totalLatencies, 50), percentileDuration(result.totalLatencies, 95),
+			percentileDuration(result.totalLatencies, 99), percentileDuration(result.totalLatencies, 100),
+			strings.Join(reasons, ", "))
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// metrics returns the indexed result of every MigrationPolicy
+func (c *VMIMPolicyComparison) metrics() []vmimPolicyComparisonMetric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var metrics []vmimPolicyComparisonMetric
+	for _, policy := range slices.Sorted(maps.Keys(c.results)) {
+		result := c.results[policy]
+		slices.Sort(result.totalLatencies)
+		m := vmimPolicyComparisonMetric{
+			Timestamp:       time.Now().UTC(),
+			MetricName:      vmimPolicyComparisonName,
+			MigrationPolicy: policy,
+			Succeeded:       len(result.totalLatencies),
+			Failed:          result.failed,
+			Incomplete:      result.incomplete,
+			P50TotalLatency: -1,
+			P95TotalLatency: -1,
+			P99TotalLatency: -1,
+			MaxTotalLatency: -1,
+			FailureReasons:  maps.Clone(result.failureReasons),
+		}
+		if len(result.totalLatencies) > 0 {
+			m.P50TotalLatency = percentile(result.totalLatencies, 50)
+			m.P95TotalLatency = percentile(result.totalLatencies, 95)
+			m.P99TotalLatency = percentile(result.totalLatencies, 99)
+			m.MaxTotalLatency = percentile(result.totalLatencies, 100)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	volumeSnapshotReadyLatencyMeasurementName      = "volumeSnapshotReadyLatencyMeasurement"
+	volumeSnapshotReadyLatencyQuantilesMeasurement = "volumeSnapshotReadyLatencyQuantilesMeasurement"
+)
+
+var (
+	supportedVolumeSnapshotReadyLatencyJobTypes = []config.JobType{config.CreationJob, config.KubeVirtJob}
+	volumeSnapshotGVR                           = schema.GroupVersionResource{
+		Group:    "snapshot.storage.k8s.io",
+		Version:  "v1",
+		Resource: "volumesnapshots",
+	}
+	volumeSnapshotContentGVR = schema.GroupVersionResource{
+		Group:    "snapshot.storage.k8s.io",
+		Version:  "v1",
+		Resource: "volumesnapshotcontents",
+	}
+)
+
+type volumeSnapshotReadyMetric struct {
+	Timestamp             time.Time `json:"timestamp"`
+	MetricName            string    `json:"metricName"`
+	UUID                  string    `json:"uuid"`
+	JobName               string    `json:"jobName,omitempty"`
+	Namespace             string    `json:"namespace"`
+	Name                  string    `json:"volumeSnapshotName"`
+	SourcePVC             string    `json:"sourcePVC,omitempty"`
+	VolumeSnapshotClass   string    `json:"volumeSnapshotClass,omitempty"`
+	VolumeSnapshotContent string    `json:"volumeSnapshotContent,omitempty"`
+	Driver                string    `json:"driver,omitempty"`
+	Metadata              any       `json:"metadata,omitempty"`
+	ReadyToUse            bool      `json:"readyToUse"`
+	RestoreSize           int64     `json:"restoreSizeBytes"`
+	Error                 string    `json:"error,omitempty"`
+	// Milliseconds from the VolumeSnapshot creation, -1 when not reached
+	ContentCreatedLatency int `json:"contentCreatedLatency"`
+	ContentBoundLatency   int `json:"contentBoundLatency"`
+	SnapshotTakenLatency  int `json:"snapshotTakenLatency"`
+	ReadyToUseLatency     int `json:"readyToUseLatency"`
+}
+
+type volumeSnapshotReadyLatency struct {
+	measurements.BaseMeasurement
+	stopCh        chan struct{}
+	dynamicClient dynamic.Interface
+	startTime     time.Time
+}
+
+type volumeSnapshotReadyLatencyMeasurementFactory struct {
+	measurements.BaseMeasurementFactory
+}
+
+func NewVolumeSnapshotReadyLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any, labelSelector string) (measurements.MeasurementFactory, error) {
+	return volumeSnapshotReadyLatencyMeasurementFactory{
+		measurements.NewBaseMeasurementFactory(configSpec, measurement, metadata, labelSelector),
+	}, nil
+}
+
+func (vmf volumeSnapshotReadyLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) measurements.Measurement {
+	return &volumeSnapshotReadyLatency{
+		BaseMeasurement: vmf.NewBaseLatency(jobConfig, clientSet, restConfig, volumeSnapshotReadyLatencyMeasurementName, volumeSnapshotReadyLatencyQuantilesMeasurement, embedCfg),
+		dynamicClient:   dynamic.NewForConfigOrDie(restConfig),
+	}
+}
+
+func (v *volumeSnapshotReadyLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	v.LatencyQuantiles, v.NormLatencies = nil, nil
+	v.Metrics = sync.Map{}
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	v.startTime = time.Now().UTC().Truncate(time.Second)
+	v.stopCh = make(chan struct{})
+	// VolumeSnapshots created by VirtualMachineSnapshots or CDI aren't labeled by kube-burner, so every snapshot created during the job is tracked
+	namespace := v.JobConfig.Namespace
+	if v.JobConfig.NamespacedIterations {
+		namespace = metav1.NamespaceAll
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(v.dynamicClient, 0, namespace, nil)
+	vsInformer := factory.ForResource(volumeSnapshotGVR).Informer()
+	vsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: v.handleVolumeSnapshot,
+		UpdateFunc: func(oldObj, newObj any) {
+			v.handleVolumeSnapshot(newObj)
+		},
+	})
+	log.Infof("Starting VolumeSnapshot ready latency watcher for job %s", v.JobConfig.Name)
+	factory.Start(v.stopCh)
+	factory.WaitForCacheSync(v.stopCh)
+	return nil
+}
+
+// handleVolumeSnapshot records the first time each milestone of the VolumeSnapshot is observed
+func (v *volumeSnapshotReadyLatency) handleVolumeSnapshot(obj any) {
+	vs := obj.(*unstructured.Unstructured)
+	created := vs.GetCreationTimestamp().UTC()
+	if created.Before(v.startTime) {
+		return
+	}
+	now := time.Now().UTC()
+	var m volumeSnapshotReadyMetric
+	if val, ok := v.Metrics.Load(string(vs.GetUID())); ok {
+		m = val.(volumeSnapshotReadyMetric)
+		if m.ReadyToUse {
+			return
+		}
+	} else {
+		m = volumeSnapshotReadyMetric{
+			Timestamp:             created,
+			MetricName:            volumeSnapshotReadyLatencyMeasurementName,
+			Namespace:             vs.GetNamespace(),
+			Name:                  vs.GetName(),
+			ContentCreatedLatency: -1,
+			ContentBoundLatency:   -1,
+			SnapshotTakenLatency:  -1,
+			ReadyToUseLatency:     -1,
+		}
+		m.SourcePVC, _, _ = unstructured.NestedString(vs.Object, "spec", "source", "persistentVolumeClaimName")
+		m.VolumeSnapshotClass, _, _ = unstructured.NestedString(vs.Object, "spec", "volumeSnapshotClassName")
+	}
+	status, _, _ := unstructured.NestedMap(vs.Object, "status")
+	setVolumeSnapshotStatus(&m, status, now)
+	if m.VolumeSnapshotContent != "" && m.Driver == "" {
+		v.setContentInfo(&m)
+	}
+	if m.ReadyToUse {
+		log.Debugf("VolumeSnapshot %s/%s ready after %dms", m.Namespace, m.Name, m.ReadyToUseLatency)
+	}
+	v.Metrics.Store(string(vs.GetUID()), m)
+}
+
+// setVolumeSnapshotStatus updates the milestones reached according to the VolumeSnapshot status observed at the given time
+func setVolumeSnapshotStatus(m *volumeSnapshotReadyMetric, status map[string]any, observed time.Time) {
+	if content, _, _ := unstructured.NestedString(status, "boundVolumeSnapshotContentName"); content != "" && m.ContentBoundLatency == -1 {
+		m.VolumeSnapshotContent = content
+		m.ContentBoundLatency = int(observed.Sub(m.Timestamp).Milliseconds())
+	}
+	// creationTime is the time the storage system took the snapshot
+	if creationTime, _, _ := unstructured.NestedString(status, "creationTime"); creationTime != "" && m.SnapshotTakenLatency == -1 {
+		if taken, err := time.Parse(time.RFC3339, creationTime); err == nil {
+			m.SnapshotTakenLatency = int(taken.Sub(m.Timestamp).Milliseconds())
+		}
+	}
+	if restoreSize, _, _ := unstructured.NestedString(status, "restoreSize"); restoreSize != "" {
+		if quantity, err := resource.ParseQuantity(restoreSize); err == nil {
+			m.RestoreSize = quantity.Value()
+		}
+	}
+	if message, _, _ := unstructured.NestedString(status, "error", "message"); message != "" {
+		m.Error = message
+	}
+	if ready, _, _ := unstructured.NestedBool(status, "readyToUse"); ready {
+		m.ReadyToUse = true
+		m.ReadyToUseLatency = int(observed.Sub(m.Timestamp).Milliseconds())
+	}
+}
+
+// setContentInfo reads the CSI driver and creation time of the bound VolumeSnapshotContent
+func (v *volumeSnapshotReadyLatency) setContentInfo(m *volumeSnapshotReadyMetric) {
+	content, err := v.dynamicClient.Resource(volumeSnapshotContentGVR).Get(context.TODO(), m.VolumeSnapshotContent, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("Unable to get VolumeSnapshotContent %s: %v", m.VolumeSnapshotContent, err)
+		return
+	}
+	m.Driver, _, _ = unstructured.NestedString(content.Object, "spec", "driver")
+	m.ContentCreatedLatency = int(content.GetCreationTimestamp().UTC().Sub(m.Timestamp).Milliseconds())
+}
+
+func (v *volumeSnapshotReadyLatency) Collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+func (v *volumeSnapshotReadyLatency) Stop() error {
+	if v.JobConfig.SkipIndexing {
+		return nil
+	}
+	close(v.stopCh)
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(volumeSnapshotReadyMetric)
+		if !m.ReadyToUse {
+			log.Warnf("VolumeSnapshot %s/%s wasn't ready to use when the job finished: %s", m.Namespace, m.Name, m.Error)
+		}
+		return true
+	})
+	return v.StopMeasurement(v.normalizeMetrics, v.getLatency)
+}
+
+func (v *volumeSnapshotReadyLatency) normalizeMetrics() float64 {
+	v.Metrics.Range(func(key, value any) bool {
+		m := value.(volumeSnapshotReadyMetric)
+		m.UUID = v.Uuid
+		m.JobName = v.JobConfig.Name
+		m.Metadata = v.Metadata
+		v.NormLatencies = append(v.NormLatencies, m)
+		return true
+	})
+	return 0
+}
+
+// getLatency only accounts for snapshots ready to use, skipping the milestones that weren't observed
+func (v *volumeSnapshotReadyLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(volumeSnapshotReadyMetric)
+	latencies := map[string]float64{}
+	if !m.ReadyToUse {
+		return latencies
+	}
+	for name, latency := range map[string]int{
+		"ContentCreatedLatency": m.ContentCreatedLatency,
+		"ContentBoundLatency":   m.ContentBoundLatency,
+		"SnapshotTakenLatency":  m.SnapshotTakenLatency,
+		"ReadyToUseLatency":     m.ReadyToUseLatency,
+	} {
+		if latency >= 0 {
+			latencies[name] = float64(latency)
+		}
+	}
+	return latencies
+}
+
+func (v *volumeSnapshotReadyLatency) IsCompatible() bool {
+	return slices.Contains(supportedVolumeSnapshotReadyLatencyJobTypes, v.JobConfig.JobType)
+}
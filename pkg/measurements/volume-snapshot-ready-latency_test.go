@@ -0,0 +1,59 @@
+package measurements
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetVolumeSnapshotStatus(t *testing.T) {
+	created := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	m := volumeSnapshotReadyMetric{
+		Timestamp:             created,
+		ContentCreatedLatency: -1,
+		ContentBoundLatency:   -1,
+		SnapshotTakenLatency:  -1,
+		ReadyToUseLatency:     -1,
+	}
+	setVolumeSnapshotStatus(&m, map[string]any{
+		"boundVolumeSnapshotContentName": "snapcontent-1",
+		"readyToUse":                     false,
+	}, created.Add(time.Second))
+	if m.VolumeSnapshotContent != "snapcontent-1" || m.ContentBoundLatency != 1000 {
+		t.Errorf("expected content bound after 1000ms, got %s after %dms", m.VolumeSnapshotContent, m.ContentBoundLatency)
+	}
+	if m.ReadyToUse || m.ReadyToUseLatency != -1 {
+		t.Errorf("expected the snapshot not to be ready")
+	}
+	setVolumeSnapshotStatus(&m, map[string]any{
+		"boundVolumeSnapshotContentName": "snapcontent-1",
+		"creationTime":                   "2025-01-01T10:00:02Z",
+		"restoreSize":                    "1Gi",
+		"readyToUse":                     true,
+	}, created.Add(5*time.Second))
+	if m.ContentBoundLatency != 1000 {
+		t.Errorf("expected the first binding observation to be kept, got %dms", m.ContentBoundLatency)
+	}
+	if m.SnapshotTakenLatency != 2000 || m.ReadyToUseLatency != 5000 || !m.ReadyToUse {
+		t.Errorf("unexpected latencies taken %dms ready %dms", m.SnapshotTakenLatency, m.ReadyToUseLatency)
+	}
+	if m.RestoreSize != 1<<30 {
+		t.Errorf("expected restore size of 1Gi, got %d", m.RestoreSize)
+	}
+}
+
+func TestVolumeSnapshotGetLatency(t *testing.T) {
+	v := &volumeSnapshotReadyLatency{}
+	latencies := v.getLatency(volumeSnapshotReadyMetric{
+		ReadyToUse:            true,
+		ContentCreatedLatency: -1,
+		ContentBoundLatency:   -1,
+		SnapshotTakenLatency:  1500,
+		ReadyToUseLatency:     3000,
+	})
+	if _, ok := latencies["ContentCreatedLatency"]; ok || len(latencies) != 2 || latencies["ReadyToUseLatency"] != 3000 {
+		t.Errorf("expected only the observed milestones, got %v", latencies)
+	}
+	if latencies := v.getLatency(volumeSnapshotReadyMetric{ReadyToUseLatency: -1}); len(latencies) != 0 {
+		t.Errorf("expected no latencies for a snapshot not ready, got %v", latencies)
+	}
+}
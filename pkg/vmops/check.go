@@ -0,0 +1,140 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vmops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	CheckVMRunning = "check_vm_running"
+	CheckResize    = "check_resize"
+	// Wait up to ~60 minutes per VM, using shorter waits in the first retries
+	checkMaxRetries     = 130
+	checkMaxShortWaits  = 12
+	checkShortWait      = 5 * time.Second
+	checkLongWait       = 30 * time.Second
+	checkAttemptTimeout = time.Minute
+	rootDiskName        = "vda"
+	ignoredDiskSizeName = "1M"
+)
+
+// CheckOptions describes the guest verification run against every VM matching the label
+type CheckOptions struct {
+	Check            string
+	LabelKey         string
+	LabelValue       string
+	Namespace        string
+	PrivateKeyPath   string
+	User             string
+	ExpectedRootSize string
+	ExpectedDataSize string
+}
+
+type blockDevices struct {
+	BlockDevices []struct {
+		Name string `json:"name"`
+		Size string `json:"size"`
+	} `json:"blockdevices"`
+}
+
+// CheckVMs runs the requested check on every VM matching the options label, retrying each VM until it succeeds
+func (c *Client) CheckVMs(ctx context.Context, opts CheckOptions) error {
+	var check func(ctx context.Context, vm string) error
+	switch opts.Check {
+	case CheckVMRunning:
+		check = func(ctx context.Context, vm string) error {
+			_, err := c.RunCommand(ctx, opts.Namespace, vm, opts.User, opts.PrivateKeyPath, "ls")
+			return err
+		}
+	case CheckResize:
+		check = func(ctx context.Context, vm string) error {
+			output, err := c.RunCommand(ctx, opts.Namespace, vm, opts.User, opts.PrivateKeyPath, "lsblk --json -v --output=NAME,SIZE")
+			if err != nil {
+				return err
+			}
+			return verifyDiskSizes(output, opts.ExpectedRootSize, opts.ExpectedDataSize)
+		}
+	default:
+		return fmt.Errorf("unsupported check %s, supported checks are %s and %s", opts.Check, CheckVMRunning, CheckResize)
+	}
+	vms, err := c.dynamicClient.Resource(vmGVR).Namespace(opts.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", opts.LabelKey, opts.LabelValue),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get VM list: %w", err)
+	}
+	for _, vm := range vms.Items {
+		var checkErr error
+		for attempt := 1; attempt <= checkMaxRetries; attempt++ {
+			attemptCtx, cancel := context.WithTimeout(ctx, checkAttemptTimeout)
+			checkErr = check(attemptCtx, vm.GetName())
+			cancel()
+			if checkErr == nil {
+				break
+			}
+			log.Debugf("Attempt %d of %s for %s failed: %v", attempt, opts.Check, vm.GetName(), checkErr)
+			if attempt == checkMaxRetries {
+				break
+			}
+			wait := checkLongWait
+			if attempt < checkMaxShortWaits {
+				wait = checkShortWait
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		if checkErr != nil {
+			return fmt.Errorf("failed waiting on %s for %s: %w", opts.Check, vm.GetName(), checkErr)
+		}
+		log.Infof("%s finished successfully for %s", opts.Check, vm.GetName())
+	}
+	return nil
+}
+
+// verifyDiskSizes checks the lsblk output reports the expected root disk size and data disks sizes, skipping the cloud-init disk
+func verifyDiskSizes(lsblkOutput, expectedRootSize, expectedDataSize string) error {
+	var devices blockDevices
+	if err := json.Unmarshal([]byte(lsblkOutput), &devices); err != nil {
+		return fmt.Errorf("parsing lsblk output: %w", err)
+	}
+	rootFound := false
+	for _, device := range devices.BlockDevices {
+		switch {
+		case device.Name == rootDiskName:
+			if device.Size != expectedRootSize {
+				return fmt.Errorf("root disk size is %s, expected %s", device.Size, expectedRootSize)
+			}
+			rootFound = true
+		case device.Size == ignoredDiskSizeName:
+			continue
+		case device.Size != expectedDataSize:
+			return fmt.Errorf("data disk %s size is %s, expected %s", device.Name, device.Size, expectedDataSize)
+		}
+	}
+	if !rootFound {
+		return fmt.Errorf("root disk %s not found", rootDiskName)
+	}
+	return nil
+}
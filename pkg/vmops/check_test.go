@@ -0,0 +1,26 @@
+package vmops
+
+import "testing"
+
+func TestVerifyDiskSizes(t *testing.T) {
+	lsblk := `{"blockdevices": [
+		{"name": "vda", "size": "7G"},
+		{"name": "vdb", "size": "2G"},
+		{"name": "vdc", "size": "1M"}
+	]}`
+	if err := verifyDiskSizes(lsblk, "7G", "2G"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := verifyDiskSizes(lsblk, "6G", "2G"); err == nil {
+		t.Errorf("expected error for a root disk not resized")
+	}
+	if err := verifyDiskSizes(lsblk, "7G", "1G"); err == nil {
+		t.Errorf("expected error for a data disk not resized")
+	}
+	if err := verifyDiskSizes(`{"blockdevices": [{"name": "vdb", "size": "2G"}]}`, "7G", "2G"); err == nil {
+		t.Errorf("expected error when the root disk is missing")
+	}
+	if err := verifyDiskSizes("connection refused", "7G", "2G"); err == nil {
+		t.Errorf("expected error for an invalid lsblk output")
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vmops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	subresourcesGroupVersion = "subresources.kubevirt.io/v1"
+	// Subprotocol used by the KubeVirt port-forward and console subresources to carry raw data
+	portForwardSubprotocol = "plain.kubevirt.io"
+)
+
+var vmGVR = schema.GroupVersionResource{
+	Group:    "kubevirt.io",
+	Version:  "v1",
+	Resource: "virtualmachines",
+}
+
+// Client runs VirtualMachine operations through the KubeVirt subresource APIs
+type Client struct {
+	restConfig    *rest.Config
+	restClient    rest.Interface
+	dynamicClient dynamic.Interface
+	// Run SSH commands through virtctl ssh instead of the in-process client
+	UseVirtctl bool
+}
+
+func NewClient(restConfig *rest.Config) (*Client, error) {
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		restConfig:    restConfig,
+		restClient:    clientSet.Discovery().RESTClient(),
+		dynamicClient: dynamicClient,
+	}, nil
+}
+
+// Start starts the given VirtualMachine
+func (c *Client) Start(ctx context.Context, namespace, name string) error {
+	return c.vmSubresource(ctx, namespace, name, "start")
+}
+
+// Stop stops the given VirtualMachine
+func (c *Client) Stop(ctx context.Context, namespace, name string) error {
+	return c.vmSubresource(ctx, namespace, name, "stop")
+}
+
+// Restart restarts the given VirtualMachine
+func (c *Client) Restart(ctx context.Context, namespace, name string) error {
+	return c.vmSubresource(ctx, namespace, name, "restart")
+}
+
+// Migrate triggers the live migration of the given VirtualMachine
+func (c *Client) Migrate(ctx context.Context, namespace, name string) error {
+	return c.vmSubresource(ctx, namespace, name, "migrate")
+}
+
+func (c *Client) vmSubresource(ctx context.Context, namespace, name, subresource string) error {
+	err := c.restClient.Put().
+		AbsPath("/apis", subresourcesGroupVersion, "namespaces", namespace, "virtualmachines", name, subresource).
+		SetHeader("Content-Type", "application/json").
+		Body([]byte("{}")).
+		Do(ctx).
+		Error()
+	if err != nil {
+		return fmt.Errorf("%s VirtualMachine %s/%s: %w", subresource, namespace, name, err)
+	}
+	return nil
+}
+
+// DialVMI opens a TCP connection to the given port of the VMI through the KubeVirt port-forward subresource
+func (c *Client) DialVMI(ctx context.Context, namespace, name string, port int) (net.Conn, error) {
+	conn, err := c.dialVMISubresource(ctx, namespace, name, "portforward", strconv.Itoa(port), "tcp")
+	if err != nil {
+		return nil, fmt.Errorf("port-forward to %s/%s:%d: %w", namespace, name, port, err)
+	}
+	return conn, nil
+}
+
+// ConsoleVMI opens a stream to the serial console of the VMI through the KubeVirt console subresource
+func (c *Client) ConsoleVMI(ctx context.Context, namespace, name string) (net.Conn, error) {
+	conn, err := c.dialVMISubresource(ctx, namespace, name, "console")
+	if err != nil {
+		return nil, fmt.Errorf("serial console of %s/%s: %w", namespace, name, err)
+	}
+	return conn, nil
+}
+
+// dialVMISubresource opens a websocket to a streaming subresource of the VMI, carrying raw data
+func (c *Client) dialVMISubresource(ctx context.Context, namespace, name string, subresource ...string) (net.Conn, error) {
+	u, _, err := rest.DefaultServerUrlFor(c.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = path.Join(append([]string{u.Path, "/apis", subresourcesGroupVersion, "namespaces", namespace, "virtualmachineinstances", name}, subresource...)...)
+	tlsConfig, err := rest.TLSConfigFor(c.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := c.authHeaders()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: 30 * time.Second,
+		Subprotocols:     []string{portForwardSubprotocol},
+	}
+	ws, resp, err := dialer.DialContext(ctx, u.String(), headers)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("%w (%s)", err, resp.Status)
+		}
+		return nil, err
+	}
+	return &wsConn{Conn: ws}, nil
+}
+
+// authHeaders returns the authentication headers client-go would add to a request built from the rest config
+func (c *Client) authHeaders() (http.Header, error) {
+	capture := &headerCapture{}
+	rt, err := rest.HTTPWrappersForConfig(c.restConfig, capture)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, c.restConfig.Host, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := rt.RoundTrip(req); err != nil {
+		return nil, err
+	}
+	return capture.header, nil
+}
+
+type headerCapture struct {
+	header http.Header
+}
+
+func (h *headerCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.header = req.Header.Clone()
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+// wsConn adapts a binary websocket stream to net.Conn
+type wsConn struct {
+	*websocket.Conn
+	reader  io.Reader
+	writeMu sync.Mutex
+}
+
+func (c *wsConn) Read(b []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			_, reader, err := c.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			c.reader = reader
+		}
+		n, err := c.reader.Read(b)
+		if err == io.EOF {
+			c.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (c *wsConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.WriteMessage(websocket.BinaryMessage, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *wsConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
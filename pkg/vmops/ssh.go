@@ -0,0 +1,108 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vmops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const sshPort = 22
+
+// Newer virtctl versions require --local-ssh to use the ssh binary
+var virtctlSupportsLocalSSH = sync.OnceValue(func() bool {
+	output, err := exec.Command("virtctl", "ssh", "--help").CombinedOutput()
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(output), "--local-ssh ")
+})
+
+// RunCommand runs command in the guest of the given VMI through SSH and returns its standard output
+func (c *Client) RunCommand(ctx context.Context, namespace, name, user, privateKeyPath, command string) (string, error) {
+	if c.UseVirtctl {
+		return runVirtctlCommand(ctx, namespace, name, user, privateKeyPath, command)
+	}
+	privateKey, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return "", err
+	}
+	signer, err := ssh.ParsePrivateKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("parsing private key %s: %w", privateKeyPath, err)
+	}
+	conn, err := c.DialVMI(ctx, namespace, name, sshPort)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	// Unblock the SSH handshake and session when the context is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, fmt.Sprintf("vmi/%s.%s:%d", name, namespace, sshPort), &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		// Guests are ephemeral test VMs, same as StrictHostKeyChecking=no in virtctl ssh
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("ssh to %s/%s: %w", namespace, name, err)
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	defer client.Close()
+	session, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer session.Close()
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err := session.Run(command); err != nil {
+		return stdout.String(), fmt.Errorf("running %q in %s/%s: %w: %s", command, namespace, name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func runVirtctlCommand(ctx context.Context, namespace, name, user, privateKeyPath, command string) (string, error) {
+	args := []string{"ssh"}
+	if virtctlSupportsLocalSSH() {
+		args = append(args, "--local-ssh")
+	}
+	args = append(args,
+		"--local-ssh-opts=-o StrictHostKeyChecking=no",
+		"--local-ssh-opts=-o UserKnownHostsFile=/dev/null",
+		"-n", namespace,
+		"-i", privateKeyPath,
+		"--username", user,
+		"-c", command,
+		"vmi/"+name,
+	)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "virtctl", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("running %q in %s/%s: %w: %s", command, namespace, name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
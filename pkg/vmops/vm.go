@@ -0,0 +1,125 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vmops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// VM returns a command running VirtualMachine operations through the KubeVirt API, used by the virt workloads hooks instead of virtctl
+func VM() *cobra.Command {
+	var useVirtctl bool
+	cmd := &cobra.Command{
+		Use:   "vm",
+		Short: "Runs VirtualMachine operations without requiring virtctl",
+	}
+	cmd.PersistentFlags().BoolVar(&useVirtctl, "use-virtctl", false, "Run SSH commands through virtctl ssh instead of the built-in client")
+	newClient := func() (*Client, error) {
+		_, restConfig := config.NewKubeClientProvider("", "").DefaultClientSet()
+		client, err := NewClient(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		client.UseVirtctl = useVirtctl
+		return client, nil
+	}
+	ops := map[string]func(*Client, context.Context, string, string) error{
+		"start":   (*Client).Start,
+		"stop":    (*Client).Stop,
+		"restart": (*Client).Restart,
+		"migrate": (*Client).Migrate,
+	}
+	for op, run := range ops {
+		var namespace string
+		opCmd := &cobra.Command{
+			Use:          op + " <vm>",
+			Short:        fmt.Sprintf("Sends a %s request for the given VirtualMachine", op),
+			Args:         cobra.ExactArgs(1),
+			SilenceUsage: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				client, err := newClient()
+				if err != nil {
+					return err
+				}
+				return run(client, cmd.Context(), namespace, args[0])
+			},
+		}
+		opCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "VirtualMachine namespace")
+		cmd.AddCommand(opCmd)
+	}
+	cmd.AddCommand(sshCommand(newClient), checkCommand(newClient))
+	return cmd
+}
+
+func sshCommand(newClient func() (*Client, error)) *cobra.Command {
+	var namespace, identityFile, user, command string
+	cmd := &cobra.Command{
+		Use:          "ssh <vmi>",
+		Short:        "Runs a command in the guest of the given VirtualMachineInstance",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			output, err := client.RunCommand(cmd.Context(), namespace, args[0], user, identityFile, command)
+			fmt.Fprint(cmd.OutOrStdout(), output)
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "VirtualMachineInstance namespace")
+	cmd.Flags().StringVarP(&identityFile, "identity-file", "i", "", "Private key used to authenticate")
+	cmd.Flags().StringVar(&user, "username", "fedora", "Guest user")
+	cmd.Flags().StringVarP(&command, "command", "c", "", "Command to run in the guest")
+	cmd.MarkFlagRequired("identity-file")
+	cmd.MarkFlagRequired("command")
+	return cmd
+}
+
+// checkCommand takes the same positional arguments as the former check.sh script used by the hooks
+func checkCommand(newClient func() (*Client, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:          "check <check_vm_running|check_resize> <label-key> <label-value> <namespace> <identity-file> <user> [<root-size> <data-size>]",
+		Short:        "Waits until the guests of the VirtualMachines matching the label pass the given check",
+		Args:         cobra.RangeArgs(6, 8),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := CheckOptions{
+				Check:          args[0],
+				LabelKey:       args[1],
+				LabelValue:     args[2],
+				Namespace:      args[3],
+				PrivateKeyPath: args[4],
+				User:           args[5],
+			}
+			if opts.Check == CheckResize {
+				if len(args) != 8 {
+					return fmt.Errorf("%s requires the expected root and data volume sizes", CheckResize)
+				}
+				opts.ExpectedRootSize, opts.ExpectedDataSize = args[6], args[7]
+			}
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			return client.CheckVMs(cmd.Context(), opts)
+		},
+	}
+}
@@ -15,24 +15,22 @@
 package workloads
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/rest"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
 type PodInfo struct {
@@ -40,28 +38,31 @@ type PodInfo struct {
 	IP   string
 }
 
-// AdminNetworkPolicy GVR (Group Version Resource)
-var anpGVR = schema.GroupVersionResource{
-	Group:    "policy.networking.k8s.io",
-	Version:  "v1alpha1",
-	Resource: "adminnetworkpolicies",
+var anpMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"anpLatency": measurements.NewANPLatencyMeasurementFactory,
 }
 
-func getNamespacesByPrefix(prefix string) ([]string, error) {
-	kubeClientProvider := config.NewKubeClientProvider("", "")
-	clientSet, _ := kubeClientProvider.ClientSet(0, 0)
-	nsList, err := clientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+// anpGeneratorOptions holds the flags driving the per-tenant policy generation
+type anpGeneratorOptions struct {
+	sourceNsPrefix  string
+	targetNamespace string
+	allowPodPattern string
+	allowPort       string
+	denyPodPattern  string
+	denyPort        string
+	nsPerTenant     int
+	ipsPerRule      int
+	banp            bool
+}
 
-	if err != nil {
-		return nil, err
+func (o anpGeneratorOptions) validate() error {
+	if o.sourceNsPrefix == "" || o.targetNamespace == "" {
+		return fmt.Errorf("please specify targetNamespace or sourceNsPrefix")
 	}
-	var ns []string
-	for _, item := range nsList.Items {
-		if strings.HasPrefix(item.Name, prefix) {
-			ns = append(ns, item.Name)
-		}
+	if o.nsPerTenant < 1 || o.ipsPerRule < 1 {
+		return fmt.Errorf("namespaces per tenant and IPs per rule must be >= 1")
 	}
-	return ns, nil
+	return nil
 }
 
 func getPodsByNamespaceAndPattern(namespace, pattern string) ([]PodInfo, error) {
@@ -81,221 +82,82 @@ func getPodsByNamespaceAndPattern(namespace, pattern string) ([]PodInfo, error)
 	return pods, nil
 }
 
-// Apply AdminNetworkPolicy using Dynamic Client
-func applyWithDynamicClient(config *rest.Config, yamlString string) error {
-	// Create dynamic client
-	dynamicClient, err := dynamic.NewForConfig(config)
-	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %v", err)
-	}
-
-	// Parse YAML to unstructured object
-	obj := &unstructured.Unstructured{}
-	dec := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-	_, _, err = dec.Decode([]byte(yamlString), nil, obj)
-	if err != nil {
-		return fmt.Errorf("failed to decode YAML: %v", err)
-	}
-
-	// Get the resource interface for AdminNetworkPolicy
-	anpClient := dynamicClient.Resource(anpGVR)
-	ctx := context.TODO()
-	name := obj.GetName()
-
-	existing, err := anpClient.Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		// Resource doesn't exist, create it
-		_, err = anpClient.Create(ctx, obj, metav1.CreateOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to create AdminNetworkPolicy: %v", err)
-		}
-		log.Info("Created AdminNetworkPolicy/", name)
-	} else {
-		// Resource exists, update it
-		obj.SetResourceVersion(existing.GetResourceVersion())
-		_, err = anpClient.Update(ctx, obj, metav1.UpdateOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to update AdminNetworkPolicy: %v", err)
+// chunkPodIPs splits the pod IPs in groups of size, each group is rendered as a policy rule
+func chunkPodIPs(pods []PodInfo, size int) [][]string {
+	chunks := [][]string{}
+	for i := 0; i < len(pods); i += size {
+		var ips []string
+		for j := i; j < i+size && j < len(pods); j++ {
+			ips = append(ips, pods[j].IP)
 		}
-		log.Info("Applied AdminNetworkPolicy/", name)
+		chunks = append(chunks, ips)
 	}
-	return nil
+	return chunks
 }
 
-func verifyAdminNetworkPolicies(config *rest.Config, expectedANPs int) error {
-	log.Info("Verifying created objects")
-	dynamicClient, err := dynamic.NewForConfig(config)
+// getANPTargetRules groups the IPs of the allowed and denied pods of the target namespace in rules, the target
+// pods exist before the run, so the tenant policies are rendered by kube-burner along with the rest of the objects
+func getANPTargetRules(opts anpGeneratorOptions) (allowRules, denyRules [][]string, err error) {
+	allowPods, err := getPodsByNamespaceAndPattern(opts.targetNamespace, opts.allowPodPattern)
 	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %v", err)
+		return nil, nil, fmt.Errorf("failed to list pods in namespace %s: %w", opts.targetNamespace, err)
 	}
-
-	anpList, err := dynamicClient.Resource(anpGVR).List(context.TODO(), metav1.ListOptions{})
+	denyPods, err := getPodsByNamespaceAndPattern(opts.targetNamespace, opts.denyPodPattern)
 	if err != nil {
-		return fmt.Errorf("failed to list adminnetworkpolicies: %v", err)
+		return nil, nil, fmt.Errorf("failed to list pods in namespace %s: %w", opts.targetNamespace, err)
 	}
-
-	for _, item := range anpList.Items {
-		status, found, err := unstructured.NestedMap(item.Object, "status")
-		resourceName := item.GetName()
-		if err != nil || !found {
-			log.Debug("status not found for adminnetworkpolicies", item.GetName())
-			continue
-		} else {
-			// For status.conditions pattern
-			conditions := status["conditions"].([]interface{})
-			reason := conditions[0].(map[string]interface{})["reason"].(string)
-			log.Debug("adminnetworkpolicies: ", resourceName, " ", reason)
-		}
+	if len(allowPods) == 0 && len(denyPods) == 0 {
+		return nil, nil, fmt.Errorf("no pod matching %s or %s was found in namespace %s", opts.allowPodPattern, opts.denyPodPattern, opts.targetNamespace)
 	}
-	log.Info("adminnetworkpolicies found: ", len(anpList.Items), " Expected: ", expectedANPs)
-	if len(anpList.Items) == 0 || len(anpList.Items) != expectedANPs {
-		return fmt.Errorf("no adminnetworkpolicies found or mismatch expect number of ANPs.: %v", err)
-	}
-	return nil
+	return chunkPodIPs(allowPods, opts.ipsPerRule), chunkPodIPs(denyPods, opts.ipsPerRule), nil
 }
 
-func generateCidrSelectorAnpMultiPolicyWithMultiRulesMultiIPsByTenant(
-	sourceNsPrefix, targetNsPrefix string,
-	targetNsAllowPod string,
-	targetNsAllowPort string,
-	targetNsDenyPod string,
-	targetNsDenyPort string,
-	totalNsByTenant int,
-	totalIpBlockNumByRule int,
-) error {
-	if sourceNsPrefix == "" || targetNsPrefix == "" {
-		return fmt.Errorf("please specify targetNsPrefix or sourceNsPrefix")
-	}
-
-	sourceNsList, err := getNamespacesByPrefix(sourceNsPrefix)
-	if err != nil || len(sourceNsList) == 0 {
-		return fmt.Errorf("no source namespace with prefix %s was found", sourceNsPrefix)
-	}
-
-	// For simplicity, only use the first source ns
-	tenantID := 1
-	priority := 1
-	newTenant := true
-	kubeClientProvider := config.NewKubeClientProvider("", "")
-	clientSet, restConfig := kubeClientProvider.ClientSet(0, 0)
-
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %v", err)
-	}
-
-	anpList, err := dynamicClient.Resource(anpGVR).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to list AdminNetworkPolicies: %v", err)
-	}
-
-	currentANPs := len(anpList.Items)
-	// For each tenant, generate a YAML
-	for nsIdx, sns := range sourceNsList {
-		if nsIdx%totalNsByTenant == 0 && nsIdx != 0 {
-			tenantID++
-			priority++
-			newTenant = true
-			if priority > 99 {
-				priority = 1
-			}
-		}
-		// Label the namespace
-		patchOps := []byte(`[{"op": "add", "path": "/metadata/labels/customer_tenant", "value": "tenant` + fmt.Sprintf("%d", tenantID) + `"}]`)
-
-		_, err = clientSet.CoreV1().Namespaces().Patch(context.TODO(), sns, types.JSONPatchType, patchOps, metav1.PatchOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to patch namespace %s: %v", sns, err)
-		}
-
-		// Get pods in target namespace
-		allowPods, _ := getPodsByNamespaceAndPattern(targetNsPrefix, targetNsAllowPod)
-		denyPods, _ := getPodsByNamespaceAndPattern(targetNsPrefix, targetNsDenyPod)
-
-		var yaml bytes.Buffer
-		fmt.Fprintf(&yaml, `apiVersion: policy.networking.k8s.io/v1alpha1
-kind: AdminNetworkPolicy
-metadata:
-  name: anp-cidr-selector-policy-rules-%s-to-%s-network-tenant%d-p%d
-spec:
-  priority: %d
-  subject:
-    namespaces:
-      matchLabels:
-        customer_tenant: tenant%d
-  ingress:
-  - name: "all-ingress-from-same-tenant"  
-    action: Allow   # Allows connection 
-    from:
-    - namespaces:
-        matchLabels:
-          customer_tenat: tenant%d
-  egress:                           
-  - name: "pass-egress-to-cluster-network"
-    action: "Pass"
-    ports:
-      - portNumber:
-          port: 9093
-          protocol: TCP
-      - portNumber:
-          port: 9094
-          protocol: TCP    
-    to:
-    - networks:
-      - 10.128.0.0/14
-`, sourceNsPrefix, targetNsPrefix, tenantID, priority, priority, tenantID, tenantID)
-
-		// Allow rules for allowPods
-		appRuleIndex := 1
-		for i := 0; i < len(allowPods); i += totalIpBlockNumByRule {
-			fmt.Fprintf(&yaml, "  - name: \"allow-egress-to-%s-network-%d\"\n    action: \"Allow\"\n    ports:\n      - portNumber:\n          port: %s\n      - portNumber:\n          port: 8080\n          protocol: TCP\n      - portRange:\n          start: 9201\n          end: 9205\n          protocol: TCP\n    to:\n    - networks:\n", targetNsPrefix, appRuleIndex, targetNsAllowPort)
-			for j := i; j < i+totalIpBlockNumByRule && j < len(allowPods); j++ {
-				fmt.Fprintf(&yaml, "      - %s/32\n", allowPods[j].IP)
-			}
-			appRuleIndex++
-		}
-
-		// Deny rules for denyPods
-		dbRuleIndex := 1
-		for i := 0; i < len(denyPods); i += totalIpBlockNumByRule {
-			fmt.Fprintf(&yaml, "  - name: \"deny-egress-to-%s-network-%d\"\n    action: \"Deny\"\n    ports:\n      - portNumber:\n          port: %s\n      - portNumber:\n          port: 5432\n          protocol: TCP\n      - portNumber:\n          port: 60000\n          protocol: TCP\n      - portNumber:\n          port: 9099\n          protocol: TCP\n      - portNumber:\n          port: 9393\n          protocol: TCP\n    to:\n    - networks:\n", targetNsPrefix, dbRuleIndex, targetNsDenyPort)
-			for j := i; j < i+totalIpBlockNumByRule && j < len(denyPods); j++ {
-				fmt.Fprintf(&yaml, "      - %s/32\n", denyPods[j].IP)
-			}
-			dbRuleIndex++
-		}
-
-		// Optionally, print YAML to stdout for debugging
-		if newTenant {
-			newTenant = false
-			// Using Dynamic Client to apply the YAML
-			if err := applyWithDynamicClient(restConfig, yaml.String()); err != nil {
-				log.Fatalf("Error applying with dynamic client: %v", err)
-			}
+// probeTargets returns the space separated ip:port list probed by the connectivity probe pods
+func probeTargets(rules [][]string, port string) string {
+	var targets []string
+	for _, ips := range rules {
+		for _, ip := range ips {
+			targets = append(targets, net.JoinHostPort(ip, port))
 		}
 	}
-
-	verifyAdminNetworkPolicies(restConfig, tenantID+currentANPs)
-	return nil
+	return strings.Join(targets, " ")
 }
 
-// NewUDNDensityPods holds udn-density-pods workload
+// NewANPDensityPods holds anp-density-pods workload
 func NewANPDensityPods(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	var churnPercent, churnCycles, iterations int
 	var svcLatency, simple, pprof bool
 	var jobPause time.Duration
 	var churnDelay, churnDuration, podReadyThreshold, pprofInterval time.Duration
 	var metricsProfiles []string
+	var generatorOpts anpGeneratorOptions
+	var anpLatency, connectivityProbe bool
+	var anpReadyTimeout time.Duration
 	var rc int
 	cmd := &cobra.Command{
 		Use:   variant,
 		Short: fmt.Sprintf("Runs %v workload", variant),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if err := generatorOpts.validate(); err != nil {
+				log.Fatal(err)
+			}
+			if connectivityProbe && !anpLatency {
+				log.Fatal("--connectivity-probe requires --anp-latency")
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			setMetrics(cmd, metricsProfiles)
 
 			if churnDuration > 0 || churnCycles > 0 {
 				log.Info("Churn is enabled, there will not be a pause after UDN creation")
 			}
+			allowRules, denyRules, err := getANPTargetRules(generatorOpts)
+			if err != nil {
+				log.Fatalf("Error generating AdminNetworkPolicy rules: %v", err)
+			}
+			// Rules are passed as JSON, a valid YAML flow sequence of IP lists
+			allowRulesJSON, _ := json.Marshal(allowRules)
+			denyRulesJSON, _ := json.Marshal(denyRules)
 
 			AdditionalVars["PPROF"] = pprof
 			AdditionalVars["PPROF_INTERVAL"] = pprofInterval.String()
@@ -307,31 +169,22 @@ func NewANPDensityPods(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 			AdditionalVars["JOB_ITERATIONS"] = iterations
 			AdditionalVars["POD_READY_THRESHOLD"] = podReadyThreshold
 			AdditionalVars["SVC_LATENCY"] = svcLatency
+			AdditionalVars["SOURCE_NS_PREFIX"] = generatorOpts.sourceNsPrefix
+			AdditionalVars["TARGET_NAMESPACE"] = generatorOpts.targetNamespace
+			AdditionalVars["ALLOW_PORT"] = generatorOpts.allowPort
+			AdditionalVars["DENY_PORT"] = generatorOpts.denyPort
+			AdditionalVars["ALLOW_RULES"] = string(allowRulesJSON)
+			AdditionalVars["DENY_RULES"] = string(denyRulesJSON)
+			AdditionalVars["NAMESPACES_PER_TENANT"] = generatorOpts.nsPerTenant
+			AdditionalVars["TENANTS"] = (iterations + generatorOpts.nsPerTenant - 1) / generatorOpts.nsPerTenant
+			AdditionalVars["BANP"] = generatorOpts.banp
+			AdditionalVars["ANP_LATENCY"] = anpLatency
+			AdditionalVars["CONNECTIVITY_PROBE"] = connectivityProbe
+			AdditionalVars["ANP_READY_TIMEOUT"] = anpReadyTimeout
+			AdditionalVars["ANP_ALLOW_TARGETS"] = probeTargets(allowRules, generatorOpts.allowPort)
+			AdditionalVars["ANP_DENY_TARGETS"] = probeTargets(denyRules, generatorOpts.denyPort)
+			wh.SetMeasurements(anpMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
-
-			sourceNsPrefix := "anp-cidr"
-			targetNsPrefix := "openshift-monitoring"
-			targetNsAllowPod := "node-exporter"
-			targetNsAllowPort := "9100"
-			targetNsDenyPod := "prometheus-k8s"
-			targetNsDenyPort := "9091"
-			totalNsByTenant := 3
-			totalIpBlockNumByRule := 3
-
-			err := generateCidrSelectorAnpMultiPolicyWithMultiRulesMultiIPsByTenant(
-				sourceNsPrefix,
-				targetNsPrefix,
-				targetNsAllowPod,
-				targetNsAllowPort,
-				targetNsDenyPod,
-				targetNsDenyPort,
-				totalNsByTenant,
-				totalIpBlockNumByRule,
-			)
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
-			}
-			log.Infof("👋 kube-burner run completed with rc %d for UUID %s", rc, wh.UUID)
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
 			os.Exit(rc)
@@ -348,6 +201,18 @@ func NewANPDensityPods(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	cmd.Flags().IntVar(&iterations, "iterations", 0, "Iterations")
 	cmd.Flags().BoolVar(&svcLatency, "service-latency", false, "Enable service latency measurement")
 	cmd.Flags().DurationVar(&podReadyThreshold, "pod-ready-threshold", 0, "Pod ready timeout threshold")
+	cmd.Flags().StringVar(&generatorOpts.sourceNsPrefix, "source-ns-prefix", "anp-cidr", "Prefix of the namespaces grouped in tenants by the CIDR selector AdminNetworkPolicies")
+	cmd.Flags().StringVar(&generatorOpts.targetNamespace, "target-namespace", "openshift-monitoring", "Namespace of the pods targeted by the CIDR selector rules")
+	cmd.Flags().StringVar(&generatorOpts.allowPodPattern, "allow-pod-pattern", "node-exporter", "Name pattern of the target pods allowed by the generated rules")
+	cmd.Flags().StringVar(&generatorOpts.allowPort, "allow-port", "9100", "Port of the target pods allowed by the generated rules")
+	cmd.Flags().StringVar(&generatorOpts.denyPodPattern, "deny-pod-pattern", "prometheus-k8s", "Name pattern of the target pods denied by the generated rules")
+	cmd.Flags().StringVar(&generatorOpts.denyPort, "deny-port", "9091", "Port of the target pods denied by the generated rules")
+	cmd.Flags().IntVar(&generatorOpts.nsPerTenant, "namespaces-per-tenant", 3, "Number of source namespaces grouped in each tenant, one AdminNetworkPolicy is generated per tenant")
+	cmd.Flags().IntVar(&generatorOpts.ipsPerRule, "ips-per-rule", 3, "Number of target pod IPs per generated rule")
+	cmd.Flags().BoolVar(&generatorOpts.banp, "banp", false, "Create the default BaselineAdminNetworkPolicy with allow and deny rules to the target pods instead of the static one")
+	cmd.Flags().BoolVar(&anpLatency, "anp-latency", false, "Measure the time taken by the created AdminNetworkPolicies to be programmed in all the nodes")
+	cmd.Flags().BoolVar(&connectivityProbe, "connectivity-probe", false, "Probe the allow and deny rules from every source namespace once the policies are programmed, requires --anp-latency")
+	cmd.Flags().DurationVar(&anpReadyTimeout, "anp-ready-timeout", 5*time.Minute, "Maximum time to wait for the AdminNetworkPolicies to be programmed")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
 	cmd.MarkFlagRequired("iterations")
 	return cmd
@@ -0,0 +1,100 @@
+package workloads
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+)
+
+// anpTemplateFuncs mimics the integer sprig functions used by the policy templates
+var anpTemplateFuncs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+	"mul": func(a, b int) int { return a * b },
+	"mod": func(a, b int) int { return a % b },
+	"until": func(n int) []int {
+		r := make([]int, n)
+		for i := range r {
+			r[i] = i
+		}
+		return r
+	},
+}
+
+func renderANPObjectTemplate(t *testing.T, file string, inputVars map[string]any) *unstructured.Unstructured {
+	t.Helper()
+	policyTemplate, err := os.ReadFile(filepath.Join("..", "..", "cmd", "config", "anp-density-pods", file))
+	if err != nil {
+		t.Fatalf("failed reading %s: %v", file, err)
+	}
+	tpl, err := template.New(file).Option("missingkey=error").Funcs(anpTemplateFuncs).Parse(string(policyTemplate))
+	if err != nil {
+		t.Fatalf("failed parsing %s: %v", file, err)
+	}
+	var policy bytes.Buffer
+	if err := tpl.Execute(&policy, inputVars); err != nil {
+		t.Fatalf("failed rendering %s: %v", file, err)
+	}
+	obj := &unstructured.Unstructured{}
+	if _, _, err := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme).Decode(policy.Bytes(), nil, obj); err != nil {
+		t.Fatalf("rendered %s is not valid YAML: %v\n%s", file, err, policy.String())
+	}
+	return obj
+}
+
+func TestRenderANPTenantTemplate(t *testing.T) {
+	// Rules reach the templates as the JSON documents passed through the job inputVars
+	var allowRules, denyRules []any
+	allowJSON, _ := json.Marshal(chunkPodIPs([]PodInfo{{IP: "10.128.0.1"}, {IP: "10.128.0.2"}, {IP: "10.128.0.3"}}, 2))
+	denyJSON, _ := json.Marshal(chunkPodIPs([]PodInfo{{IP: "10.129.0.1"}}, 2))
+	if err := json.Unmarshal(allowJSON, &allowRules); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(denyJSON, &denyRules); err != nil {
+		t.Fatal(err)
+	}
+	inputVars := map[string]any{
+		"Iteration":           1,
+		"sourceNsPrefix":      "tenant-src",
+		"sourceNamespaces":    5,
+		"namespacesPerTenant": 3,
+		"targetNamespace":     "openshift-monitoring",
+		"allowPort":           "9100",
+		"denyPort":            "9091",
+		"allowRules":          allowRules,
+		"denyRules":           denyRules,
+	}
+	for file, wantEgressRules := range map[string]int{
+		"anp-cidr-tenant.yml":   4, // pass + 2 allow + 1 deny
+		"banp-cidr-tenants.yml": 6, // 2 allow + 1 deny + 3 default deny
+	} {
+		obj := renderANPObjectTemplate(t, file, inputVars)
+		egress, _, _ := unstructured.NestedSlice(obj.Object, "spec", "egress")
+		if len(egress) != wantEgressRules {
+			t.Fatalf("expected %d egress rules in %s, got %d", wantEgressRules, file, len(egress))
+		}
+	}
+	// The second tenant groups the remaining source namespaces
+	obj := renderANPObjectTemplate(t, "anp-cidr-tenant.yml", inputVars)
+	expressions, _, _ := unstructured.NestedSlice(obj.Object, "spec", "subject", "namespaces", "matchExpressions")
+	if len(expressions) != 1 {
+		t.Fatalf("expected a single subject expression, got %v", expressions)
+	}
+	values, _, _ := unstructured.NestedStringSlice(expressions[0].(map[string]any), "values")
+	if len(values) != 2 || values[0] != "tenant-src-3" || values[1] != "tenant-src-4" {
+		t.Errorf("unexpected tenant namespaces %v", values)
+	}
+	if tenant := obj.GetLabels()["kube-burner.io/anp-tenant"]; tenant != "tenant2" {
+		t.Errorf("unexpected tenant label %s", tenant)
+	}
+	banp := renderANPObjectTemplate(t, "banp-cidr-tenants.yml", inputVars)
+	expressions, _, _ = unstructured.NestedSlice(banp.Object, "spec", "subject", "namespaces", "matchExpressions")
+	if values, _, _ := unstructured.NestedStringSlice(expressions[0].(map[string]any), "values"); values[0] != "tenant-src" {
+		t.Errorf("expected the BaselineAdminNetworkPolicy to select the source namespaces prefix, got %v", values)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/prometheus"
+	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
+)
+
+// capacitySearch looks for the highest size of a density workload meeting its SLO,
+// growing the size geometrically until a probe fails and then bisecting between the last passing and the first failing sizes
+type capacitySearch struct {
+	enabled   bool
+	sizeName  string
+	factor    float64
+	max       int
+	precision float64
+	// Lowest size the workload can run with, 1 when not set
+	min                   int
+	sloLatencyExpressions []string
+	sloLatencies          []latencyStopCondition
+	sloPromQL             []string
+}
+
+// capacityProbe is the outcome of a run of the workload with a given size
+type capacityProbe struct {
+	size       int
+	rc         int
+	duration   time.Duration
+	violations []string
+	evidence   []string
+}
+
+func (p capacityProbe) passed() bool {
+	return p.rc == 0 && len(p.violations) == 0
+}
+
+// addCapacitySearchFlags adds the search flags, sizeName is the flag holding the size of the workload
+func addCapacitySearchFlags(cmd *cobra.Command, s *capacitySearch, sizeName string) {
+	s.sizeName = sizeName
+	cmd.Flags().BoolVar(&s.enabled, "capacity-search", false, fmt.Sprintf("Search the highest --%s meeting the SLO, starting from its value", sizeName))
+	cmd.Flags().Float64Var(&s.factor, "search-factor", 2, fmt.Sprintf("Factor --%s grows by until a probe fails", sizeName))
+	cmd.Flags().IntVar(&s.max, "search-max", 0, fmt.Sprintf("Highest --%s probed, 0 for no limit", sizeName))
+	cmd.Flags().Float64Var(&s.precision, "search-precision", 10, "Stop bisecting once the gap between the passing and failing sizes is below this percentage of the passing size")
+	cmd.Flags().StringArrayVar(&s.sloLatencyExpressions, "search-slo-latency", []string{}, "Measurement quantile threshold failing the probe when exceeded, e.g. Ready.P99>10s for the pod ready latency. Can be repeated")
+	cmd.Flags().StringArrayVar(&s.sloPromQL, "search-slo-promql", []string{}, "PromQL expression failing the probe when it returns a result during the probe, e.g. API latency or error rates. Can be repeated")
+}
+
+// validate exits when the search parameters can't converge or leftovers of a probe would skew the next one
+func (s *capacitySearch) validate(cmd *cobra.Command, start int) {
+	if !s.enabled {
+		return
+	}
+	if start <= 0 {
+		log.Fatalf("--capacity-search requires --%s to be greater than 0", s.sizeName)
+	}
+	if s.factor <= 1 {
+		log.Fatal("--search-factor must be greater than 1")
+	}
+	if s.precision <= 0 {
+		log.Fatal("--search-precision must be greater than 0")
+	}
+	if s.max > 0 && s.max < start {
+		log.Fatalf("--search-max must be greater than --%s", s.sizeName)
+	}
+	if gc := cmd.Flag("gc"); gc != nil && gc.Value.String() == "false" {
+		log.Fatal("--capacity-search requires --gc, each probe must start from a clean cluster")
+	}
+	for _, expression := range s.sloLatencyExpressions {
+		condition, err := parseLatencyStopCondition(expression)
+		if err != nil {
+			log.Fatal(err)
+		}
+		s.sloLatencies = append(s.sloLatencies, condition)
+	}
+}
+
+// next returns the size of the next probe, false once the search is over
+func (s *capacitySearch) next(start int, probes []capacityProbe) (int, bool) {
+	if len(probes) == 0 {
+		return start, true
+	}
+	// Sizes below the lowest one are never probed
+	lastPass := max(s.min-1, 0)
+	var firstFail int
+	for _, probe := range probes {
+		if probe.passed() {
+			lastPass = max(lastPass, probe.size)
+		} else if firstFail == 0 || probe.size < firstFail {
+			firstFail = probe.size
+		}
+	}
+	if firstFail == 0 {
+		if s.max > 0 && lastPass >= s.max {
+			return 0, false
+		}
+		size := max(int(math.Ceil(float64(lastPass)*s.factor)), lastPass+1)
+		if s.max > 0 {
+			size = min(size, s.max)
+		}
+		return size, true
+	}
+	gap := firstFail - lastPass
+	if gap <= 1 || float64(gap) < float64(lastPass)*s.precision/100 {
+		return 0, false
+	}
+	return lastPass + gap/2, true
+}
+
+// run probes the workload until the search converges, the indexed documents of each probe are tagged with its size.
+// Returns 0 when at least one probe met the SLO
+func (s *capacitySearch) run(wh *workloads.WorkloadHelper, start int, runProbe func(size int) int) int {
+	var client *prometheus.Prometheus
+	if len(s.sloPromQL) > 0 {
+		client = newPrometheusClient(wh)
+	}
+	comparison := measurements.NewLatencyComparison()
+	metricsDirectory := "collected-metrics-" + wh.UUID
+	if len(s.sloLatencies) > 0 {
+		// The latency SLO reads the quantiles indexed locally
+		AdditionalVars["LOCAL_INDEXING"] = true
+	}
+	var probes []capacityProbe
+	for {
+		size, ok := s.next(start, probes)
+		if !ok {
+			break
+		}
+		log.Infof("Capacity search probe #%d with --%s=%d", len(probes), s.sizeName, size)
+		setCapacitySearchMetadata(wh, size)
+		run := fmt.Sprintf("probe-%d", len(probes))
+		comparison.SetRun(run)
+		probeStart := time.Now()
+		probe := capacityProbe{size: size, rc: runProbe(size)}
+		probeEnd := time.Now()
+		probe.duration = probeEnd.Sub(probeStart).Round(time.Second)
+		if probe.rc != 0 {
+			probe.violations = append(probe.violations, fmt.Sprintf("rc %d", probe.rc))
+		}
+		if len(s.sloLatencies) > 0 {
+			if err := comparison.Collect(metricsDirectory); err != nil {
+				log.Warnf("Failed to read the quantiles of the probe: %v", err)
+			}
+		}
+		for _, condition := range s.sloLatencies {
+			latency, ok := condition.latency(comparison, run)
+			if !ok {
+				probe.evidence = append(probe.evidence, fmt.Sprintf("%s.%s: not measured", condition.quantileName, condition.stat))
+				probe.violations = append(probe.violations, fmt.Sprintf("%s.%s not measured", condition.quantileName, condition.stat))
+				continue
+			}
+			probe.evidence = append(probe.evidence, fmt.Sprintf("%s.%s: %v", condition.quantileName, condition.stat, latency))
+			if latency > condition.threshold {
+				probe.violations = append(probe.violations, condition.violation(latency))
+			}
+		}
+		for _, expression := range s.sloPromQL {
+			met, result := evaluatePromQLRangeCondition(client, expression, probeStart, probeEnd)
+			probe.evidence = append(probe.evidence, fmt.Sprintf("%s: %s", expression, result))
+			if met {
+				probe.violations = append(probe.violations, expression)
+			}
+		}
+		if probe.passed() {
+			log.Infof("Probe with --%s=%d met the SLO", s.sizeName, size)
+		} else {
+			log.Warnf("Probe with --%s=%d violated the SLO: %s", s.sizeName, size, strings.Join(probe.violations, ", "))
+		}
+		probes = append(probes, probe)
+	}
+	log.Infof("Capacity search probes:\n%s", s.table(probes))
+	sustainable := 0
+	for _, probe := range probes {
+		if probe.passed() {
+			sustainable = max(sustainable, probe.size)
+		}
+	}
+	if sustainable == 0 {
+		log.Errorf("No probe met the SLO, the lowest --%s probed was %d", s.sizeName, start)
+		return 1
+	}
+	log.Infof("Maximum sustainable --%s: %d", s.sizeName, sustainable)
+	return 0
+}
+
+// table renders the result and evidence of every probe in the order they ran
+func (s *capacitySearch) table(probes []capacityProbe) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "PROBE\t%s\tRESULT\tDURATION\tEVIDENCE\n", strings.ToUpper(s.sizeName))
+	for i, probe := range probes {
+		result := "passed"
+		if !probe.passed() {
+			result = "failed: " + strings.Join(probe.violations, ", ")
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%v\t%s\n", i, probe.size, result, probe.duration, strings.Join(probe.evidence, "; "))
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// setCapacitySearchMetadata tags the metrics, measurements and job summaries with the size of the probe
+func setCapacitySearchMetadata(wh *workloads.WorkloadHelper, size int) {
+	if wh.MetricsMetadata == nil {
+		wh.MetricsMetadata = make(map[string]any)
+	}
+	if wh.SummaryMetadata == nil {
+		wh.SummaryMetadata = make(map[string]any)
+	}
+	wh.MetricsMetadata["capacitySearchSize"] = size
+	wh.SummaryMetadata["capacitySearchSize"] = size
+}
@@ -0,0 +1,62 @@
+package workloads
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// searchSizes runs the search against a workload failing from the given size
+func searchSizes(s *capacitySearch, start, failingSize int) []int {
+	var probes []capacityProbe
+	var sizes []int
+	for {
+		size, ok := s.next(start, probes)
+		if !ok {
+			return sizes
+		}
+		sizes = append(sizes, size)
+		probe := capacityProbe{size: size}
+		if size >= failingSize {
+			probe.rc = 1
+		}
+		probes = append(probes, probe)
+	}
+}
+
+func TestCapacitySearchNext(t *testing.T) {
+	tests := []struct {
+		name        string
+		search      capacitySearch
+		start       int
+		failingSize int
+		sizes       []int
+	}{
+		{"grow and bisect", capacitySearch{factor: 2, precision: 10}, 10, 55, []int{10, 20, 40, 80, 60, 50, 55, 52}},
+		{"first probe fails", capacitySearch{factor: 2, precision: 10}, 4, 2, []int{4, 2, 1}},
+		{"lowest size", capacitySearch{factor: 2, precision: 10, min: 3}, 4, 2, []int{4, 3}},
+		{"maximum reached", capacitySearch{factor: 2, max: 30, precision: 10}, 10, 100, []int{10, 20, 30}},
+		{"coarse precision", capacitySearch{factor: 3, precision: 50}, 10, 50, []int{10, 30, 90, 60, 45}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if sizes := searchSizes(&tt.search, tt.start, tt.failingSize); !slices.Equal(sizes, tt.sizes) {
+				t.Errorf("expected probes %v, got %v", tt.sizes, sizes)
+			}
+		})
+	}
+}
+
+func TestCapacitySearchTable(t *testing.T) {
+	s := capacitySearch{sizeName: "pods-per-node"}
+	lines := strings.Split(strings.TrimSpace(s.table([]capacityProbe{
+		{size: 100, evidence: []string{"apiserver: no result"}},
+		{size: 200, rc: 1, violations: []string{"rc 1"}},
+	})), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 probes, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[0], "PODS-PER-NODE") || !strings.Contains(lines[1], "apiserver: no result") || !strings.Contains(lines[2], "failed: rc 1") {
+		t.Errorf("unexpected table:\n%s", strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	cloneIntegrityDefaultImage = "registry.access.redhat.com/ubi9/ubi-minimal:latest"
+	cloneIntegrityGuestPath    = "/var/lib/kube-burner-integrity"
+)
+
+// cloneIntegrity writes a payload into the source disk of the clone workloads and verifies its checksum in every clone
+type cloneIntegrity struct {
+	enabled bool
+	sizeMiB int
+	image   string
+	timeout string
+}
+
+// addCloneIntegrityFlags adds the integrity flags, the verifier image is only used by the workloads verifying PVCs
+func addCloneIntegrityFlags(cmd *cobra.Command, c *cloneIntegrity, withImage bool) {
+	cmd.Flags().BoolVar(&c.enabled, "verify-integrity", false, "Write a payload into the source disk before cloning and verify its checksum in every clone")
+	cmd.Flags().IntVar(&c.sizeMiB, "integrity-payload-size", 16, "Size in MiB of the integrity payload")
+	cmd.Flags().StringVar(&c.timeout, "integrity-timeout", "15m", "Maximum time to verify the integrity payload of each clone")
+	if withImage {
+		cmd.Flags().StringVar(&c.image, "integrity-image", cloneIntegrityDefaultImage, "Image of the pods writing and verifying the integrity payload, must provide sh, yes, head, dd and sha256sum")
+	}
+}
+
+// setVars exposes the payload to the templates, every run uses a different payload.
+// offsetMiB is where the payload is written in the source disk, unused when written as a file in the guest
+func (c *cloneIntegrity) setVars(offsetMiB int) {
+	AdditionalVars["verifyIntegrity"] = c.enabled
+	if !c.enabled {
+		return
+	}
+	if c.sizeMiB <= 0 {
+		log.Fatal("--integrity-payload-size must be greater than 0")
+	}
+	AdditionalVars["integrityToken"] = uuid.NewString()
+	AdditionalVars["integritySizeMiB"] = c.sizeMiB
+	AdditionalVars["integrityOffsetMiB"] = offsetMiB
+	AdditionalVars["integrityPath"] = cloneIntegrityGuestPath
+	AdditionalVars["integrityImage"] = c.image
+	AdditionalVars["integrityTimeout"] = c.timeout
+}
+
+// diskOffset returns the offset in MiB of the payload in a raw disk of the given size.
+// The payload goes in the middle of the disk, past the content of small images and far from the size lost to the filesystem overhead
+func (c *cloneIntegrity) diskOffset(diskSize string) int {
+	if !c.enabled {
+		return 0
+	}
+	size, err := resource.ParseQuantity(diskSize)
+	if err != nil {
+		log.Fatalf("Invalid disk size %s - %v", diskSize, err)
+	}
+	sizeMiB := int(size.Value() >> 20)
+	offsetMiB := sizeMiB / 2
+	if offsetMiB+c.sizeMiB > sizeMiB*9/10 {
+		log.Fatalf("--integrity-payload-size %dMiB doesn't fit in the second half of a %s disk", c.sizeMiB, diskSize)
+	}
+	return offsetMiB
+}
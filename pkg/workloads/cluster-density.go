@@ -43,6 +43,7 @@ func NewClusterDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	var rc int
 	var nodeSelector corev1.NodeSelector
 	var matchExpressions []corev1.NodeSelectorRequirement
+	var search capacitySearch
 	const workerNodeSelector = "node-role.kubernetes.io/worker=,node-role.kubernetes.io/infra!=,node-role.kubernetes.io/workload!="
 	cmd := &cobra.Command{
 		Use:          variant,
@@ -116,6 +117,14 @@ func NewClusterDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 			AdditionalVars["INGRESS_DOMAIN"] = ingressDomain
 			AdditionalVars["CHURN_MODE"] = churnMode
 
+			search.validate(cmd, iterations)
+			if search.enabled {
+				rc = search.run(wh, iterations, func(size int) int {
+					AdditionalVars["JOB_ITERATIONS"] = size
+					return RunWorkload(cmd, wh, cmd.Name()+".yml")
+				})
+				return
+			}
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -135,6 +144,7 @@ func NewClusterDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	cmd.Flags().BoolVar(&svcLatency, "service-latency", false, "Enable service latency measurement")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
 	cmd.Flags().StringVar(&selector, "selector", workerNodeSelector, "Node selector")
+	addCapacitySearchFlags(cmd, &search, "iterations")
 	return cmd
 }
 
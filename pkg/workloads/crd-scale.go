@@ -16,24 +16,46 @@ package workloads
 
 import (
 	"os"
+	"time"
 
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
+var crdScaleMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"crdLatency": measurements.NewCRDLatencyMeasurementFactory,
+}
+
 // NewCrdScale holds the crd-scale workload
 func NewCrdScale(wh *workloads.WorkloadHelper) *cobra.Command {
-	var iterations int
+	var iterations, crsPerCRD, schemaProperties, validationRules int
+	var discoveryInterval time.Duration
 	var metricsProfiles []string
 	var rc int
 	cmd := &cobra.Command{
 		Use:          "crd-scale",
 		Short:        "Runs crd-scale workload",
 		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if crsPerCRD < 0 || schemaProperties < 0 {
+				log.Fatal("--crs-per-crd and --schema-properties can't be negative")
+			}
+			if validationRules < 0 || validationRules > schemaProperties {
+				log.Fatalf("--validation-rules must be between 0 and --schema-properties (%d)", schemaProperties)
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			setMetrics(cmd, metricsProfiles)
 			AdditionalVars["JOB_ITERATIONS"] = iterations
-
+			AdditionalVars["CRS_PER_CRD"] = crsPerCRD
+			AdditionalVars["SCHEMA_PROPERTIES"] = schemaProperties
+			AdditionalVars["VALIDATION_RULES"] = validationRules
+			AdditionalVars["DISCOVERY_INTERVAL"] = discoveryInterval
+			wh.SetMeasurements(crdScaleMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -41,6 +63,10 @@ func NewCrdScale(wh *workloads.WorkloadHelper) *cobra.Command {
 		},
 	}
 	cmd.Flags().IntVar(&iterations, "iterations", 0, "Number of CRDs to create")
+	cmd.Flags().IntVar(&crsPerCRD, "crs-per-crd", 0, "Custom resources created for each CRD once all of them are established, 0 to skip their creation")
+	cmd.Flags().IntVar(&schemaProperties, "schema-properties", 0, "Additional string properties of the CRD schema")
+	cmd.Flags().IntVar(&validationRules, "validation-rules", 0, "Schema properties validated with a CEL rule, up to --schema-properties")
+	cmd.Flags().DurationVar(&discoveryInterval, "discovery-interval", 10*time.Second, "Interval to time the aggregated discovery calls and sample the document size, 0 to disable it")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics-aggregated.yml"}, "Comma separated list of metrics profiles to use")
 	cmd.MarkFlagRequired("iterations")
 	return cmd
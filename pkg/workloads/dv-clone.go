@@ -41,6 +41,9 @@ func NewDVClone(wh *workloads.WorkloadHelper) *cobra.Command {
 	var clonesPerIteration int
 	var metricsProfiles []string
 	var volumeAccessMode string
+	var matrix storageMatrix
+	var integrity cloneIntegrity
+	var matrixSnapshotClasses map[string]string
 	var rc int
 	cmd := &cobra.Command{
 		Use:          dvCloneTestName,
@@ -51,7 +54,15 @@ func NewDVClone(wh *workloads.WorkloadHelper) *cobra.Command {
 				log.Fatalf("Unsupported access mode - %s", volumeAccessMode)
 			}
 
-			storageClassName, volumeSnapshotClassName = getStorageAndSnapshotClasses(storageClassName, useSnapshot, cmd.Flags().Lookup("use-snapshot").Changed)
+			matrix.validate(accessModeTranslator)
+			if matrix.enabled() {
+				matrixSnapshotClasses = make(map[string]string)
+				for _, storageClass := range matrix.storageClasses {
+					_, matrixSnapshotClasses[storageClass] = getStorageAndSnapshotClasses(storageClass, useSnapshot, cmd.Flags().Lookup("use-snapshot").Changed)
+				}
+			} else {
+				storageClassName, volumeSnapshotClassName = getStorageAndSnapshotClasses(storageClassName, useSnapshot, cmd.Flags().Lookup("use-snapshot").Changed)
+			}
 
 			if cmd.Flags().Lookup("container-disk").Changed && !cmd.Flags().Lookup("datavolume-size").Changed {
 				log.Warnf("--container-disk was set without setting --datavolume-size. Make sure the default size [%v] is sufficient", dataVolumeSize)
@@ -76,8 +87,24 @@ func NewDVClone(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["accessMode"] = accessModeTranslator[volumeAccessMode]
 			AdditionalVars["iterations"] = iterations
 			AdditionalVars["clonesPerIteration"] = clonesPerIteration
+			AdditionalVars["volumeMode"] = ""
+			AdditionalVars["metricsDirectory"] = dvCloneTestName + "-results"
+			integrity.setVars(integrity.diskOffset(dataVolumeSize))
 
 			setMetrics(cmd, metricsProfiles)
+			if matrix.enabled() {
+				rc = matrix.run(wh, volumeAccessMode, virtMeasurementFactoryMap, func(entry storageMatrixEntry) (int, string) {
+					AdditionalVars["storageClassName"] = entry.storageClass
+					AdditionalVars["volumeSnapshotClassName"] = matrixSnapshotClasses[entry.storageClass]
+					AdditionalVars["accessMode"] = accessModeTranslator[entry.accessMode]
+					AdditionalVars["volumeMode"] = entry.volumeMode
+					metricsDirectory := entry.metricsDirectory(dvCloneTestName + "-results")
+					AdditionalVars["metricsDirectory"] = metricsDirectory
+					return RunWorkload(cmd, wh, cmd.Name()+".yml"), metricsDirectory
+				})
+				return
+			}
+			wh.SetMeasurements(virtMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -93,5 +120,7 @@ func NewDVClone(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().StringVar(&containerDiskUrl, "container-disk", dvCloneDefaultContainerDiskUrl, "URL of the container disk to load into the volume")
 	cmd.Flags().StringVar(&dataVolumeSize, "datavolume-size", dvCloneDefaultDataVolumeSize, "Size of the DataVolume to create")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	addStorageMatrixFlags(cmd, &matrix)
+	addCloneIntegrityFlags(cmd, &integrity, true)
 	return cmd
 }
@@ -24,13 +24,20 @@ import (
 	"time"
 
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	"github.com/praserx/ipconv"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
+var egressIPMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"egressIPSourceLatency": measurements.NewEgressIPSourceLatencyMeasurementFactory,
+}
+
 // get egress IP cidr, node IPs from worker node annotations
 func getEgressIPCidrNodeIPs() ([]string, string) {
 	kubeClientProvider := config.NewKubeClientProvider("", "")
@@ -77,27 +84,13 @@ func getEgressIPCidrNodeIPs() ([]string, string) {
 // This function returns first usable address from the cidr
 // for example, if cidr is 10.0.132.49/19, first usable address is 10.0.128.1
 func getFirstUsableAddr(cidr string) uint32 {
-	// Parse the IP address and subnet mask
-	ip, ipNet, err := net.ParseCIDR(cidr)
+	// Skip the first 4 addresses of the network.
+	// For example, OVN didn't assign eip to node when eip was in between 10.0.0.0 and 10.0.0.3 for cidr 10.0.0.0/19
+	firstUsableIP, err := nthHost(cidr, 4)
 	if err != nil {
-		log.Fatal("Error parsing CIDR notation:", err)
+		log.Fatal("Error parsing CIDR notation: ", err)
 	}
-
-	// Get the network address by performing a bitwise AND
-	ipBytes := ip.To4()
-	networkBytes := make([]byte, 4)
-	for i := range 4 {
-		networkBytes[i] = ipBytes[i] & ipNet.Mask[i]
-	}
-
-	// Calculate the first usable IP address by skipping first 4 addresses.
-	// For example, OVN didn't assign eip to node when eip was in between 10.0.0.0 and 10.0.0.3 for cidr 10.0.0.0/19
-	firstUsableIP := make(net.IP, len(networkBytes))
-	copy(firstUsableIP, networkBytes)
-	firstUsableIP[3] += 4 // Increment the last byte by 1 for the first usable IP address
-
-	// Output the network address and the first usable IP address in CIDR notation
-	baseAddrInt, err := ipconv.IPv4ToInt(firstUsableIP)
+	baseAddrInt, err := ipconv.IPv4ToInt(net.ParseIP(firstUsableIP))
 	if err != nil {
 		log.Fatal("Error converting IP to int: ", err)
 	}
@@ -146,7 +139,8 @@ func generateEgressIPs(numJobIterations int, addressesPerIteration int, external
 func NewEgressIP(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	var iterations, addressesPerIteration int
 	var externalServerIP string
-	var podReadyThreshold time.Duration
+	var podReadyThreshold, sourceCheckDuration time.Duration
+	var sourceIPCheck bool
 	var metricsProfiles []string
 	var rc int
 	cmd := &cobra.Command{
@@ -160,6 +154,9 @@ func NewEgressIP(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 			AdditionalVars["ADDRESSES_PER_ITERATION"] = addressesPerIteration
 			AdditionalVars["EXTERNAL_SERVER_IP"] = externalServerIP
 			AdditionalVars["EIP_ADDRESSES"] = eipAddresses
+			AdditionalVars["SOURCE_IP_CHECK"] = sourceIPCheck
+			AdditionalVars["SOURCE_CHECK_DURATION"] = sourceCheckDuration
+			wh.SetMeasurements(egressIPMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -170,6 +167,8 @@ func NewEgressIP(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	cmd.Flags().IntVar(&iterations, "iterations", 0, fmt.Sprintf("%v iterations", variant))
 	cmd.Flags().StringVar(&externalServerIP, "external-server-ip", "", "External server IP address")
 	cmd.Flags().IntVar(&addressesPerIteration, "addresses-per-iteration", 1, fmt.Sprintf("%v iterations", variant))
+	cmd.Flags().BoolVar(&sourceIPCheck, "source-ip-check", false, "Verify the source IP observed by the external server matches the EgressIP of each namespace. The external server must reply with the request source IP, see the echo-server command")
+	cmd.Flags().DurationVar(&sourceCheckDuration, "source-check-duration", 30*time.Second, "Time given to the source IP checks before collecting their results")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics-egressip.yml"}, "Comma separated list of metrics profiles to use")
 	cmd.MarkFlagRequired("iterations")
 	cmd.MarkFlagRequired("external-server-ip")
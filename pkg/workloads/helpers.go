@@ -28,7 +28,9 @@ import (
 	k8sconnector "github.com/cloud-bulldozer/go-commons/v2/k8s-connector"
 	k8sstorage "github.com/cloud-bulldozer/go-commons/v2/k8s-storage"
 	ocpmetadata "github.com/cloud-bulldozer/go-commons/v2/ocp-metadata"
+	"github.com/cloud-bulldozer/go-commons/v2/prometheus"
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	kubeburnerutil "github.com/kube-burner/kube-burner/v2/pkg/util"
 	"github.com/kube-burner/kube-burner/v2/pkg/util/fileutils"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
@@ -40,6 +42,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
 const (
@@ -52,9 +56,24 @@ var (
 	AdditionalVars       map[string]any
 	SetVars              map[string]any
 	accessModeTranslator = map[string]string{
-		"RO":  "ReadOnly",
-		"RWO": "ReadWriteOnce",
-		"RWX": "ReadWriteMany",
+		"RO":   "ReadOnly",
+		"RWO":  "ReadWriteOnce",
+		"RWX":  "ReadWriteMany",
+		"ROX":  "ReadOnlyMany",
+		"RWOP": "ReadWriteOncePod",
+	}
+	// Measurements available to the virt workloads
+	virtMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+		"vmGuestReady":               measurements.NewVMGuestReadyMeasurementFactory,
+		"vmBootMilestones":           measurements.NewVMBootMilestonesMeasurementFactory,
+		"vmimPhaseLatency":           measurements.NewVMIMPhaseLatencyMeasurementFactory,
+		"dvClonePhaseLatency":        measurements.NewDVClonePhaseLatencyMeasurementFactory,
+		"volumeSnapshotReadyLatency": measurements.NewVolumeSnapshotReadyLatencyMeasurementFactory,
+		"cloneIntegrity":             measurements.NewCloneIntegrityMeasurementFactory,
+		"vmFio":                      measurements.NewVMFioMeasurementFactory,
+		"vmHotplugLatency":           measurements.NewVMHotplugLatencyMeasurementFactory,
+		"vmSnapshotRestoreLatency":   measurements.NewVMSnapshotRestoreLatencyMeasurementFactory,
+		"vmMigrationDowntime":        measurements.NewVMMigrationDowntimeMeasurementFactory,
 	}
 )
 
@@ -103,6 +122,32 @@ func applyClusterInfo(wh *workloads.WorkloadHelper, clusterInfo ocpmetadata.Clus
 	return nil
 }
 
+// connectPrometheus connects to the Prometheus of the flags, or to the one of the cluster when not set
+func connectPrometheus(wh *workloads.WorkloadHelper) (*prometheus.Prometheus, error) {
+	var err error
+	prometheusURL, prometheusToken := wh.PrometheusURL, wh.PrometheusToken
+	if prometheusURL == "" {
+		prometheusURL, prometheusToken, err = wh.MetadataAgent.GetPrometheus()
+		if err != nil {
+			return nil, fmt.Errorf("error obtaining prometheus information from cluster: %w", err)
+		}
+	}
+	client, err := prometheus.NewClient(prometheusURL, prometheusToken, "", "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Prometheus client - %w", err)
+	}
+	return client, nil
+}
+
+// newPrometheusClient connects to Prometheus like connectPrometheus, exiting when it's not reachable
+func newPrometheusClient(wh *workloads.WorkloadHelper) *prometheus.Prometheus {
+	client, err := connectPrometheus(wh)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return client
+}
+
 func HasAPIGroup(group string) bool {
 	return clusterCapabilities.HasAPIGroup(group)
 }
@@ -268,6 +313,16 @@ func kebabToCamelCase(word string) string {
 	return strings.Join(parts, "")
 }
 
+// Sets the variables used by the virt workloads to verify the guests through the vm command of this binary
+func setVMCheckVars(useVirtctl bool) {
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Failed to get the path of the current executable: %v", err)
+	}
+	AdditionalVars["KUBE_BURNER_OCP"] = executable
+	AdditionalVars["USE_VIRTCTL"] = useVirtctl
+}
+
 // Add metadata specific to the CNV workloads
 func AddVirtMetadata(wh *workloads.WorkloadHelper, vmImage, udnLayer, udnBindingMethod string) error {
 	var err error
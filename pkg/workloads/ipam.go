@@ -0,0 +1,144 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/util"
+)
+
+// ULA range the IPv6 index based helpers carve their subnets from
+const ipv6SubnetBase = "fd00::/32"
+
+// RegisterIPAMFunctions makes the IPAM helpers available to the templates of every workload
+func RegisterIPAMFunctions() {
+	util.AddRenderingFunction("CarveSubnet", carveSubnet)
+	util.AddRenderingFunction("NthHost", nthHost)
+	util.AddRenderingFunction("SubnetsOverlap", subnetsOverlap)
+	util.AddRenderingFunction("GenerateMAC", generateMAC)
+	util.AddRenderingFunction("GetSubnet16", getSubnet16)
+	util.AddRenderingFunction("GetSubnet24In16", getSubnet24In16)
+	util.AddRenderingFunction("GetSubnet48", getSubnet48)
+	util.AddRenderingFunction("GetSubnet64In48", getSubnet64In48)
+}
+
+// carveSubnet returns the index-th subnet of size newPrefix within parent, works for both IPv4 and IPv6
+// for example, carveSubnet("10.0.0.0/8", 24, 258) returns 10.1.2.0/24
+func carveSubnet(parent string, newPrefix, index int) (string, error) {
+	prefix, err := netip.ParsePrefix(parent)
+	if err != nil {
+		return "", err
+	}
+	prefix = prefix.Masked()
+	bits := prefix.Addr().BitLen()
+	if newPrefix < prefix.Bits() || newPrefix > bits {
+		return "", fmt.Errorf("prefix length /%d doesn't fit in %s", newPrefix, prefix)
+	}
+	subnets := new(big.Int).Lsh(big.NewInt(1), uint(newPrefix-prefix.Bits()))
+	if index < 0 || big.NewInt(int64(index)).Cmp(subnets) >= 0 {
+		return "", fmt.Errorf("subnet index %d out of range, %s holds %v /%d subnets", index, prefix, subnets, newPrefix)
+	}
+	offset := new(big.Int).Lsh(big.NewInt(int64(index)), uint(bits-newPrefix))
+	addr, err := addOffset(prefix.Addr(), offset)
+	if err != nil {
+		return "", err
+	}
+	return netip.PrefixFrom(addr, newPrefix).String(), nil
+}
+
+// nthHost returns the address located n positions after the network address of cidr, negative values count back from the last address
+// for example, nthHost("10.0.0.0/24", 1) returns 10.0.0.1 and nthHost("10.0.0.0/24", -2) returns 10.0.0.254
+func nthHost(cidr string, n int) (string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return "", err
+	}
+	prefix = prefix.Masked()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+	offset := big.NewInt(int64(n))
+	if n < 0 {
+		offset.Add(offset, size)
+	}
+	if offset.Sign() < 0 || offset.Cmp(size) >= 0 {
+		return "", fmt.Errorf("host %d out of range for %s", n, prefix)
+	}
+	addr, err := addOffset(prefix.Addr(), offset)
+	if err != nil {
+		return "", err
+	}
+	return addr.String(), nil
+}
+
+// subnetsOverlap reports whether the two given subnets share any address
+func subnetsOverlap(a, b string) (bool, error) {
+	prefixA, err := netip.ParsePrefix(a)
+	if err != nil {
+		return false, err
+	}
+	prefixB, err := netip.ParsePrefix(b)
+	if err != nil {
+		return false, err
+	}
+	return prefixA.Masked().Overlaps(prefixB.Masked()), nil
+}
+
+// generateMAC returns a locally administered unicast MAC address derived from index, unique for the first 2^32 indexes
+func generateMAC(index int) string {
+	return fmt.Sprintf("02:00:%02x:%02x:%02x:%02x", byte(index>>24), byte(index>>16), byte(index>>8), byte(index))
+}
+
+// getSubnet16 returns a /16 subnet per index, starting at 1.0.0.0/16
+func getSubnet16(subnetIdx int) string {
+	first := byte((subnetIdx >> 8) + 1)
+	second := byte(subnetIdx & 0xFF)
+	return netip.AddrFrom4([4]byte{first, second, 0, 0}).String() + "/16"
+}
+
+// getSubnet24In16 returns the offset-th /24 subnet of the /16 subnet returned by getSubnet16
+func getSubnet24In16(subnetIdx, offset int) string {
+	first := byte((subnetIdx >> 8) + 1)
+	second := byte(subnetIdx & 0xFF)
+	third := byte(offset)
+	return netip.AddrFrom4([4]byte{first, second, third, 0}).String() + "/24"
+}
+
+// getSubnet48 is the IPv6 equivalent of getSubnet16, returning a /48 subnet per index within fd00::/32
+func getSubnet48(subnetIdx int) (string, error) {
+	return carveSubnet(ipv6SubnetBase, 48, subnetIdx)
+}
+
+// getSubnet64In48 is the IPv6 equivalent of getSubnet24In16, returning the offset-th /64 subnet of the /48 subnet returned by getSubnet48
+func getSubnet64In48(subnetIdx, offset int) (string, error) {
+	parent, err := getSubnet48(subnetIdx)
+	if err != nil {
+		return "", err
+	}
+	return carveSubnet(parent, 64, offset)
+}
+
+// addOffset adds offset to addr, failing when the result overflows the address family
+func addOffset(addr netip.Addr, offset *big.Int) (netip.Addr, error) {
+	sum := new(big.Int).Add(new(big.Int).SetBytes(addr.AsSlice()), offset)
+	buf := make([]byte, addr.BitLen()/8)
+	if sum.BitLen() > addr.BitLen() {
+		return netip.Addr{}, fmt.Errorf("offset %v overflows %s", offset, addr)
+	}
+	sum.FillBytes(buf)
+	result, _ := netip.AddrFromSlice(buf)
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package workloads
+
+import "testing"
+
+func TestCarveSubnet(t *testing.T) {
+	tests := []struct {
+		parent    string
+		newPrefix int
+		index     int
+		expected  string
+		wantErr   bool
+	}{
+		{"10.0.0.0/8", 24, 258, "10.1.2.0/24", false},
+		{"10.0.0.0/8", 19, 132 * 8, "10.132.0.0/19", false},
+		{"10.0.0.0/8", 19, 132*8 + 9, "10.133.32.0/19", false},
+		{"fd00::/32", 48, 1, "fd00:0:1::/48", false},
+		{"fd00:0:1::/48", 64, 2, "fd00:0:1:2::/64", false},
+		{"10.0.0.0/24", 26, 4, "", true},
+		{"10.0.0.0/24", 16, 0, "", true},
+		{"10.0.0.0/24", 26, -1, "", true},
+		{"not-a-cidr", 24, 0, "", true},
+	}
+	for _, tc := range tests {
+		got, err := carveSubnet(tc.parent, tc.newPrefix, tc.index)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("carveSubnet(%s, %d, %d) unexpected error: %v", tc.parent, tc.newPrefix, tc.index, err)
+		}
+		if got != tc.expected {
+			t.Errorf("carveSubnet(%s, %d, %d) = %s, expected %s", tc.parent, tc.newPrefix, tc.index, got, tc.expected)
+		}
+	}
+}
+
+func TestNthHost(t *testing.T) {
+	tests := []struct {
+		cidr     string
+		n        int
+		expected string
+		wantErr  bool
+	}{
+		{"10.0.132.49/19", 4, "10.0.128.4", false},
+		{"10.0.0.0/24", -2, "10.0.0.254", false},
+		{"fd00::/64", 10, "fd00::a", false},
+		{"10.0.0.0/24", 256, "", true},
+		{"10.0.0.0/24", -257, "", true},
+	}
+	for _, tc := range tests {
+		got, err := nthHost(tc.cidr, tc.n)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("nthHost(%s, %d) unexpected error: %v", tc.cidr, tc.n, err)
+		}
+		if got != tc.expected {
+			t.Errorf("nthHost(%s, %d) = %s, expected %s", tc.cidr, tc.n, got, tc.expected)
+		}
+	}
+}
+
+func TestSubnetsOverlap(t *testing.T) {
+	if overlap, _ := subnetsOverlap("10.0.0.0/8", "10.1.0.0/16"); !overlap {
+		t.Errorf("expected 10.0.0.0/8 and 10.1.0.0/16 to overlap")
+	}
+	if overlap, _ := subnetsOverlap("10.132.0.0/19", "10.132.32.0/19"); overlap {
+		t.Errorf("expected 10.132.0.0/19 and 10.132.32.0/19 not to overlap")
+	}
+	if _, err := subnetsOverlap("10.0.0.0/8", "invalid"); err == nil {
+		t.Errorf("expected error for an invalid subnet")
+	}
+}
+
+func TestSubnetHelpersDontOverlap(t *testing.T) {
+	seen := map[string]bool{}
+	for i := range 300 {
+		for offset := range 3 {
+			subnet := getSubnet24In16(i, offset)
+			if seen[subnet] {
+				t.Fatalf("subnet %s allocated twice", subnet)
+			}
+			seen[subnet] = true
+		}
+	}
+	if got := generateMAC(258); got != "02:00:00:00:01:02" {
+		t.Errorf("generateMAC(258) = %s, expected 02:00:00:00:01:02", got)
+	}
+}
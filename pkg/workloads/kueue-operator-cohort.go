@@ -0,0 +1,105 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"os"
+	"slices"
+	"time"
+
+	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/spf13/cobra"
+)
+
+// NewKueueOperatorCohort holds the kueue-operator-cohort workload
+func NewKueueOperatorCohort(wh *workloads.WorkloadHelper) *cobra.Command {
+	var rc int
+	var metricsProfiles []string
+	var tenantWeights []string
+	var jobsPerTenant, parallelism, podsQuota, borrowingLimit, highPriorityPercent int
+	var workloadRuntime string
+	var reclaimWithinCohort, borrowWithinCohort, withinClusterQueue string
+	var sampleInterval time.Duration
+	var QPS, burst int
+	cmd := &cobra.Command{
+		Use:          "kueue-operator-cohort",
+		Short:        "Runs kueue-operator-cohort workload",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if len(tenantWeights) < 2 {
+				log.Fatal("At least two tenants are required, set them with --tenant-weights")
+			}
+			for _, weight := range tenantWeights {
+				if _, err := resource.ParseQuantity(weight); err != nil {
+					log.Fatalf("Invalid tenant weight %s - %v", weight, err)
+				}
+			}
+			if highPriorityPercent < 0 || highPriorityPercent > 100 {
+				log.Fatal("--high-priority-percent must be between 0 and 100")
+			}
+			if !slices.Contains([]string{"Never", "LowerPriority", "Any"}, reclaimWithinCohort) {
+				log.Fatalf("Unsupported --reclaim-within-cohort policy %s", reclaimWithinCohort)
+			}
+			if !slices.Contains([]string{"Never", "LowerPriority"}, borrowWithinCohort) {
+				log.Fatalf("Unsupported --borrow-within-cohort policy %s", borrowWithinCohort)
+			}
+			if borrowWithinCohort != "Never" && reclaimWithinCohort == "Never" {
+				log.Fatal("--borrow-within-cohort requires --reclaim-within-cohort to be different than Never")
+			}
+			if !slices.Contains([]string{"Never", "LowerPriority", "LowerOrNewerEqualPriority"}, withinClusterQueue) {
+				log.Fatalf("Unsupported --within-cluster-queue policy %s", withinClusterQueue)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			AdditionalVars["TENANT_WEIGHTS"] = tenantWeights
+			AdditionalVars["JOBS_PER_TENANT"] = jobsPerTenant
+			AdditionalVars["PARALLELISM"] = parallelism
+			AdditionalVars["PODS_QUOTA"] = podsQuota
+			AdditionalVars["BORROWING_LIMIT"] = borrowingLimit
+			AdditionalVars["HIGH_PRIORITY_PERCENT"] = highPriorityPercent
+			AdditionalVars["RECLAIM_WITHIN_COHORT"] = reclaimWithinCohort
+			AdditionalVars["BORROW_WITHIN_COHORT"] = borrowWithinCohort
+			AdditionalVars["WITHIN_CLUSTER_QUEUE"] = withinClusterQueue
+			AdditionalVars["WORKLOAD_RUNTIME"] = workloadRuntime
+			AdditionalVars["SAMPLE_INTERVAL"] = sampleInterval
+			AdditionalVars["QPS"] = QPS
+			AdditionalVars["BURST"] = burst
+			setMetrics(cmd, metricsProfiles)
+			wh.SetMeasurements(kueueMeasurementFactoryMap)
+			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
+		},
+		PostRun: func(cmd *cobra.Command, args []string) {
+			os.Exit(rc)
+		},
+	}
+	cmd.Flags().StringSliceVar(&tenantWeights, "tenant-weights", []string{"1", "1", "2"}, "Comma separated list of fair sharing weights, a ClusterQueue is created in the cohort for each of them")
+	cmd.Flags().IntVar(&jobsPerTenant, "jobs-per-tenant", 100, "Jobs submitted by each tenant")
+	cmd.Flags().IntVar(&parallelism, "parallelism", 2, "Number of pods of each job")
+	cmd.Flags().IntVar(&podsQuota, "pods-quota", 20, "Nominal pods quota of each ClusterQueue")
+	cmd.Flags().IntVar(&borrowingLimit, "borrowing-limit", 20, "Pods each ClusterQueue can borrow from the cohort, a negative value removes the limit")
+	cmd.Flags().IntVar(&highPriorityPercent, "high-priority-percent", 20, "Percentage of the jobs of each tenant using the high WorkloadPriorityClass")
+	cmd.Flags().StringVar(&reclaimWithinCohort, "reclaim-within-cohort", "Any", "Preemption policy to reclaim the nominal quota borrowed by other ClusterQueues - Never, LowerPriority, Any")
+	cmd.Flags().StringVar(&borrowWithinCohort, "borrow-within-cohort", "Never", "Preemption policy of other ClusterQueues workloads while borrowing - Never, LowerPriority")
+	cmd.Flags().StringVar(&withinClusterQueue, "within-cluster-queue", "LowerPriority", "Preemption policy within each ClusterQueue - Never, LowerPriority, LowerOrNewerEqualPriority")
+	cmd.Flags().StringVar(&workloadRuntime, "workload-runtime", "30s", "Workload runtime")
+	cmd.Flags().DurationVar(&sampleInterval, "sample-interval", 10*time.Second, "Interval to sample the usage of the ClusterQueues")
+	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"kueue-metrics.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.PersistentFlags().IntVar(&QPS, "qps", 20, "QPS")
+	cmd.PersistentFlags().IntVar(&burst, "burst", 20, "Burst")
+	return cmd
+}
@@ -18,13 +18,21 @@ import (
 	"fmt"
 	"os"
 
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
 const kueueOperatorJobsShared = "kueue-operator-jobs-shared"
 
+var kueueMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"kueueAdmissionLatency": measurements.NewKueueAdmissionLatencyMeasurementFactory,
+	"kueueFairShare":        measurements.NewKueueFairShareMeasurementFactory,
+}
+
 // NewKueueOperator holds kueue-operator workload
 func NewKueueOperator(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	var rc int
@@ -53,6 +61,7 @@ func NewKueueOperator(wh *workloads.WorkloadHelper, variant string) *cobra.Comma
 			AdditionalVars["QPS"] = QPS
 			AdditionalVars["BURST"] = burst
 			setMetrics(cmd, metricsProfiles)
+			wh.SetMeasurements(kueueMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
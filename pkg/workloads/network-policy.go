@@ -16,19 +16,26 @@ package workloads
 
 import (
 	"fmt"
-	"net/netip"
 	"os"
 	"time"
 
-	"github.com/kube-burner/kube-burner/v2/pkg/util"
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
+var netpolMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"netpolConnectivity": measurements.NewNetpolConnectivityMeasurementFactory,
+}
+
 // NewNetworkPolicy holds network-policy workload
 func NewNetworkPolicy(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	var iterations, podsPerNamespace, netpolPerNamespace, localPods, podSelectors, singlePorts, portRanges, remoteNamespaces, remotePods, cidrs, exceptRules int
-	var netpolLatency bool
+	var netpolLatency, verifyConnectivity bool
+	var verifySampleSize int
+	var verifyMaxMismatchPercent float64
 	var metricsProfiles []string
 	var netpolReadyThreshold time.Duration
 	var rc int
@@ -42,18 +49,6 @@ func NewNetworkPolicy(wh *workloads.WorkloadHelper, variant string) *cobra.Comma
 			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			// Register template functions used only by network-policy templates
-			util.AddRenderingFunction("GetSubnet16", func(subnetIdx int) string {
-				first := byte((subnetIdx >> 8) + 1)
-				second := byte(subnetIdx & 0xFF)
-				return netip.AddrFrom4([4]byte{first, second, 0, 0}).String() + "/16"
-			})
-			util.AddRenderingFunction("GetSubnet24In16", func(subnetIdx, offset int) string {
-				first := byte((subnetIdx >> 8) + 1)
-				second := byte(subnetIdx & 0xFF)
-				third := byte(offset)
-				return netip.AddrFrom4([4]byte{first, second, third, 0}).String() + "/24"
-			})
 			setMetrics(cmd, metricsProfiles)
 			AdditionalVars["JOB_ITERATIONS"] = iterations
 			AdditionalVars["PODS_PER_NAMESPACE"] = podsPerNamespace
@@ -68,6 +63,10 @@ func NewNetworkPolicy(wh *workloads.WorkloadHelper, variant string) *cobra.Comma
 			AdditionalVars["EXCEPT_RULES"] = exceptRules
 			AdditionalVars["NETPOL_LATENCY"] = netpolLatency
 			AdditionalVars["NETPOL_READY_THRESHOLD"] = netpolReadyThreshold
+			AdditionalVars["VERIFY_CONNECTIVITY"] = verifyConnectivity
+			AdditionalVars["VERIFY_SAMPLE_SIZE"] = verifySampleSize
+			AdditionalVars["VERIFY_MAX_MISMATCH_PERCENT"] = verifyMaxMismatchPercent
+			wh.SetMeasurements(netpolMeasurementFactoryMap)
 
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
@@ -88,6 +87,9 @@ func NewNetworkPolicy(wh *workloads.WorkloadHelper, variant string) *cobra.Comma
 	cmd.Flags().IntVar(&cidrs, "cidrs", 2, "Number of cidrs to accept traffic from or send traffic to in ingress and egress rules")
 	cmd.Flags().IntVar(&exceptRules, "except-rules", 0, "Number of except rules to exclude traffic from ingress and egress cidr blocks")
 	cmd.Flags().BoolVar(&netpolLatency, "networkpolicy-latency", true, "Enable network policy latency measurement")
+	cmd.Flags().BoolVar(&verifyConnectivity, "verify-connectivity", false, "Probe a sample of pod pairs and compare the observed connectivity with the one expected from the network policy parameters")
+	cmd.Flags().IntVar(&verifySampleSize, "verify-sample-size", 100, "Number of pod pairs probed by the connectivity verification, half of them expected to be allowed")
+	cmd.Flags().Float64Var(&verifyMaxMismatchPercent, "verify-max-mismatch-percent", 0, "Fail the run when the percentage of unexpected allows and denies is above this value")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics-aggregated.yml"}, "Comma separated list of metrics profiles to use")
 	return cmd
 }
@@ -40,12 +40,14 @@ func NewNodeDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Command
 	var namespacedIterations, pprof, svcLatency bool
 	var nodeSelector corev1.NodeSelector
 	var matchExpressions []corev1.NodeSelectorRequirement
+	var search capacitySearch
 	const workerNodeSelector = "node-role.kubernetes.io/worker=,node-role.kubernetes.io/infra!=,node-role.kubernetes.io/workload!="
 	cmd := &cobra.Command{
 		Use:          variant,
 		Short:        fmt.Sprintf("Runs %v workload", variant),
 		SilenceUsage: true,
 		Run: func(cmd *cobra.Command, args []string) {
+			search.validate(cmd, podsPerNode)
 			kubeClientProvider := config.NewKubeClientProvider("", "")
 			clientSet, _ := kubeClientProvider.ClientSet(0, 0)
 			nodes, err := clientSet.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: selector})
@@ -55,7 +57,6 @@ func NewNodeDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Command
 			if len(nodes.Items) == 0 {
 				log.Fatalf("No nodes found with the selector: %s", selector)
 			}
-			totalPods := len(nodes.Items) * podsPerNode
 			podCount, err := wh.MetadataAgent.GetCurrentPodCount(selector)
 			if err != nil {
 				log.Fatal(err.Error())
@@ -105,12 +106,30 @@ func NewNodeDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Command
 				log.Fatal(err.Error())
 			}
 			AdditionalVars["NODE_SELECTOR"] = string(nodeSelectorJson)
-			if variant == "node-density" {
-				AdditionalVars["JOB_ITERATIONS"] = totalPods - podCount
-			} else {
-				AdditionalVars["JOB_ITERATIONS"] = (totalPods - podCount) / 2
+			jobIterations := func(podsPerNode int) int {
+				totalPods := len(nodes.Items) * podsPerNode
+				if variant == "node-density" {
+					return totalPods - podCount
+				}
+				return (totalPods - podCount) / 2
 			}
 			setMetrics(cmd, metricsProfiles)
+			if search.enabled {
+				// Lower sizes are already met by the pods running in the nodes and wouldn't create any pod
+				search.min = 1
+				for jobIterations(search.min) <= 0 {
+					search.min++
+				}
+				if podsPerNode < search.min {
+					log.Fatalf("--capacity-search requires --pods-per-node to be at least %d with the %d pods running in the nodes", search.min, podCount)
+				}
+				rc = search.run(wh, podsPerNode, func(size int) int {
+					AdditionalVars["JOB_ITERATIONS"] = jobIterations(size)
+					return RunWorkload(cmd, wh, cmd.Name()+".yml")
+				})
+				return
+			}
+			AdditionalVars["JOB_ITERATIONS"] = jobIterations(podsPerNode)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -145,5 +164,6 @@ func NewNodeDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Command
 	cmd.Flags().BoolVar(&namespacedIterations, "namespaced-iterations", true, "Namespaced iterations")
 	cmd.Flags().IntVar(&iterationsPerNamespace, "iterations-per-namespace", 1000, "Iterations per namespace")
 	cmd.Flags().StringVar(&selector, "selector", workerNodeSelector, "Node selector")
+	addCapacitySearchFlags(cmd, &search, "pods-per-node")
 	return cmd
 }
@@ -20,24 +20,39 @@ import (
 	"time"
 
 	"github.com/kube-burner/kube-burner-ocp/pkg/clusterhealth"
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+var olmMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"olmv1Latency": measurements.NewOLMv1LatencyMeasurementFactory,
+}
+
 // NewOLMv1 holds OLMv1 workload
 func NewOLMv1(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	var iterations int
 	var catalogImage, deletionStrategy, namespace, prefixPkgName, prefixImgName, churnMode string
+	var installVersion, upgradeVersion, upgradeConstraintPolicy string
 	var metricsProfiles []string
 	var rc, iterationsPerNamespace, churnCycles, churnPercent int
 	var pprof, namespacedIterations bool
-	var churnDuration, churnDelay, pprofInterval time.Duration
+	var churnDuration, churnDelay, pprofInterval, rolloutTimeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   variant,
 		Short: fmt.Sprintf("Runs %v workload", variant),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if upgradeConstraintPolicy != "CatalogProvided" && upgradeConstraintPolicy != "SelfCertified" {
+				log.Fatalf("Unsupported --upgrade-constraint-policy %s", upgradeConstraintPolicy)
+			}
+			if upgradeVersion != "" && churnCycles > 0 {
+				log.Fatal("--upgrade-version can't be combined with --churn-cycles")
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			kubeClientProvider := config.NewKubeClientProvider("", "")
 			clientSet, _ := kubeClientProvider.ClientSet(0, 0)
@@ -60,7 +75,12 @@ func NewOLMv1(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 			AdditionalVars["NAMESPACE"] = namespace
 			AdditionalVars["PREFIX_PKG_NAME_V1"] = prefixPkgName
 			AdditionalVars["PREFIX_IMG_NAME"] = prefixImgName
+			AdditionalVars["INSTALL_VERSION"] = installVersion
+			AdditionalVars["UPGRADE_VERSION"] = upgradeVersion
+			AdditionalVars["UPGRADE_CONSTRAINT_POLICY"] = upgradeConstraintPolicy
+			AdditionalVars["ROLLOUT_TIMEOUT"] = rolloutTimeout
 
+			wh.SetMeasurements(olmMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -83,6 +103,10 @@ func NewOLMv1(wh *workloads.WorkloadHelper, variant string) *cobra.Command {
 	cmd.Flags().StringVar(&namespace, "namespace", "olmv1-ce", "Namespace to run the workload in")
 	cmd.Flags().StringVar(&prefixPkgName, "prefix-pkg-name", "stress-olmv1-c", "Prefix for package names")
 	cmd.Flags().StringVar(&prefixImgName, "prefix-image-name", "quay.io/olmqe/stress-index:vokv", "Prefix for catalog image names")
+	cmd.Flags().StringVar(&installVersion, "install-version", "", "Version or version range of the packages installed by the ClusterExtensions, default to the latest")
+	cmd.Flags().StringVar(&upgradeVersion, "upgrade-version", "", "Version or version range the ClusterExtensions are upgraded to once installed, the upgrade phase is skipped when not set")
+	cmd.Flags().StringVar(&upgradeConstraintPolicy, "upgrade-constraint-policy", "CatalogProvided", "Upgrade constraint policy of the ClusterExtensions - CatalogProvided, SelfCertified")
+	cmd.Flags().DurationVar(&rolloutTimeout, "rollout-timeout", 30*time.Minute, "Maximum time to wait for the ClusterExtensions upgrades to roll out")
 	cmd.MarkFlagRequired("iterations")
 	return cmd
 }
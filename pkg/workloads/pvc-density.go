@@ -18,10 +18,19 @@ import (
 	"fmt"
 	"os"
 
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
+var pvcDensityMeasurementFactoryMap = map[string]kubeburnermeasurements.NewMeasurementFactory{
+	"pvcProvisioningLatency":     measurements.NewPVCProvisioningLatencyMeasurementFactory,
+	"volumeSnapshotReadyLatency": measurements.NewVolumeSnapshotReadyLatencyMeasurementFactory,
+}
+
 // NewPVCDensity holds pvc-density workload
 func NewPVCDensity(wh *workloads.WorkloadHelper) *cobra.Command {
 
@@ -29,19 +38,44 @@ func NewPVCDensity(wh *workloads.WorkloadHelper) *cobra.Command {
 	var metricsProfiles []string
 	var claimSize string
 	var containerImage, storageClassName string
+	var accessMode string
+	var matrix storageMatrix
 	var rc int
 
 	cmd := &cobra.Command{
 		Use:          "pvc-density",
 		Short:        "Runs pvc-density workload",
 		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if _, ok := accessModeTranslator[accessMode]; !ok {
+				log.Fatalf("Unsupported access mode - %s", accessMode)
+			}
+			matrix.validate(accessModeTranslator)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			AdditionalVars["JOB_ITERATIONS"] = iterations
 			AdditionalVars["CONTAINER_IMAGE"] = containerImage
 			AdditionalVars["CLAIM_SIZE"] = claimSize
 			AdditionalVars["STORAGE_CLASS_NAME"] = storageClassName
+			AdditionalVars["ACCESS_MODE"] = accessModeTranslator[accessMode]
+			AdditionalVars["VOLUME_MODE"] = ""
+			AdditionalVars["METRICS_DIRECTORY"] = "collected-metrics-" + wh.UUID
 
 			setMetrics(cmd, metricsProfiles)
+			if matrix.enabled() {
+				// The comparison reads the quantiles indexed locally
+				AdditionalVars["LOCAL_INDEXING"] = true
+				rc = matrix.run(wh, accessMode, pvcDensityMeasurementFactoryMap, func(entry storageMatrixEntry) (int, string) {
+					AdditionalVars["STORAGE_CLASS_NAME"] = entry.storageClass
+					AdditionalVars["ACCESS_MODE"] = accessModeTranslator[entry.accessMode]
+					AdditionalVars["VOLUME_MODE"] = entry.volumeMode
+					metricsDirectory := entry.metricsDirectory("collected-metrics-" + wh.UUID)
+					AdditionalVars["METRICS_DIRECTORY"] = metricsDirectory
+					return RunWorkload(cmd, wh, cmd.Name()+".yml"), metricsDirectory
+				})
+				return
+			}
+			wh.SetMeasurements(pvcDensityMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -50,8 +84,10 @@ func NewPVCDensity(wh *workloads.WorkloadHelper) *cobra.Command {
 	}
 	cmd.Flags().StringVar(&storageClassName, "storage-class-name", "", "Storage class name, leave this empty to use the default one")
 	cmd.Flags().IntVar(&iterations, "iterations", 0, fmt.Sprintf("%v iterations", iterations))
+	cmd.Flags().StringVar(&accessMode, "access-mode", "RWO", "Access mode of the claims - RWO, RWX, ROX, RWOP")
 	cmd.Flags().StringVar(&claimSize, "claim-size", "256Mi", "claim-size=256Mi")
 	cmd.Flags().StringVar(&containerImage, "container-image", "gcr.io/google_containers/pause:3.1", "Container image")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	addStorageMatrixFlags(cmd, &matrix)
 	return cmd
 }
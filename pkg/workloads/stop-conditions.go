@@ -0,0 +1,249 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/prometheus"
+	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
+)
+
+// promQLConditionStep is the resolution PromQL conditions are evaluated with over a run
+const promQLConditionStep = 30 * time.Second
+
+var (
+	latencyStopConditionRegex = regexp.MustCompile(`^(\w+)\.(\w+)\s*>\s*(\S+)$`)
+	// Latencies always reported in the loop summary
+	loopSummaryLatencies = []latencyStopCondition{{quantileName: "VMReady", stat: "P99"}}
+)
+
+// loopStopConditions holds the conditions evaluated after each loop of the capacity workloads to stop once the platform degrades
+type loopStopConditions struct {
+	latencyExpressions []string
+	promQLExpressions  []string
+	latencies          []latencyStopCondition
+	prometheus         *prometheus.Prometheus
+}
+
+type latencyStopCondition struct {
+	quantileName string
+	stat         string
+	threshold    time.Duration
+}
+
+// capacityLoop is the outcome of a loop of the capacity workloads
+type capacityLoop struct {
+	counter      int
+	vmCount      int
+	storageClass string
+	rc           int
+	stopReason   string
+}
+
+func addLoopStopConditionFlags(cmd *cobra.Command, c *loopStopConditions) {
+	cmd.Flags().StringArrayVar(&c.latencyExpressions, "stop-on-latency", []string{}, "Stop after the loop in which a measurement quantile exceeds a threshold, e.g. VMReady.P99>2m. Can be repeated")
+	cmd.Flags().StringArrayVar(&c.promQLExpressions, "stop-on-promql", []string{}, "Stop after the loop in which a PromQL expression returns a result, e.g. 'sum(kube_pod_status_phase{phase=\"Failed\"}) > 0'. Can be repeated")
+}
+
+// parse exits when a latency stop condition is malformed
+func (c *loopStopConditions) parse() {
+	for _, expression := range c.latencyExpressions {
+		condition, err := parseLatencyStopCondition(expression)
+		if err != nil {
+			log.Fatal(err)
+		}
+		c.latencies = append(c.latencies, condition)
+	}
+}
+
+func parseLatencyStopCondition(expression string) (latencyStopCondition, error) {
+	matches := latencyStopConditionRegex.FindStringSubmatch(strings.TrimSpace(expression))
+	if matches == nil {
+		return latencyStopCondition{}, fmt.Errorf("invalid latency stop condition %q, expected <quantileName>.<stat>><duration>", expression)
+	}
+	if !slices.Contains(measurements.LatencyComparisonStats, matches[2]) {
+		return latencyStopCondition{}, fmt.Errorf("invalid statistic %q in latency stop condition %q, expected one of %s", matches[2], expression, strings.Join(measurements.LatencyComparisonStats, ", "))
+	}
+	threshold, err := time.ParseDuration(matches[3])
+	if err != nil {
+		return latencyStopCondition{}, fmt.Errorf("invalid threshold in latency stop condition %q: %v", expression, err)
+	}
+	return latencyStopCondition{quantileName: matches[1], stat: matches[2], threshold: threshold}, nil
+}
+
+// latency returns the statistic of the quantile measured in the run, false when it wasn't measured
+func (c latencyStopCondition) latency(comparison *measurements.LatencyComparison, run string) (time.Duration, bool) {
+	value, ok := comparison.Quantile(run, c.quantileName, c.stat)
+	if !ok {
+		log.Warnf("Quantile %s wasn't measured in %s", c.quantileName, run)
+		return 0, false
+	}
+	return time.Duration(value) * time.Millisecond, true
+}
+
+func (c latencyStopCondition) violation(latency time.Duration) string {
+	return fmt.Sprintf("%s.%s %v > %v", c.quantileName, c.stat, latency, c.threshold)
+}
+
+// connect creates the Prometheus client evaluating the PromQL stop conditions
+func (c *loopStopConditions) connect(wh *workloads.WorkloadHelper) {
+	if len(c.promQLExpressions) > 0 {
+		c.prometheus = newPrometheusClient(wh)
+	}
+}
+
+// check returns why the workload must stop after the run, empty when no condition is met
+func (c *loopStopConditions) check(comparison *measurements.LatencyComparison, run string) string {
+	for _, condition := range c.latencies {
+		latency, ok := condition.latency(comparison, run)
+		if ok && latency > condition.threshold {
+			return condition.violation(latency)
+		}
+	}
+	for _, expression := range c.promQLExpressions {
+		if met, _ := evaluatePromQLCondition(c.prometheus, expression); met {
+			return fmt.Sprintf("PromQL %q", expression)
+		}
+	}
+	return ""
+}
+
+// evaluatePromQLCondition returns whether the expression is met now and its result
+func evaluatePromQLCondition(client *prometheus.Prometheus, expression string) (bool, string) {
+	value, err := client.Query(expression, time.Now())
+	if err != nil {
+		log.Warnf("Failed to evaluate PromQL condition %q: %v", expression, err)
+		return false, "query failed"
+	}
+	return promQLConditionMet(value), promQLResult(value)
+}
+
+// evaluatePromQLRangeCondition returns whether the expression was met at any step of the window and its result
+func evaluatePromQLRangeCondition(client *prometheus.Prometheus, expression string, start, end time.Time) (bool, string) {
+	value, err := client.QueryRange(expression, start, end, promQLConditionStep)
+	if err != nil {
+		log.Warnf("Failed to evaluate PromQL condition %q: %v", expression, err)
+		return false, "query failed"
+	}
+	return promQLConditionMet(value), promQLResult(value)
+}
+
+// promQLConditionMet follows the alerting rules semantics, a vector is met when not empty and a scalar when not zero.
+// A range result is met when the expression returned a result at any step
+func promQLConditionMet(value model.Value) bool {
+	switch v := value.(type) {
+	case model.Vector:
+		return len(v) > 0
+	case model.Matrix:
+		return slices.ContainsFunc(v, func(stream *model.SampleStream) bool { return len(stream.Values) > 0 })
+	case *model.Scalar:
+		return v.Value != 0
+	}
+	return false
+}
+
+// promQLResult summarizes the value of a PromQL expression
+func promQLResult(value model.Value) string {
+	switch v := value.(type) {
+	case model.Vector:
+		if len(v) == 0 {
+			return "no result"
+		}
+		highest := v[0].Value
+		for _, sample := range v[1:] {
+			highest = max(highest, sample.Value)
+		}
+		return fmt.Sprintf("%g (highest of %d series)", float64(highest), len(v))
+	case model.Matrix:
+		var highest model.SampleValue
+		var samples int
+		for _, stream := range v {
+			for _, sample := range stream.Values {
+				if samples == 0 || sample.Value > highest {
+					highest = sample.Value
+				}
+				samples++
+			}
+		}
+		if samples == 0 {
+			return "no result"
+		}
+		return fmt.Sprintf("%g (highest of %d series over the run)", float64(highest), len(v))
+	case *model.Scalar:
+		return fmt.Sprintf("%g", float64(v.Value))
+	}
+	return "unsupported result"
+}
+
+// summary renders the outcome and key latencies of every loop
+func (c *loopStopConditions) summary(loops []capacityLoop, comparison *measurements.LatencyComparison) string {
+	columns := slices.Clone(loopSummaryLatencies)
+	for _, condition := range c.latencies {
+		if !slices.ContainsFunc(columns, func(column latencyStopCondition) bool {
+			return column.quantileName == condition.quantileName && column.stat == condition.stat
+		}) {
+			columns = append(columns, condition)
+		}
+	}
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	header := []string{"LOOP", "VMS", "STORAGE CLASS", "RESULT"}
+	for _, column := range columns {
+		header = append(header, column.quantileName+" "+column.stat)
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, loop := range loops {
+		result := "passed"
+		switch {
+		case loop.rc != 0:
+			result = fmt.Sprintf("failed (rc %d)", loop.rc)
+		case loop.stopReason != "":
+			result = "stopped: " + loop.stopReason
+		}
+		row := []string{fmt.Sprint(loop.counter), fmt.Sprint(loop.vmCount), loop.storageClass, result}
+		for _, column := range columns {
+			value, ok := comparison.Quantile(capacityLoopRun(loop.counter), column.quantileName, column.stat)
+			if !ok {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, (time.Duration(value) * time.Millisecond).String())
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return sb.String()
+}
+
+func capacityLoopRun(counter int) string {
+	return fmt.Sprintf("loop-%d", counter)
+}
+
+// capacityLoopMetricsDirectory returns the directory where the documents of the loop are indexed locally
+func capacityLoopMetricsDirectory(metricsBaseDirectory string, counter int) string {
+	return filepath.Join(metricsBaseDirectory, fmt.Sprintf("iteration-%d", counter))
+}
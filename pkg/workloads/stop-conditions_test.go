@@ -0,0 +1,96 @@
+package workloads
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
+)
+
+func TestParseLatencyStopCondition(t *testing.T) {
+	condition, err := parseLatencyStopCondition("VMReady.P99 > 2m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition.quantileName != "VMReady" || condition.stat != "P99" || condition.threshold != 2*time.Minute {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+	for _, expression := range []string{"VMReady>2m", "VMReady.P90>2m", "VMReady.P99>2", "VMReady.P99<2m"} {
+		if _, err := parseLatencyStopCondition(expression); err == nil {
+			t.Errorf("expected %q to be rejected", expression)
+		}
+	}
+}
+
+func TestPromQLConditionMet(t *testing.T) {
+	tests := []struct {
+		value model.Value
+		met   bool
+	}{
+		{model.Vector{}, false},
+		{model.Vector{&model.Sample{Value: 0}}, true},
+		{&model.Scalar{Value: 0}, false},
+		{&model.Scalar{Value: 1}, true},
+		{model.Matrix{}, false},
+		{model.Matrix{&model.SampleStream{}}, false},
+		{model.Matrix{&model.SampleStream{Values: []model.SamplePair{{Value: 0}}}}, true},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if met := promQLConditionMet(tt.value); met != tt.met {
+			t.Errorf("expected %v for %v, got %v", tt.met, tt.value, met)
+		}
+	}
+}
+
+func TestLoopSummary(t *testing.T) {
+	conditions := loopStopConditions{latencyExpressions: []string{"VMReady.P99>2m", "VMIRunning.max>5m"}}
+	conditions.parse()
+	metricsBaseDirectory := t.TempDir()
+	comparison := measurements.NewLatencyComparison()
+	// Quantiles indexed by the kube-burner vmiLatency measurement of each loop
+	for counter, p99 := range []int{90000, 180000} {
+		comparison.SetRun(capacityLoopRun(counter))
+		metricsDirectory := capacityLoopMetricsDirectory(metricsBaseDirectory, counter)
+		if err := os.MkdirAll(metricsDirectory, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		quantiles := fmt.Sprintf(`[{"quantileName": "VMReady", "jobName": "create-vms-%d", "P99": %d, "max": %d, "metricName": "vmiLatencyQuantilesMeasurement"}]`, counter, p99, p99)
+		if err := os.WriteFile(filepath.Join(metricsDirectory, fmt.Sprintf("vmiLatencyQuantilesMeasurement-create-vms-%d.json", counter)), []byte(quantiles), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := comparison.Collect(metricsDirectory); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reason := conditions.check(comparison, capacityLoopRun(0)); reason != "" {
+		t.Errorf("expected no stop condition met in the first loop, got %q", reason)
+	}
+	reason := conditions.check(comparison, capacityLoopRun(1))
+	if reason != "VMReady.P99 3m0s > 2m0s" {
+		t.Errorf("unexpected stop reason %q", reason)
+	}
+	loops := []capacityLoop{
+		{counter: 0, vmCount: 5, storageClass: "gp3-csi"},
+		{counter: 1, vmCount: 10, storageClass: "gp3-csi", stopReason: reason},
+	}
+	lines := strings.Split(strings.TrimSpace(conditions.summary(loops, comparison)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 loops, got:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[0], "VMReady P99") || !strings.Contains(lines[0], "VMIRunning max") || strings.Count(lines[0], "VMReady") != 1 {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); fields[len(fields)-2] != "1m30s" || fields[len(fields)-1] != "-" {
+		t.Errorf("unexpected loop row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); !strings.Contains(lines[2], "stopped: VMReady.P99 3m0s > 2m0s") || fields[len(fields)-2] != "3m0s" {
+		t.Errorf("unexpected loop row %q", lines[2])
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	kubeburnermeasurements "github.com/kube-burner/kube-burner/v2/pkg/measurements"
+	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
+)
+
+var storageMatrixVolumeModes = []string{"Filesystem", "Block"}
+
+// storageMatrix holds the storage combinations a workload is run against, one run per combination
+type storageMatrix struct {
+	storageClasses []string
+	accessModes    []string
+	volumeModes    []string
+}
+
+type storageMatrixEntry struct {
+	storageClass string
+	accessMode   string
+	volumeMode   string
+}
+
+func addStorageMatrixFlags(cmd *cobra.Command, m *storageMatrix) {
+	cmd.Flags().StringSliceVar(&m.storageClasses, "storage-matrix", []string{}, "Comma separated list of Storage Classes to run the workload against, once per class")
+	cmd.Flags().StringSliceVar(&m.accessModes, "storage-matrix-access-modes", []string{}, "Comma separated list of access modes to run for each Storage Class of --storage-matrix, defaults to --access-mode")
+	cmd.Flags().StringSliceVar(&m.volumeModes, "storage-matrix-volume-modes", []string{}, "Comma separated list of volume modes to run for each Storage Class of --storage-matrix - Filesystem, Block")
+}
+
+func (m *storageMatrix) enabled() bool {
+	return len(m.storageClasses) > 0
+}
+
+// validate exits when the matrix is inconsistent or uses access modes missing from the given translator
+func (m *storageMatrix) validate(accessModes map[string]string) {
+	if !m.enabled() {
+		if len(m.accessModes) > 0 || len(m.volumeModes) > 0 {
+			log.Fatal("--storage-matrix-access-modes and --storage-matrix-volume-modes require --storage-matrix")
+		}
+		return
+	}
+	for _, accessMode := range m.accessModes {
+		if _, ok := accessModes[accessMode]; !ok {
+			log.Fatalf("Unsupported access mode in storage matrix - %s", accessMode)
+		}
+	}
+	for _, volumeMode := range m.volumeModes {
+		if !slices.Contains(storageMatrixVolumeModes, volumeMode) {
+			log.Fatalf("Unsupported volume mode in storage matrix - %s", volumeMode)
+		}
+	}
+}
+
+// entries returns every combination of the matrix, access modes default to the given one and volume modes to the Storage Class one
+func (m *storageMatrix) entries(defaultAccessMode string) []storageMatrixEntry {
+	accessModes := m.accessModes
+	if len(accessModes) == 0 {
+		accessModes = []string{defaultAccessMode}
+	}
+	volumeModes := m.volumeModes
+	if len(volumeModes) == 0 {
+		volumeModes = []string{""}
+	}
+	var entries []storageMatrixEntry
+	for _, storageClass := range m.storageClasses {
+		for _, accessMode := range accessModes {
+			for _, volumeMode := range volumeModes {
+				entries = append(entries, storageMatrixEntry{storageClass: storageClass, accessMode: accessMode, volumeMode: volumeMode})
+			}
+		}
+	}
+	return entries
+}
+
+func (e storageMatrixEntry) String() string {
+	parts := []string{e.storageClass, e.accessMode}
+	if e.volumeMode != "" {
+		parts = append(parts, e.volumeMode)
+	}
+	return strings.Join(parts, "/")
+}
+
+// run runs the workload once per combination, tagging the indexed documents with it, and logs a side-by-side comparison of every run.
+// runWorkload returns the return code of the run and the directory where its documents were indexed locally, read to compare the quantiles.
+// A failed run doesn't stop the matrix, the return code of the last failed run is returned
+func (m *storageMatrix) run(wh *workloads.WorkloadHelper, defaultAccessMode string, measurementFactoryMap map[string]kubeburnermeasurements.NewMeasurementFactory, runWorkload func(storageMatrixEntry) (int, string)) int {
+	var rc int
+	comparison := measurements.NewLatencyComparison()
+	wh.SetMeasurements(measurementFactoryMap)
+	for _, entry := range m.entries(defaultAccessMode) {
+		log.Infof("Running storage matrix combination [%s]", entry)
+		comparison.SetRun(entry.String())
+		setStorageMatrixMetadata(wh, entry)
+		entryRC, metricsDirectory := runWorkload(entry)
+		comparison.SetResult(entry.String(), entryRC)
+		if err := comparison.Collect(metricsDirectory); err != nil {
+			log.Warnf("Failed to read the quantiles of storage matrix combination [%s]: %v", entry, err)
+		}
+		if entryRC != 0 {
+			log.Errorf("Storage matrix combination [%s] failed with rc %d", entry, entryRC)
+			rc = entryRC
+		}
+	}
+	log.Infof("Storage matrix comparison:\n%s", comparison.Table())
+	return rc
+}
+
+// metricsDirectory returns a directory of the combination below the given one, so that runs don't overwrite each other's documents
+func (e storageMatrixEntry) metricsDirectory(baseDirectory string) string {
+	return filepath.Join(baseDirectory, e.storageClass, e.accessMode, e.volumeMode)
+}
+
+// setStorageMatrixMetadata tags the metrics, measurements and job summaries with the storage combination
+func setStorageMatrixMetadata(wh *workloads.WorkloadHelper, entry storageMatrixEntry) {
+	if wh.MetricsMetadata == nil {
+		wh.MetricsMetadata = make(map[string]any)
+	}
+	if wh.SummaryMetadata == nil {
+		wh.SummaryMetadata = make(map[string]any)
+	}
+	for _, metadata := range []map[string]any{wh.MetricsMetadata, wh.SummaryMetadata} {
+		metadata["storageClass"] = entry.storageClass
+		metadata["accessMode"] = entry.accessMode
+		metadata["volumeMode"] = entry.volumeMode
+	}
+}
@@ -0,0 +1,31 @@
+package workloads
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStorageMatrixEntries(t *testing.T) {
+	matrix := storageMatrix{storageClasses: []string{"gp3-csi", "ocs-rbd"}}
+	var got []string
+	for _, entry := range matrix.entries("RWX") {
+		got = append(got, entry.String())
+	}
+	if want := []string{"gp3-csi/RWX", "ocs-rbd/RWX"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	matrix.accessModes = []string{"RWO", "RWX"}
+	matrix.volumeModes = []string{"Filesystem", "Block"}
+	got = nil
+	for _, entry := range matrix.entries("RWX") {
+		got = append(got, entry.String())
+	}
+	want := []string{
+		"gp3-csi/RWO/Filesystem", "gp3-csi/RWO/Block", "gp3-csi/RWX/Filesystem", "gp3-csi/RWX/Block",
+		"ocs-rbd/RWO/Filesystem", "ocs-rbd/RWO/Block", "ocs-rbd/RWX/Filesystem", "ocs-rbd/RWX/Block",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -32,12 +32,14 @@ func NewUDNDensityPods(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	var churnDelay, churnDuration, podReadyThreshold, pprofInterval time.Duration
 	var deletionStrategy, churnMode string
 	var metricsProfiles []string
+	var search capacitySearch
 	var rc int
 	cmd := &cobra.Command{
 		Use:          variant,
 		Short:        fmt.Sprintf("Runs %v workload", variant),
 		SilenceUsage: true,
 		Run: func(cmd *cobra.Command, args []string) {
+			search.validate(cmd, iterations)
 			setMetrics(cmd, metricsProfiles)
 			if l3 {
 				log.Info("Layer 3 is enabled")
@@ -60,6 +62,13 @@ func NewUDNDensityPods(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 			AdditionalVars["JOB_ITERATIONS"] = iterations
 			AdditionalVars["POD_READY_THRESHOLD"] = podReadyThreshold
 			AdditionalVars["ENABLE_LAYER_3"] = l3
+			if search.enabled {
+				rc = search.run(wh, iterations, func(size int) int {
+					AdditionalVars["JOB_ITERATIONS"] = size
+					return RunWorkload(cmd, wh, variant+".yml")
+				})
+				return
+			}
 			rc = RunWorkload(cmd, wh, variant+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -79,6 +88,7 @@ func NewUDNDensityPods(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	cmd.Flags().IntVar(&iterations, "iterations", 0, "Job iterations, (One UDN/CUDN will be created per iteration)")
 	cmd.Flags().DurationVar(&podReadyThreshold, "pod-ready-threshold", 0, "Pod ready timeout threshold")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	addCapacitySearchFlags(cmd, &search, "iterations")
 	cmd.MarkFlagRequired("iterations")
 	if variant == "cudn-density-pods" {
 		cmd.Annotations = map[string]string{"configDir": "udn-density-pods"}
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"slices"
+	"time"
 
 	k8sstorage "github.com/cloud-bulldozer/go-commons/v2/k8s-storage"
 	"github.com/cloud-bulldozer/go-commons/v2/ssh"
@@ -25,6 +27,8 @@ import (
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
 const (
@@ -50,8 +54,16 @@ func NewVirtCapacityBenchmark(wh *workloads.WorkloadHelper) *cobra.Command {
 	var minimalVolumeSize int
 	var minimalVolumeIncreaseSize int
 	var skipResizeJob bool
+	var guestReadyTimeout time.Duration
+	var guestReadyCloudInit bool
+	var fioVMs int
+	var fioTarget, fioArgs, fioInstallCommand string
+	var fioTimeout time.Duration
 	var metricsProfiles []string
+	var useVirtctl bool
 	var cleanup bool
+	var stopConditions loopStopConditions
+	var matrix storageMatrix
 	var rc int
 	cmd := &cobra.Command{
 		Use:          virtCapacityBenchmarkTestName,
@@ -62,9 +74,23 @@ func NewVirtCapacityBenchmark(wh *workloads.WorkloadHelper) *cobra.Command {
 				return
 			}
 
-			if !virtctl.IsInstalled() {
+			if useVirtctl && !virtctl.IsInstalled() {
 				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
 			}
+			if fioVMs > vmsPerIteration {
+				log.Fatalf("--fio-vms [%d] can't be greater than --vms [%d]", fioVMs, vmsPerIteration)
+			}
+			stopConditions.parse()
+			matrix.validate(accessModeTranslator)
+			if matrix.enabled() {
+				if storageClasses != nil {
+					log.Fatal("--storage-class and --storage-matrix are mutually exclusive")
+				}
+				if !skipMigrationJob && slices.ContainsFunc(matrix.accessModes, func(accessMode string) bool { return accessMode != "RWX" }) {
+					log.Fatal("The migration job requires RWX volumes - set --skip-migration-job to run other access modes of --storage-matrix")
+				}
+				storageClasses = matrix.storageClasses
+			}
 
 			if storageClasses == nil {
 				storageClassName, _ := getStorageAndSnapshotClasses("", true, true)
@@ -121,6 +147,7 @@ func NewVirtCapacityBenchmark(wh *workloads.WorkloadHelper) *cobra.Command {
 			if err != nil {
 				log.Warnf("Failed to get OCP Virtualization version: %v", err)
 			}
+			setVMCheckVars(useVirtctl)
 			AdditionalVars["privateKey"] = privateKeyPath
 			AdditionalVars["publicKey"] = publicKeyPath
 			AdditionalVars["vmCount"] = fmt.Sprint(vmsPerIteration)
@@ -134,27 +161,69 @@ func NewVirtCapacityBenchmark(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["VM_IMAGE"] = vmImage
 			AdditionalVars["VM_CPU"] = vmCPU
 			AdditionalVars["VM_MEMORY"] = vmMemory
+			AdditionalVars["guestReadyTimeout"] = guestReadyTimeout
+			AdditionalVars["guestReadyCloudInit"] = guestReadyCloudInit
+			AdditionalVars["fioVMs"] = fioVMs
+			AdditionalVars["fioTarget"] = fioTarget
+			AdditionalVars["fioArgs"] = fioArgs
+			AdditionalVars["fioInstallCommand"] = fioInstallCommand
+			AdditionalVars["fioTimeout"] = fioTimeout
+
+			AdditionalVars["accessMode"] = accessModeTranslator[capacityAccessMode(skipMigrationJob)]
+			AdditionalVars["volumeMode"] = ""
 
 			setMetrics(cmd, metricsProfiles)
+			stopConditions.connect(wh)
 			log.Infof("Running tests in Namespace [%s]", testNamespace)
-			counter := 0
-			for {
-				storageClassName := storageClasses[counter%len(storageClasses)]
-				log.Infof("Running loop %d with Storage Class [%s]", counter, storageClassName)
-				AdditionalVars["storageClassName"] = storageClassName
-
-				os.Setenv("counter", fmt.Sprint(counter))
-				rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
-				if rc != 0 {
-					log.Errorf("Capacity failed in loop #%d", counter)
-					break
-				}
-				counter += 1
-				if maxIterations > 0 && counter >= maxIterations {
-					log.Infof("Reached maxIterations [%d]", maxIterations)
-					break
+			runLoops := func(storageClasses []string, metricsBaseDirectory string) int {
+				var loopRC int
+				AdditionalVars["metricsBaseDirectory"] = metricsBaseDirectory
+				comparison := measurements.NewLatencyComparison()
+				var loops []capacityLoop
+				counter := 0
+				for {
+					storageClassName := storageClasses[counter%len(storageClasses)]
+					log.Infof("Running loop %d with Storage Class [%s]", counter, storageClassName)
+					AdditionalVars["storageClassName"] = storageClassName
+
+					os.Setenv("counter", fmt.Sprint(counter))
+					comparison.SetRun(capacityLoopRun(counter))
+					loopRC = RunWorkload(cmd, wh, cmd.Name()+".yml")
+					if err := comparison.Collect(capacityLoopMetricsDirectory(metricsBaseDirectory, counter)); err != nil {
+						log.Warnf("Failed to read the quantiles of loop #%d: %v", counter, err)
+					}
+					loop := capacityLoop{counter: counter, vmCount: vmsPerIteration * (counter + 1), storageClass: storageClassName, rc: loopRC}
+					if loopRC != 0 {
+						log.Errorf("Capacity failed in loop #%d", counter)
+						loops = append(loops, loop)
+						break
+					}
+					loop.stopReason = stopConditions.check(comparison, capacityLoopRun(counter))
+					loops = append(loops, loop)
+					if loop.stopReason != "" {
+						log.Infof("Stop condition met in loop #%d: %s", counter, loop.stopReason)
+						break
+					}
+					counter += 1
+					if maxIterations > 0 && counter >= maxIterations {
+						log.Infof("Reached maxIterations [%d]", maxIterations)
+						break
+					}
 				}
+				log.Infof("Loop summary:\n%s", stopConditions.summary(loops, comparison))
+				return loopRC
+			}
+			if matrix.enabled() {
+				rc = matrix.run(wh, capacityAccessMode(skipMigrationJob), virtMeasurementFactoryMap, func(entry storageMatrixEntry) (int, string) {
+					AdditionalVars["accessMode"] = accessModeTranslator[entry.accessMode]
+					AdditionalVars["volumeMode"] = entry.volumeMode
+					metricsBaseDirectory := entry.metricsDirectory(virtCapacityBenchmarkTestName)
+					return runLoops([]string{entry.storageClass}, metricsBaseDirectory), metricsBaseDirectory
+				})
+				return
 			}
+			wh.SetMeasurements(virtMeasurementFactoryMap)
+			rc = runLoops(storageClasses, virtCapacityBenchmarkTestName)
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
 			os.Exit(rc)
@@ -173,7 +242,25 @@ func NewVirtCapacityBenchmark(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().IntVar(&minimalVolumeSize, "min-vol-size", 0, "Minimal volume size - use when enforced or overridden by the StorageClass")
 	cmd.Flags().IntVar(&minimalVolumeIncreaseSize, "min-vol-inc-size", 0, "Minimal volume increment size - use when enforced or overridden by the StorageClass")
 	cmd.Flags().BoolVar(&skipResizeJob, "skip-resize-job", false, "Skip the resize propagation check - For now use when values are propagated in a base of 10 instead of 2")
+	cmd.Flags().DurationVar(&guestReadyTimeout, "guest-ready-timeout", 1*time.Hour, "Maximum time to wait for each VM to become reachable through SSH")
+	cmd.Flags().BoolVar(&guestReadyCloudInit, "guest-ready-cloud-init", false, "Also measure the time until cloud-init completes in the guest")
+	cmd.Flags().IntVar(&fioVMs, "fio-vms", 0, "Number of VMs of each loop running fio once booted, 0 to skip the storage I/O benchmark")
+	cmd.Flags().StringVar(&fioTarget, "fio-target", "/var/tmp/kube-burner-fio", "File or device benchmarked by fio in the guest, e.g. /dev/vdb for the first data volume")
+	cmd.Flags().StringVar(&fioArgs, "fio-args", "--rw=randrw --bs=4k --iodepth=16 --ioengine=libaio --direct=1 --size=256M --runtime=60s --time_based", "fio arguments describing the workload")
+	cmd.Flags().StringVar(&fioInstallCommand, "fio-install-command", "command -v fio || sudo dnf install -y -q fio", "Command installing fio in the guest, empty when the image provides it")
+	cmd.Flags().DurationVar(&fioTimeout, "fio-timeout", 15*time.Minute, "Maximum time for each VM to become reachable, install fio and run it")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics-aggregated.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
+	addLoopStopConditionFlags(cmd, &stopConditions)
+	addStorageMatrixFlags(cmd, &matrix)
 	return cmd
 }
+
+// capacityAccessMode returns the access mode of the volumes of the capacity workloads, RWX unless the migration job is skipped
+func capacityAccessMode(skipMigrationJob bool) string {
+	if skipMigrationJob {
+		return "RWO"
+	}
+	return "RWX"
+}
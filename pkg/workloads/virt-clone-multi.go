@@ -51,6 +51,7 @@ func NewVirtCloneMulti(wh *workloads.WorkloadHelper) *cobra.Command {
 	var jobIterationDelay time.Duration
 	var testNamespaceBaseName string
 	var metricsProfiles []string
+	var useVirtctl bool
 	var cleanup bool
 	var rc int
 
@@ -67,7 +68,7 @@ func NewVirtCloneMulti(wh *workloads.WorkloadHelper) *cobra.Command {
 				log.Fatalf("Unsupported access mode - %s", volumeAccessMode)
 			}
 
-			if !virtctl.IsInstalled() {
+			if useVirtctl && !virtctl.IsInstalled() {
 				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
 			}
 
@@ -99,6 +100,7 @@ func NewVirtCloneMulti(wh *workloads.WorkloadHelper) *cobra.Command {
 			log.Infof("Using Storage Class [%s], VolumeSnapshotClass [%s]", storageClassName, volumeSnapshotClassName)
 			log.Infof("Use Snapshot: %t", useSnapshot)
 
+			setVMCheckVars(useVirtctl)
 			AdditionalVars["privateKey"] = privateKeyPath
 			AdditionalVars["publicKey"] = publicKeyPath
 			AdditionalVars["storageClassName"] = storageClassName
@@ -116,6 +118,7 @@ func NewVirtCloneMulti(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["VM_MEMORY"] = vmMemory
 
 			setMetrics(cmd, metricsProfiles)
+			wh.SetMeasurements(virtMeasurementFactoryMap)
 
 			// Run workload once - kube-burner will handle namespace iterations
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
@@ -139,6 +142,7 @@ func NewVirtCloneMulti(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().DurationVar(&jobIterationDelay, "job-iteration-delay", 1*time.Minute, "Delay between namespace iterations")
 	cmd.Flags().StringVarP(&testNamespaceBaseName, "namespace", "n", virtCloneMultiTestName, "Base namespace name for the test")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics-aggregated.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
 
 	return cmd
@@ -54,8 +54,14 @@ func NewVirtClone(wh *workloads.WorkloadHelper) *cobra.Command {
 	var verifyEachIteration bool
 	var jobIterationDelay time.Duration
 	var verifyMaxWaitTime time.Duration
+	var guestReadyTimeout time.Duration
+	var guestReadyCloudInit bool
 	var dataVolumeCount int
+	var useVirtctl bool
 	var cleanup bool
+	var matrix storageMatrix
+	var integrity cloneIntegrity
+	var matrixSnapshotClasses map[string]string
 	var rc int
 	cmd := &cobra.Command{
 		Use:          virtCloneTestName,
@@ -69,11 +75,19 @@ func NewVirtClone(wh *workloads.WorkloadHelper) *cobra.Command {
 				log.Fatalf("Unsupported access mode - %s", volumeAccessMode)
 			}
 
-			if !virtctl.IsInstalled() {
+			if useVirtctl && !virtctl.IsInstalled() {
 				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
 			}
 
-			storageClassName, volumeSnapshotClassName = getStorageAndSnapshotClasses(storageClassName, useSnapshot, cmd.Flags().Lookup("use-snapshot").Changed)
+			matrix.validate(accessModeTranslator)
+			if matrix.enabled() {
+				matrixSnapshotClasses = make(map[string]string)
+				for _, storageClass := range matrix.storageClasses {
+					_, matrixSnapshotClasses[storageClass] = getStorageAndSnapshotClasses(storageClass, useSnapshot, cmd.Flags().Lookup("use-snapshot").Changed)
+				}
+			} else {
+				storageClassName, volumeSnapshotClassName = getStorageAndSnapshotClasses(storageClassName, useSnapshot, cmd.Flags().Lookup("use-snapshot").Changed)
+			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if cleanup {
@@ -89,6 +103,7 @@ func NewVirtClone(wh *workloads.WorkloadHelper) *cobra.Command {
 			if err != nil {
 				log.Warnf("Failed to get OCP Virtualization version: %v", err)
 			}
+			setVMCheckVars(useVirtctl)
 			AdditionalVars["privateKey"] = privateKeyPath
 			AdditionalVars["publicKey"] = publicKeyPath
 			AdditionalVars["VM_IMAGE"] = vmImage
@@ -104,8 +119,26 @@ func NewVirtClone(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["jobIterationDelay"] = jobIterationDelay
 			AdditionalVars["verifyMaxWaitTime"] = verifyMaxWaitTime
 			AdditionalVars["dataVolumeCounters"] = generateLoopCounterSlice(dataVolumeCount, 1)
+			AdditionalVars["guestReadyTimeout"] = guestReadyTimeout
+			AdditionalVars["guestReadyCloudInit"] = guestReadyCloudInit
+			AdditionalVars["volumeMode"] = ""
+			AdditionalVars["metricsDirectory"] = virtCloneTestName + "-results"
+			integrity.setVars(0)
 
 			setMetrics(cmd, metricsProfiles)
+			if matrix.enabled() {
+				rc = matrix.run(wh, volumeAccessMode, virtMeasurementFactoryMap, func(entry storageMatrixEntry) (int, string) {
+					AdditionalVars["storageClassName"] = entry.storageClass
+					AdditionalVars["volumeSnapshotClassName"] = matrixSnapshotClasses[entry.storageClass]
+					AdditionalVars["accessMode"] = accessModeTranslator[entry.accessMode]
+					AdditionalVars["volumeMode"] = entry.volumeMode
+					metricsDirectory := entry.metricsDirectory(virtCloneTestName + "-results")
+					AdditionalVars["metricsDirectory"] = metricsDirectory
+					return RunWorkload(cmd, wh, cmd.Name()+".yml"), metricsDirectory
+				})
+				return
+			}
+			wh.SetMeasurements(virtMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -125,8 +158,13 @@ func NewVirtClone(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().BoolVar(&verifyEachIteration, "verify-each-iteration", true, "Wait for each iteration to complete and verify before starting the next one")
 	cmd.Flags().DurationVar(&jobIterationDelay, "job-iteration-delay", 0, "Delay between job iterations")
 	cmd.Flags().DurationVar(&verifyMaxWaitTime, "verify-max-wait-time", 1*time.Hour, "Max wait time for clone creation waiting")
+	cmd.Flags().DurationVar(&guestReadyTimeout, "guest-ready-timeout", 1*time.Hour, "Maximum time to wait for each VM to become reachable through SSH")
+	cmd.Flags().BoolVar(&guestReadyCloudInit, "guest-ready-cloud-init", false, "Also measure the time until cloud-init completes in the guest")
 	cmd.Flags().IntVar(&dataVolumeCount, "data-volume-count", virtCloneDefaultDataVolumeCount, "Number of data volumes per VM")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
+	addStorageMatrixFlags(cmd, &matrix)
+	addCloneIntegrityFlags(cmd, &integrity, false)
 	return cmd
 }
@@ -19,6 +19,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/cloud-bulldozer/go-commons/v2/ssh"
+	"github.com/cloud-bulldozer/go-commons/v2/virtctl"
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
@@ -26,6 +28,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	virtDensitySSHKeyFileName = "ssh"
+	virtDensityTmpDirPattern  = "kube-burner-virt-density-*"
+)
+
 var (
 	virtDensityNamespaceLabelSelector = fmt.Sprintf("%s=%s", kubeBurnerTestNameLabelKey, "virt-density")
 )
@@ -36,20 +43,44 @@ func NewVirtDensity(wh *workloads.WorkloadHelper) *cobra.Command {
 	var vmsPerNode, iterationsPerNamespace, churnPercent, churnCycles int
 	var vmiRunningThreshold time.Duration
 	var namespacedIterations, mounts bool
+	var guestReady, guestReadyCloudInit, guestBootMilestones bool
+	var guestReadyTimeout, bootMilestonesTimeout time.Duration
+	var sshKeyPairPath string
 	var churnDelay, churnDuration time.Duration
 	var metricsProfiles []string
+	var useVirtctl bool
 	var cleanup bool
 	var rc int
 	cmd := &cobra.Command{
 		Use:          "virt-density",
 		Short:        "Runs virt-density workload",
 		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if cleanup || !guestReady {
+				return
+			}
+			if !mounts {
+				log.Fatal("--guest-ready requires --mounts=true to inject the SSH key through cloud-init")
+			}
+			if useVirtctl && !virtctl.IsInstalled() {
+				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			if cleanup {
 				log.Infof("Cleaning up all the resources from the previous run")
 				cleanupTestNamespaces(cmd.Context(), virtDensityNamespaceLabelSelector)
 				return
 			}
+			if guestReady {
+				privateKeyPath, publicKeyPath, err := ssh.GenerateSSHKeyPair(sshKeyPairPath, virtDensityTmpDirPattern, virtDensitySSHKeyFileName)
+				if err != nil {
+					log.Fatalf("Failed to generate SSH keys for the test - %v", err)
+				}
+				AdditionalVars["privateKey"] = privateKeyPath
+				AdditionalVars["publicKey"] = publicKeyPath
+				setVMCheckVars(useVirtctl)
+			}
 			totalVMs := clusterMetadata.WorkerNodesCount * vmsPerNode
 			vmCount, err := wh.MetadataAgent.GetCurrentVMICount()
 
@@ -70,7 +101,13 @@ func NewVirtDensity(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["CHURN_MODE"] = churnMode
 			AdditionalVars["DELETION_STRATEGY"] = deletionStrategy
 			AdditionalVars["MOUNTS"] = mounts
+			AdditionalVars["GUEST_READY"] = guestReady
+			AdditionalVars["GUEST_READY_CLOUD_INIT"] = guestReadyCloudInit
+			AdditionalVars["GUEST_READY_TIMEOUT"] = guestReadyTimeout
+			AdditionalVars["GUEST_BOOT_MILESTONES"] = guestBootMilestones
+			AdditionalVars["BOOT_MILESTONES_TIMEOUT"] = bootMilestonesTimeout
 			setMetrics(cmd, metricsProfiles)
+			wh.SetMeasurements(virtMeasurementFactoryMap)
 			AddVirtMetadata(wh, vmImage, "", "")
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
 		},
@@ -93,6 +130,13 @@ func NewVirtDensity(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().DurationVar(&churnDelay, "churn-delay", 2*time.Minute, "Time to wait between each churn")
 	cmd.Flags().IntVar(&churnPercent, "churn-percent", 10, "Percentage of job iterations that kube-burner will churn each round")
 	cmd.Flags().StringVar(&churnMode, "churn-mode", string(config.ChurnObjects), "Either namespaces, to churn entire namespaces or objects, to churn individual objects")
+	cmd.Flags().BoolVar(&guestReady, "guest-ready", false, "Measure the time from VMI Running to the first successful SSH command in the guest, requires --mounts")
+	cmd.Flags().BoolVar(&guestReadyCloudInit, "guest-ready-cloud-init", false, "Also measure the time until cloud-init completes in the guest")
+	cmd.Flags().BoolVar(&guestBootMilestones, "guest-boot-milestones", false, "Collect the guest kernel start and cloud-init stages times from the VM serial console, cloud-init stages require --mounts")
+	cmd.Flags().DurationVar(&guestReadyTimeout, "guest-ready-timeout", 15*time.Minute, "Maximum time to wait for each guest to become reachable")
+	cmd.Flags().DurationVar(&bootMilestonesTimeout, "boot-milestones-timeout", 15*time.Minute, "Maximum time to wait for the boot milestones of each guest in its serial console")
+	cmd.Flags().StringVar(&sshKeyPairPath, "ssh-key-path", "", "Path to save the generarated SSH keys - default to a temporary location")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
 	return cmd
 }
@@ -0,0 +1,170 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/ssh"
+	"github.com/cloud-bulldozer/go-commons/v2/virtctl"
+	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	virtHotplugSSHKeyFileName = "ssh"
+	virtHotplugTmpDirPattern  = "kube-burner-virt-hotplug-*"
+	virtHotplugTestName       = "virt-hotplug"
+)
+
+var (
+	virtHotplugNamespaceLabelSelector = fmt.Sprintf("%s=%s", kubeBurnerTestNameLabelKey, virtHotplugTestName)
+)
+
+// NewVirtHotplug holds the virt-hotplug workload
+func NewVirtHotplug(wh *workloads.WorkloadHelper) *cobra.Command {
+	var storageClassName string
+	var sshKeyPairPath string
+	var vmImage string
+	var vmMemory, maxMemory, hotplugMemory string
+	var vmSockets, maxSockets, hotplugSockets int
+	var vmCount int
+	var waves int
+	var volumeCount int
+	var volumeSize string
+	var volumeAccessMode string
+	var testNamespace string
+	var qps, burst int
+	var hotplugTimeout time.Duration
+	var skipVolumes, skipCPU, skipMemory bool
+	var metricsProfiles []string
+	var useVirtctl bool
+	var cleanup bool
+	var rc int
+	cmd := &cobra.Command{
+		Use:          virtHotplugTestName,
+		Short:        "Runs virt-hotplug workload",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if cleanup {
+				return
+			}
+			if _, ok := accessModeTranslator[volumeAccessMode]; !ok {
+				log.Fatalf("Unsupported access mode - %s", volumeAccessMode)
+			}
+			if useVirtctl && !virtctl.IsInstalled() {
+				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
+			}
+			if waves < 1 || waves > vmCount {
+				log.Fatalf("--waves must be between 1 and --vms [%d]", vmCount)
+			}
+			if !skipCPU && (hotplugSockets <= vmSockets || hotplugSockets > maxSockets) {
+				log.Fatalf("--hotplug-sockets must be greater than --vm-sockets [%d] and not greater than --max-sockets [%d]", vmSockets, maxSockets)
+			}
+			if !skipMemory {
+				var quantities []resource.Quantity
+				for _, memory := range []string{vmMemory, hotplugMemory, maxMemory} {
+					quantity, err := resource.ParseQuantity(memory)
+					if err != nil {
+						log.Fatalf("Invalid memory %s - %v", memory, err)
+					}
+					quantities = append(quantities, quantity)
+				}
+				if quantities[1].Cmp(quantities[0]) <= 0 || quantities[1].Cmp(quantities[2]) > 0 {
+					log.Fatalf("--hotplug-memory must be greater than --vm-memory [%s] and not greater than --max-memory [%s]", vmMemory, maxMemory)
+				}
+			}
+			if !skipVolumes {
+				storageClassName, _ = getStorageAndSnapshotClasses(storageClassName, false, true)
+			}
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if cleanup {
+				log.Infof("Cleaning up all the resources from the previous run")
+				cleanupTestNamespaces(cmd.Context(), virtHotplugNamespaceLabelSelector)
+				return
+			}
+			privateKeyPath, publicKeyPath, err := ssh.GenerateSSHKeyPair(sshKeyPairPath, virtHotplugTmpDirPattern, virtHotplugSSHKeyFileName)
+			if err != nil {
+				log.Fatalf("Failed to generate SSH keys for the test - %v", err)
+			}
+			wh.SummaryMetadata["OCPVirtualizationVersion"], err = wh.MetadataAgent.GetOCPVirtualizationVersion()
+			if err != nil {
+				log.Warnf("Failed to get OCP Virtualization version: %v", err)
+			}
+			setVMCheckVars(useVirtctl)
+			AdditionalVars["privateKey"] = privateKeyPath
+			AdditionalVars["publicKey"] = publicKeyPath
+			AdditionalVars["testNamespace"] = testNamespace
+			AdditionalVars["vmCount"] = vmCount
+			AdditionalVars["waves"] = waves
+			AdditionalVars["waveCounters"] = generateLoopCounterSlice(waves, 0)
+			AdditionalVars["hotplugVolumeCounters"] = generateLoopCounterSlice(volumeCount, 1)
+			AdditionalVars["volumeSize"] = volumeSize
+			AdditionalVars["storageClassName"] = storageClassName
+			AdditionalVars["accessMode"] = accessModeTranslator[volumeAccessMode]
+			AdditionalVars["vmSockets"] = vmSockets
+			AdditionalVars["maxSockets"] = maxSockets
+			AdditionalVars["hotplugSockets"] = hotplugSockets
+			AdditionalVars["maxMemory"] = maxMemory
+			AdditionalVars["hotplugMemory"] = hotplugMemory
+			AdditionalVars["hotplugTimeout"] = hotplugTimeout
+			AdditionalVars["hotplugQPS"] = qps
+			AdditionalVars["hotplugBurst"] = burst
+			AdditionalVars["skipVolumes"] = skipVolumes || volumeCount == 0
+			AdditionalVars["skipCPU"] = skipCPU
+			AdditionalVars["skipMemory"] = skipMemory
+			AdditionalVars["VM_IMAGE"] = vmImage
+			AdditionalVars["VM_MEMORY"] = vmMemory
+
+			setMetrics(cmd, metricsProfiles)
+			wh.SetMeasurements(virtMeasurementFactoryMap)
+			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
+		},
+		PostRun: func(cmd *cobra.Command, args []string) {
+			os.Exit(rc)
+		},
+	}
+	cmd.Flags().StringVar(&storageClassName, "storage-class", "", "Name of the Storage Class of the hotplugged volumes")
+	cmd.Flags().StringVar(&sshKeyPairPath, "ssh-key-path", "", "Path to save the generarated SSH keys")
+	cmd.Flags().StringVarP(&testNamespace, "namespace", "n", virtHotplugTestName, "Base name for the namespaces to run the test in, each VM has its own namespace")
+	cmd.Flags().IntVar(&vmCount, "vms", 10, "Number of VMs")
+	cmd.Flags().IntVar(&waves, "waves", 2, "Number of groups of VMs each hotplug operation is applied to one after the other")
+	cmd.Flags().IntVar(&qps, "qps", 20, "QPS of the hotplug operations of each wave")
+	cmd.Flags().IntVar(&burst, "burst", 20, "Burst of the hotplug operations of each wave")
+	cmd.Flags().IntVar(&volumeCount, "hotplug-volumes", 2, "Number of volumes hotplugged and unplugged in each VM")
+	cmd.Flags().StringVar(&volumeSize, "volume-size", "1Gi", "Size of the hotplugged volumes")
+	cmd.Flags().StringVar(&volumeAccessMode, "access-mode", "RWX", "Access mode for the hotplugged volumes - RO, RWO, RWX")
+	cmd.Flags().StringVar(&vmImage, "vm-image", "quay.io/containerdisks/fedora:41", "Container disk image of the VMs")
+	cmd.Flags().IntVar(&vmSockets, "vm-sockets", 1, "Initial number of CPU sockets of the VMs")
+	cmd.Flags().IntVar(&hotplugSockets, "hotplug-sockets", 2, "Number of CPU sockets the VMs are scaled to")
+	cmd.Flags().IntVar(&maxSockets, "max-sockets", 4, "Maximum number of CPU sockets of the VMs")
+	cmd.Flags().StringVar(&vmMemory, "vm-memory", "1Gi", "Initial guest memory of the VMs")
+	cmd.Flags().StringVar(&hotplugMemory, "hotplug-memory", "2Gi", "Guest memory the VMs are scaled to")
+	cmd.Flags().StringVar(&maxMemory, "max-memory", "4Gi", "Maximum guest memory of the VMs")
+	cmd.Flags().DurationVar(&hotplugTimeout, "hotplug-timeout", 15*time.Minute, "Maximum time for each wave to reflect a hotplug operation in the VMI status and in the guest")
+	cmd.Flags().BoolVar(&skipVolumes, "skip-volume-hotplug", false, "Skip the volume hotplug and unplug jobs")
+	cmd.Flags().BoolVar(&skipCPU, "skip-cpu-hotplug", false, "Skip the CPU sockets scaling job")
+	cmd.Flags().BoolVar(&skipMemory, "skip-memory-hotplug", false, "Skip the memory scaling job")
+	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
+	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
+	return cmd
+}
@@ -15,15 +15,21 @@
 package workloads
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 
 	"github.com/cloud-bulldozer/go-commons/v2/ssh"
 	"github.com/cloud-bulldozer/go-commons/v2/virtctl"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
 
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
 const (
@@ -43,6 +49,51 @@ var (
 	virtMigrationNamespaceLabelSelector = fmt.Sprintf("%s=%s", kubeBurnerTestNameLabelKey, virtMigrationTestName)
 )
 
+// migrationPolicyParams is a parameter set of the MigrationPolicy matrix
+type migrationPolicyParams struct {
+	Name                    string `json:"name"`
+	BandwidthPerMigration   string `json:"bandwidthPerMigration,omitempty"`
+	CompletionTimeoutPerGiB *int64 `json:"completionTimeoutPerGiB,omitempty"`
+	AllowPostCopy           *bool  `json:"allowPostCopy,omitempty"`
+	AllowAutoConverge       *bool  `json:"allowAutoConverge,omitempty"`
+}
+
+// loadMigrationPolicies reads the MigrationPolicy parameter sets, only the parameters set in the file are passed to the template
+func loadMigrationPolicies(path string) ([]map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var params []migrationPolicyParams
+	if err := yaml.UnmarshalStrict(data, &params); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("no MigrationPolicy defined in %s", path)
+	}
+	names := make(map[string]struct{}, len(params))
+	policies := make([]map[string]any, 0, len(params))
+	for _, p := range params {
+		if errs := validation.IsDNS1123Label(p.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid MigrationPolicy name %q: %v", p.Name, errs)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, fmt.Errorf("duplicated MigrationPolicy name %s", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		jsonData, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		var policy map[string]any
+		if err := json.Unmarshal(jsonData, &policy); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
 // Returns virt-density workload
 func NewVirtMigration(wh *workloads.WorkloadHelper) *cobra.Command {
 	var storageClassName string
@@ -57,6 +108,9 @@ func NewVirtMigration(wh *workloads.WorkloadHelper) *cobra.Command {
 	var loadVMsIterations int
 	var loadVMsPerIteration int
 	var migrationQPS int
+	var migrationPoliciesPath string
+	var migrationPolicies []map[string]any
+	var useVirtctl bool
 	var cleanup bool
 	var rc int
 	cmd := &cobra.Command{
@@ -67,12 +121,20 @@ func NewVirtMigration(wh *workloads.WorkloadHelper) *cobra.Command {
 			if cleanup {
 				return
 			}
-			if !virtctl.IsInstalled() {
+			if useVirtctl && !virtctl.IsInstalled() {
 				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
 			}
 
 			storageClassName, _ = getStorageAndSnapshotClasses(storageClassName, false, true)
 
+			if migrationPoliciesPath != "" {
+				var err error
+				migrationPolicies, err = loadMigrationPolicies(migrationPoliciesPath)
+				if err != nil {
+					log.Fatalf("Failed to load MigrationPolicies: %v", err)
+				}
+			}
+
 			workerNodeName = verifyOrGetRandomWorkerNodeName(workerNodeName)
 			log.Infof("Test will schedule on and migrate from worker node [%v]", workerNodeName)
 		},
@@ -90,6 +152,7 @@ func NewVirtMigration(wh *workloads.WorkloadHelper) *cobra.Command {
 			if err != nil {
 				log.Warnf("Failed to get OCP Virtualization version: %v", err)
 			}
+			setVMCheckVars(useVirtctl)
 			AdditionalVars["privateKey"] = privateKeyPath
 			AdditionalVars["publicKey"] = publicKeyPath
 			AdditionalVars["storageClassName"] = storageClassName
@@ -101,12 +164,29 @@ func NewVirtMigration(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["loadVMsIterations"] = loadVMsIterations
 			AdditionalVars["loadVMsPerIteration"] = loadVMsPerIteration
 			AdditionalVars["migrationQPS"] = migrationQPS
+			AdditionalVars["migrationPolicies"] = migrationPolicies
 			AdditionalVars["VM_IMAGE"] = vmImage
 			AdditionalVars["VM_CPU"] = vmCPU
 			AdditionalVars["VM_MEMORY"] = vmMemory
 
 			setMetrics(cmd, metricsProfiles)
+			// Collect the migration results of every policy to compare them once all the runs finish
+			var comparison *measurements.VMIMPolicyComparison
+			if migrationPolicies != nil {
+				comparison = measurements.NewVMIMPolicyComparison()
+			}
+			// Prometheus only provides the data processed by the migrations, the run goes on without it
+			prometheusClient, err := connectPrometheus(wh)
+			if err != nil {
+				log.Warnf("Migration data processed won't be reported: %v", err)
+			}
+			measurementFactoryMap := maps.Clone(virtMeasurementFactoryMap)
+			measurementFactoryMap["vmimPhaseLatency"] = measurements.NewVMIMPhaseLatencyFactory(prometheusClient, comparison)
+			wh.SetMeasurements(measurementFactoryMap)
 			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
+			if comparison != nil {
+				log.Infof("MigrationPolicy comparison of migration durations:\n%s", comparison.Table())
+			}
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
 			os.Exit(rc)
@@ -125,7 +205,9 @@ func NewVirtMigration(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().IntVar(&loadVMsIterations, "load-iterations", virtMigrationDefaultLoadVMsIteration, "Number of iterations to create load VMs")
 	cmd.Flags().IntVar(&loadVMsPerIteration, "load-per-iteration", virtMigrationDefaultLoadVMsPerIteration, "Number of VMs to create in each load VM iteration")
 	cmd.Flags().IntVar(&migrationQPS, "migration-qps", virtMigrationDefaultMigrationQPS, "Number of concurrent calls to migrate")
+	cmd.Flags().StringVar(&migrationPoliciesPath, "migration-policies", "", "Path to a YAML list of MigrationPolicy parameter sets, the VMs are migrated once per policy")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
 	return cmd
 }
@@ -0,0 +1,60 @@
+package workloads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMigrationPolicies(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policies.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMigrationPolicies(t *testing.T) {
+	path := writeMigrationPolicies(t, `
+- name: bw-64mi
+  bandwidthPerMigration: 64Mi
+  allowPostCopy: false
+- name: post-copy
+  completionTimeoutPerGiB: 150
+  allowPostCopy: true
+`)
+	policies, err := loadMigrationPolicies(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("expected 2 policies, got %d", len(policies))
+	}
+	if policies[0]["bandwidthPerMigration"] != "64Mi" || policies[0]["allowPostCopy"] != false {
+		t.Errorf("unexpected first policy %v", policies[0])
+	}
+	if _, ok := policies[0]["completionTimeoutPerGiB"]; ok {
+		t.Errorf("unset parameters must not be passed to the template, got %v", policies[0])
+	}
+	if policies[1]["completionTimeoutPerGiB"] != float64(150) {
+		t.Errorf("unexpected second policy %v", policies[1])
+	}
+}
+
+func TestLoadMigrationPoliciesErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "[]",
+		"unknown key":    "- name: a\n  bandwidth: 64Mi\n",
+		"missing name":   "- allowPostCopy: true\n",
+		"invalid name":   "- name: Not_Valid\n",
+		"duplicate name": "- name: a\n- name: a\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := loadMigrationPolicies(writeMigrationPolicies(t, content)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"slices"
 
 	k8sstorage "github.com/cloud-bulldozer/go-commons/v2/k8s-storage"
 	"github.com/cloud-bulldozer/go-commons/v2/ssh"
@@ -25,6 +26,8 @@ import (
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	"github.com/kube-burner/kube-burner-ocp/pkg/measurements"
 )
 
 const (
@@ -55,7 +58,10 @@ func NewVirtParallel(wh *workloads.WorkloadHelper) *cobra.Command {
 	var skipRestartJob bool
 	var skipSnapshotJob bool
 	var metricsProfiles []string
+	var useVirtctl bool
 	var cleanup bool
+	var stopConditions loopStopConditions
+	var matrix storageMatrix
 	var rc int
 	cmd := &cobra.Command{
 		Use:          virtParallelTestName,
@@ -66,9 +72,20 @@ func NewVirtParallel(wh *workloads.WorkloadHelper) *cobra.Command {
 				return
 			}
 
-			if !virtctl.IsInstalled() {
+			if useVirtctl && !virtctl.IsInstalled() {
 				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
 			}
+			stopConditions.parse()
+			matrix.validate(accessModeTranslator)
+			if matrix.enabled() {
+				if storageClasses != nil {
+					log.Fatal("--storage-class and --storage-matrix are mutually exclusive")
+				}
+				if !skipMigrationJob && slices.ContainsFunc(matrix.accessModes, func(accessMode string) bool { return accessMode != "RWX" }) {
+					log.Fatal("The migration job requires RWX volumes - set --skip-migration-job to run other access modes of --storage-matrix")
+				}
+				storageClasses = matrix.storageClasses
+			}
 
 			if storageClasses == nil {
 				storageClassName, _ := getStorageAndSnapshotClasses("", true, true)
@@ -128,6 +145,7 @@ func NewVirtParallel(wh *workloads.WorkloadHelper) *cobra.Command {
 				log.Infof("skipSnapshotJob is set to true")
 			}
 
+			setVMCheckVars(useVirtctl)
 			AdditionalVars["privateKey"] = privateKeyPath
 			AdditionalVars["publicKey"] = publicKeyPath
 			AdditionalVars["vmCount"] = fmt.Sprint(initialVms)
@@ -145,37 +163,72 @@ func NewVirtParallel(wh *workloads.WorkloadHelper) *cobra.Command {
 			AdditionalVars["VM_CPU"] = vmCPU
 			AdditionalVars["VM_MEMORY"] = vmMemory
 
+			AdditionalVars["accessMode"] = accessModeTranslator[capacityAccessMode(skipMigrationJob)]
+			AdditionalVars["volumeMode"] = ""
+
 			setMetrics(cmd, metricsProfiles)
+			stopConditions.connect(wh)
 
 			log.Infof("Running tests in Namespace [%s]", testNamespace)
-			counter := 0
-			vmCount = initialVms
-			for {
-				storageClassName := storageClasses[counter%len(storageClasses)]
-				log.Infof("Running loop %d with Storage Class [%s]", counter, storageClassName)
-				AdditionalVars["storageClassName"] = storageClassName
-				AdditionalVars["vmCount"] = vmCount
-
-				// Randomly select a node for migration
-				if !skipMigrationJob {
-					selectedNode := verifyOrGetRandomWorkerNodeName("")
-					AdditionalVars["selectedNode"] = selectedNode
-					log.Infof("Selected node for migration: %s", selectedNode)
-				}
+			runLoops := func(storageClasses []string, metricsBaseDirectory string) int {
+				var loopRC int
+				AdditionalVars["metricsBaseDirectory"] = metricsBaseDirectory
+				comparison := measurements.NewLatencyComparison()
+				var loops []capacityLoop
+				counter := 0
+				vmCount = initialVms
+				for {
+					storageClassName := storageClasses[counter%len(storageClasses)]
+					log.Infof("Running loop %d with Storage Class [%s]", counter, storageClassName)
+					AdditionalVars["storageClassName"] = storageClassName
+					AdditionalVars["vmCount"] = vmCount
 
-				os.Setenv("counter", fmt.Sprint(counter))
-				rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
-				if rc != 0 {
-					log.Errorf("Capacity failed in loop #%d", counter)
-					break
-				}
-				counter += 1
-				vmCount += vmsIncrement
-				if maxIterations > 0 && counter >= maxIterations {
-					log.Infof("Reached maxIterations [%d]", maxIterations)
-					break
+					// Randomly select a node for migration
+					if !skipMigrationJob {
+						selectedNode := verifyOrGetRandomWorkerNodeName("")
+						AdditionalVars["selectedNode"] = selectedNode
+						log.Infof("Selected node for migration: %s", selectedNode)
+					}
+
+					os.Setenv("counter", fmt.Sprint(counter))
+					comparison.SetRun(capacityLoopRun(counter))
+					loopRC = RunWorkload(cmd, wh, cmd.Name()+".yml")
+					if err := comparison.Collect(capacityLoopMetricsDirectory(metricsBaseDirectory, counter)); err != nil {
+						log.Warnf("Failed to read the quantiles of loop #%d: %v", counter, err)
+					}
+					loop := capacityLoop{counter: counter, vmCount: vmCount, storageClass: storageClassName, rc: loopRC}
+					if loopRC != 0 {
+						log.Errorf("Capacity failed in loop #%d", counter)
+						loops = append(loops, loop)
+						break
+					}
+					loop.stopReason = stopConditions.check(comparison, capacityLoopRun(counter))
+					loops = append(loops, loop)
+					if loop.stopReason != "" {
+						log.Infof("Stop condition met in loop #%d: %s", counter, loop.stopReason)
+						break
+					}
+					counter += 1
+					vmCount += vmsIncrement
+					if maxIterations > 0 && counter >= maxIterations {
+						log.Infof("Reached maxIterations [%d]", maxIterations)
+						break
+					}
 				}
+				log.Infof("Loop summary:\n%s", stopConditions.summary(loops, comparison))
+				return loopRC
+			}
+			if matrix.enabled() {
+				rc = matrix.run(wh, capacityAccessMode(skipMigrationJob), virtMeasurementFactoryMap, func(entry storageMatrixEntry) (int, string) {
+					AdditionalVars["accessMode"] = accessModeTranslator[entry.accessMode]
+					AdditionalVars["volumeMode"] = entry.volumeMode
+					metricsBaseDirectory := entry.metricsDirectory(virtParallelTestName)
+					return runLoops([]string{entry.storageClass}, metricsBaseDirectory), metricsBaseDirectory
+				})
+				return
 			}
+			wh.SetMeasurements(virtMeasurementFactoryMap)
+			rc = runLoops(storageClasses, virtParallelTestName)
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
 			os.Exit(rc)
@@ -198,6 +251,9 @@ func NewVirtParallel(wh *workloads.WorkloadHelper) *cobra.Command {
 	cmd.Flags().BoolVar(&skipRestartJob, "skip-restart-job", false, "Skip the VM restart job")
 	cmd.Flags().BoolVar(&skipSnapshotJob, "skip-snapshot-job", false, "Skip the VM snapshot job")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics-aggregated.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
+	addLoopStopConditionFlags(cmd, &stopConditions)
+	addStorageMatrixFlags(cmd, &matrix)
 	return cmd
 }
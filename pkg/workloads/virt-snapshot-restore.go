@@ -0,0 +1,128 @@
+// Copyright 2026 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workloads
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloud-bulldozer/go-commons/v2/ssh"
+	"github.com/cloud-bulldozer/go-commons/v2/virtctl"
+	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	virtSnapshotRestoreSSHKeyFileName = "ssh"
+	virtSnapshotRestoreTmpDirPattern  = "kube-burner-virt-snapshot-restore-*"
+	virtSnapshotRestoreTestName       = "virt-snapshot-restore"
+	restoreModeNew                    = "new"
+	restoreModeInPlace                = "in-place"
+)
+
+var (
+	virtSnapshotRestoreNamespaceLabelSelector = fmt.Sprintf("%s=%s", kubeBurnerTestNameLabelKey, virtSnapshotRestoreTestName)
+)
+
+// NewVirtSnapshotRestore holds the virt-snapshot-restore workload
+func NewVirtSnapshotRestore(wh *workloads.WorkloadHelper) *cobra.Command {
+	var storageClassName string
+	var sshKeyPairPath string
+	var vmImage, vmCPU, vmMemory string
+	var batches int
+	var vmsPerBatch int
+	var restoreMode string
+	var testNamespace string
+	var metricsProfiles []string
+	var volumeAccessMode string
+	var useVirtctl bool
+	var cleanup bool
+	var rc int
+	cmd := &cobra.Command{
+		Use:          virtSnapshotRestoreTestName,
+		Short:        "Runs virt-snapshot-restore workload",
+		SilenceUsage: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if cleanup {
+				return
+			}
+			if _, ok := accessModeTranslator[volumeAccessMode]; !ok {
+				log.Fatalf("Unsupported access mode - %s", volumeAccessMode)
+			}
+
+			if useVirtctl && !virtctl.IsInstalled() {
+				log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
+			}
+
+			if restoreMode != restoreModeNew && restoreMode != restoreModeInPlace {
+				log.Fatalf("Unsupported restore mode - %s", restoreMode)
+			}
+
+			// VirtualMachineSnapshots require a VolumeSnapshotClass for the provisioner of the StorageClass
+			storageClassName, _ = getStorageAndSnapshotClasses(storageClassName, true, true)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if cleanup {
+				log.Infof("Cleaning up all the resources from the previous run")
+				cleanupTestNamespaces(cmd.Context(), virtSnapshotRestoreNamespaceLabelSelector)
+				return
+			}
+			privateKeyPath, publicKeyPath, err := ssh.GenerateSSHKeyPair(sshKeyPairPath, virtSnapshotRestoreTmpDirPattern, virtSnapshotRestoreSSHKeyFileName)
+			if err != nil {
+				log.Fatalf("Failed to generate SSH keys for the test - %v", err)
+			}
+			wh.SummaryMetadata["OCPVirtualizationVersion"], err = wh.MetadataAgent.GetOCPVirtualizationVersion()
+			if err != nil {
+				log.Warnf("Failed to get OCP Virtualization version: %v", err)
+			}
+			setVMCheckVars(useVirtctl)
+			AdditionalVars["privateKey"] = privateKeyPath
+			AdditionalVars["publicKey"] = publicKeyPath
+			AdditionalVars["storageClassName"] = storageClassName
+			AdditionalVars["testNamespace"] = testNamespace
+			AdditionalVars["batches"] = batches
+			AdditionalVars["batchCounters"] = generateLoopCounterSlice(batches, 0)
+			AdditionalVars["vmsPerBatch"] = vmsPerBatch
+			AdditionalVars["restoreInPlace"] = restoreMode == restoreModeInPlace
+			AdditionalVars["accessMode"] = accessModeTranslator[volumeAccessMode]
+			AdditionalVars["VM_IMAGE"] = vmImage
+			AdditionalVars["VM_CPU"] = vmCPU
+			AdditionalVars["VM_MEMORY"] = vmMemory
+
+			setMetrics(cmd, metricsProfiles)
+			wh.SetMeasurements(virtMeasurementFactoryMap)
+			rc = RunWorkload(cmd, wh, cmd.Name()+".yml")
+		},
+		PostRun: func(cmd *cobra.Command, args []string) {
+			os.Exit(rc)
+		},
+	}
+	cmd.Flags().StringVar(&storageClassName, "storage-class", "", "Name of the Storage Class to test")
+	cmd.Flags().StringVar(&sshKeyPairPath, "ssh-key-path", "", "Path to save the generarated SSH keys")
+	cmd.Flags().IntVar(&batches, "batches", 2, "Number of batches of VMs snapshotted and restored one after the other. The total number of VMs is batches*batch-vms")
+	cmd.Flags().IntVar(&vmsPerBatch, "batch-vms", 10, "How many VMs to snapshot and restore simultaneously. The total number of VMs is batches*batch-vms")
+	cmd.Flags().StringVar(&restoreMode, "restore-mode", restoreModeNew, "Restore the snapshots to new VMs or in place, stopping the source VMs - new, in-place")
+	cmd.Flags().StringVarP(&testNamespace, "namespace", "n", virtSnapshotRestoreTestName, "Base name for the namespace to run the test in")
+	cmd.Flags().StringVar(&vmImage, "vm-image", "quay.io/containerdisks/fedora:41", "VM image to be deployed")
+	cmd.Flags().StringVar(&vmCPU, "vm-cpu", "1", "Number of CPU cores for the VM")
+	cmd.Flags().StringVar(&vmMemory, "vm-memory", "512Mi", "Amount of memory for the VM")
+	cmd.Flags().StringVar(&volumeAccessMode, "access-mode", "RWX", "Access mode for the created volumes - RO, RWO, RWX")
+	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
+	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
+	return cmd
+}
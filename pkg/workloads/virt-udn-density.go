@@ -19,6 +19,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/cloud-bulldozer/go-commons/v2/ssh"
+	"github.com/cloud-bulldozer/go-commons/v2/virtctl"
 	"github.com/kube-burner/kube-burner/v2/pkg/config"
 	"github.com/kube-burner/kube-burner/v2/pkg/workloads"
 	log "github.com/sirupsen/logrus"
@@ -39,6 +41,10 @@ func NewVirtUDNDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	var l3, pprof bool
 	var churnDelay, churnDuration time.Duration
 	var deletionStrategy, vmImage, bindingMethod, churnMode, vmCPU, vmMemory string
+	var migrationPercent, migrationQPS int
+	var probeInterval time.Duration
+	var sshKeyPairPath string
+	var useVirtctl bool
 	var cleanup bool
 	var rc int
 	cmd := &cobra.Command{
@@ -59,6 +65,25 @@ func NewVirtUDNDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 				fmt.Println("Invalid value for --binding-method. Allowed values are 'passt' or 'l2bridge'.")
 				os.Exit(1)
 			}
+			if migrationPercent < 0 || migrationPercent > 100 {
+				log.Fatal("--migration-percent must be between 0 and 100")
+			}
+			if migrationPercent > 0 {
+				// Only layer2 primary networks keep the IP address of the VMs across live migrations
+				if l3 {
+					log.Fatal("--migration-percent requires a layer2 network and can't be used with --layer3")
+				}
+				if useVirtctl && !virtctl.IsInstalled() {
+					log.Fatalf("Failed to run virtctl. Check that it is installed, in PATH and working")
+				}
+				privateKeyPath, publicKeyPath, err := ssh.GenerateSSHKeyPair(sshKeyPairPath, "kube-burner-"+variant+"-*", "ssh")
+				if err != nil {
+					log.Fatalf("Failed to generate SSH keys for the test - %v", err)
+				}
+				AdditionalVars["SSH_PRIVATE_KEY"] = privateKeyPath
+				AdditionalVars["SSH_PUBLIC_KEY"] = publicKeyPath
+				setVMCheckVars(useVirtctl)
+			}
 			setMetrics(cmd, metricsProfiles)
 
 			totalVMs := clusterMetadata.WorkerNodesCount * vmsPerNode
@@ -85,6 +110,9 @@ func NewVirtUDNDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 			AdditionalVars["ENABLE_LAYER_3"] = l3
 			AdditionalVars["PPROF"] = pprof
 			AdditionalVars["PPROF_INTERVAL"] = pprofInterval.String()
+			AdditionalVars["MIGRATION_PERCENT"] = migrationPercent
+			AdditionalVars["MIGRATION_QPS"] = migrationQPS
+			AdditionalVars["PROBE_INTERVAL"] = probeInterval
 			if l3 {
 				log.Info("Layer 3 is enabled")
 				AddVirtMetadata(wh, vmImage, "layer3", bindingMethod)
@@ -92,6 +120,7 @@ func NewVirtUDNDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 				log.Info("Layer 2 is enabled")
 				AddVirtMetadata(wh, vmImage, "layer2", bindingMethod)
 			}
+			wh.SetMeasurements(virtMeasurementFactoryMap)
 			rc = RunWorkload(cmd, wh, variant+".yml")
 		},
 		PostRun: func(cmd *cobra.Command, args []string) {
@@ -115,6 +144,11 @@ func NewVirtUDNDensity(wh *workloads.WorkloadHelper, variant string) *cobra.Comm
 	cmd.Flags().BoolVar(&pprof, "pprof", false, "Enable pprof collection")
 	cmd.Flags().DurationVar(&pprofInterval, "pprof-interval", 0, "Interval between pprof collections")
 	cmd.Flags().StringSliceVar(&metricsProfiles, "metrics-profile", []string{"metrics.yml"}, "Comma separated list of metrics profiles to use")
+	cmd.Flags().IntVar(&migrationPercent, "migration-percent", 0, "Percentage of the server VMs to live migrate once created, 0 disables the migration phase")
+	cmd.Flags().IntVar(&migrationQPS, "migration-qps", 20, "QPS of the migration requests")
+	cmd.Flags().DurationVar(&probeInterval, "probe-interval", 200*time.Millisecond, "Interval between the probes sent from the client VMs to the migrated server VM")
+	cmd.Flags().StringVar(&sshKeyPairPath, "ssh-key-path", "", "Path to save the generarated SSH keys")
+	cmd.Flags().BoolVar(&useVirtctl, "use-virtctl", false, "Connect to the guests through virtctl ssh instead of the built-in SSH client")
 	cmd.Flags().BoolVar(&cleanup, "cleanup", false, "Cleanup resources created by previous runs")
 	if variant == "virt-cudn-density" {
 		cmd.Annotations = map[string]string{"configDir": "virt-udn-density"}
@@ -134,6 +134,12 @@ teardown_file() {
   run_cmd ${KUBE_BURNER_OCP} crd-scale --iterations=2 --alerting=false
 }
 
+# bats test_tags=workload:crd-scale
+@test "crd-scale: custom resources and discovery latency" {
+  run_cmd ${KUBE_BURNER_OCP} crd-scale --iterations=3 --crs-per-crd=2 --schema-properties=4 --validation-rules=2 --discovery-interval=2s ${INDEXING_FLAGS}
+  check_metric_value jobSummary crdLatencyMeasurement crdLatencyQuantilesMeasurement crdDiscoveryMeasurement
+}
+
 # bats test_tags=workload:virt-density
 @test "virt-density" {
   run_cmd ${KUBE_BURNER_OCP} virt-density --vms-per-node=5 --vmi-ready-threshold=1m ${INDEXING_FLAGS}
@@ -250,6 +256,11 @@ teardown_file() {
   run_cmd ${KUBE_BURNER_OCP} kueue-operator-jobs-shared --job-replicas=10 --iterations=2 --parallelism=5 --workload-runtime=2s
 }
 
+# bats test_tags=workload:kueue-operator
+@test "kueue-operator: cohort" {
+  run_cmd ${KUBE_BURNER_OCP} kueue-operator-cohort --jobs-per-tenant=10 --pods-quota=2 --borrowing-limit=2 --workload-runtime=5s --sample-interval=2s
+}
+
 # bats test_tags=workload:virt-capacity-benchmark
 @test "virt-capacity-benchmark" {
   local STORAGE_PARAMETER
@@ -359,6 +370,37 @@ teardown_file() {
   done
 }
 
+# bats test_tags=workload:virt-hotplug
+@test "virt-hotplug" {
+  local STORAGE_PARAMETER
+  if [ -n "$KUBE_BURNER_OCP_STORAGE_CLASS" ]; then
+    STORAGE_PARAMETER="--storage-class ${KUBE_BURNER_OCP_STORAGE_CLASS}"
+  fi
+  run_cmd ${KUBE_BURNER_OCP} virt-hotplug ${STORAGE_PARAMETER} --access-mode RWO --vms 2 --waves 1 --hotplug-volumes 1
+  check_metric_recorded ./virt-hotplug-results create-vms vmiLatency vmReadyLatency
+  local jobs=("add-volume-wave-0" "remove-volume-wave-0" "hotplug-cpu-wave-0" "hotplug-memory-wave-0")
+  for job in "${jobs[@]}"; do
+    check_metric_recorded ./virt-hotplug-results ${job} vmHotplugLatency vmiStatusLatency
+    check_quantile_recorded ./virt-hotplug-results ${job} vmHotplugLatency VMIStatusLatency
+  done
+  check_destroyed_ns kube-burner.io/test-name=virt-hotplug
+}
+
+# bats test_tags=workload:virt-snapshot-restore
+@test "virt-snapshot-restore" {
+  local STORAGE_PARAMETER
+  if [ -n "$KUBE_BURNER_OCP_STORAGE_CLASS" ]; then
+    STORAGE_PARAMETER="--storage-class ${KUBE_BURNER_OCP_STORAGE_CLASS}"
+  fi
+  run_cmd ${KUBE_BURNER_OCP} virt-snapshot-restore ${STORAGE_PARAMETER} --access-mode RWO --batches 1 --batch-vms 2
+  check_metric_recorded ./virt-snapshot-restore-results create-vms vmiLatency vmReadyLatency
+  check_metric_recorded ./virt-snapshot-restore-results snapshot-vms-0 vmSnapshotRestoreLatency readyLatency
+  check_quantile_recorded ./virt-snapshot-restore-results snapshot-vms-0 vmSnapshotRestoreLatency SnapshotReadyLatency
+  check_metric_recorded ./virt-snapshot-restore-results restore-vms-0 vmSnapshotRestoreLatency readyLatency
+  check_quantile_recorded ./virt-snapshot-restore-results restore-vms-0 vmSnapshotRestoreLatency RestoreCompleteLatency
+  check_destroyed_ns kube-burner.io/test-name=virt-snapshot-restore
+}
+
 # bats test_tags=workload:crd-scale
 @test "extract and customize crd-scale" {
   run_cmd ${KUBE_BURNER_OCP} crd-scale --extract